@@ -94,6 +94,11 @@ func (s *AccountService) AddBankAccount(ctx context.Context, req *models.AddBank
 	if req.BankCode == "" {
 		return nil, fmt.Errorf("bank code is required")
 	}
+	if s.client.Banks != nil {
+		if err := s.client.Banks.ValidateBankAccount(ctx, req); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	}
 
 	// Create multipart form data
 	body, contentType, err := s.createBankAccountMultipartForm(req)
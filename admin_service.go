@@ -0,0 +1,321 @@
+package idrx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/widnyana/idrx-go/blockchain"
+)
+
+// AuditStage is the point an admin action had reached when it was recorded
+type AuditStage string
+
+// Audit stages, in the order an action passes through them
+const (
+	AuditRejected  AuditStage = "rejected"  // Invalid arguments, or the wallet lacks the required role; nothing was sent
+	AuditSubmitted AuditStage = "submitted" // The transaction was broadcast
+	AuditConfirmed AuditStage = "confirmed" // The transaction succeeded with the configured confirmations
+	AuditPending   AuditStage = "pending"   // The wait for confirmations ended first; the transaction may still be mined
+	AuditFailed    AuditStage = "failed"    // Sending failed or the transaction reverted
+)
+
+// ErrAdminPending is returned when an admin transaction was sent but the wait for its
+// confirmations ended first. The transaction may still be mined; check its hash before retrying.
+var ErrAdminPending = errors.New("admin transaction not confirmed yet")
+
+// AuditEvent records one stage of an admin action
+type AuditEvent struct {
+	Time        time.Time
+	Action      string // Contract method, e.g. "pause" or "grantRole"
+	ChainID     uint64
+	Operator    string            // Wallet performing the action
+	Role        blockchain.Role   // Role required for the action
+	Params      map[string]string // Action arguments, e.g. "account"
+	Stage       AuditStage
+	TxHash      string // Set from AuditSubmitted onwards
+	BlockNumber uint64 // Set for AuditConfirmed and reverted transactions
+	Err         error  // Set for AuditRejected, AuditPending and AuditFailed
+}
+
+// AuditHook receives every stage of every admin action. It is called synchronously,
+// so a slow hook delays the action; it must not retain ctx beyond the call.
+type AuditHook func(ctx context.Context, event AuditEvent)
+
+// AdminConfig configures the admin service
+type AdminConfig struct {
+	AuditHook     AuditHook // Optional
+	Confirmations uint64    // Confirmations to wait for (defaults to 1, the including block)
+}
+
+// AdminService performs privileged IDRX contract operations. Each action first checks that
+// the wallet holds the role the contract requires, then waits for the transaction to be
+// confirmed, recording every stage through the audit hook.
+type AdminService struct {
+	client *blockchain.Client
+	config AdminConfig
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(client *blockchain.Client, config AdminConfig) *AdminService {
+	if config.Confirmations == 0 {
+		config.Confirmations = 1
+	}
+	return &AdminService{
+		client: client,
+		config: config,
+	}
+}
+
+// AdminResult represents the result of a confirmed admin action
+type AdminResult struct {
+	Action      string `json:"action"`
+	TxHash      string `json:"txHash"`
+	ChainID     uint64 `json:"chainId"`
+	BlockNumber uint64 `json:"blockNumber"`
+	Status      string `json:"status"`
+}
+
+// Pause stops transfers, mints and burns (requires PAUSER_ROLE)
+func (as *AdminService) Pause(ctx context.Context, chainID uint64) (*AdminResult, error) {
+	return as.execute(ctx, chainID, "pause", blockchain.PauserRole, nil, func() (*types.Transaction, error) {
+		return as.client.Pause(ctx, chainID)
+	})
+}
+
+// Unpause resumes a paused contract (requires PAUSER_ROLE)
+func (as *AdminService) Unpause(ctx context.Context, chainID uint64) (*AdminResult, error) {
+	return as.execute(ctx, chainID, "unpause", blockchain.PauserRole, nil, func() (*types.Transaction, error) {
+		return as.client.Unpause(ctx, chainID)
+	})
+}
+
+// Blacklist blacklists an address (requires BLACKLIST_ROLE)
+func (as *AdminService) Blacklist(ctx context.Context, chainID uint64, address string) (*AdminResult, error) {
+	account, err := parseAddress(address)
+	if err != nil {
+		return nil, as.reject(ctx, chainID, "addBlackList", blockchain.BlacklistRole, map[string]string{"account": address}, err)
+	}
+
+	params := map[string]string{"account": account.Hex()}
+	return as.execute(ctx, chainID, "addBlackList", blockchain.BlacklistRole, params, func() (*types.Transaction, error) {
+		return as.client.AddBlackList(ctx, chainID, account)
+	})
+}
+
+// RemoveFromBlacklist removes an address from the blacklist (requires BLACKLIST_ROLE)
+func (as *AdminService) RemoveFromBlacklist(ctx context.Context, chainID uint64, address string) (*AdminResult, error) {
+	account, err := parseAddress(address)
+	if err != nil {
+		return nil, as.reject(ctx, chainID, "removeBlackList", blockchain.BlacklistRole, map[string]string{"account": address}, err)
+	}
+
+	params := map[string]string{"account": account.Hex()}
+	return as.execute(ctx, chainID, "removeBlackList", blockchain.BlacklistRole, params, func() (*types.Transaction, error) {
+		return as.client.RemoveBlackList(ctx, chainID, account)
+	})
+}
+
+// DestroyBlackFunds burns the whole balance of a blacklisted address (requires BLACKLIST_ROLE)
+func (as *AdminService) DestroyBlackFunds(ctx context.Context, chainID uint64, address string) (*AdminResult, error) {
+	account, err := parseAddress(address)
+	if err != nil {
+		return nil, as.reject(ctx, chainID, "destroyBlackFunds", blockchain.BlacklistRole, map[string]string{"account": address}, err)
+	}
+
+	params := map[string]string{"account": account.Hex()}
+	return as.execute(ctx, chainID, "destroyBlackFunds", blockchain.BlacklistRole, params, func() (*types.Transaction, error) {
+		return as.client.DestroyBlackFunds(ctx, chainID, account)
+	})
+}
+
+// SetPlatformFeeInfo sets the platform fee recipient and the bridge fees in basis points
+// (requires PLATFORM_FEE_SETTER_ROLE)
+func (as *AdminService) SetPlatformFeeInfo(
+	ctx context.Context,
+	chainID uint64,
+	recipient string,
+	burnBridgeFee uint64,
+	mintBridgeFee uint64,
+) (*AdminResult, error) {
+	recipientAddr, err := parseAddress(recipient)
+	if err != nil {
+		return nil, as.reject(ctx, chainID, "setPlatformFeeInfo", blockchain.PlatformFeeSetterRole, map[string]string{"recipient": recipient}, err)
+	}
+
+	info := &blockchain.PlatformFeeInfo{
+		Recipient:     recipientAddr,
+		BurnBridgeFee: burnBridgeFee,
+		MintBridgeFee: mintBridgeFee,
+	}
+	params := map[string]string{
+		"recipient":     recipientAddr.Hex(),
+		"burnBridgeFee": strconv.FormatUint(burnBridgeFee, 10),
+		"mintBridgeFee": strconv.FormatUint(mintBridgeFee, 10),
+	}
+	return as.execute(ctx, chainID, "setPlatformFeeInfo", blockchain.PlatformFeeSetterRole, params, func() (*types.Transaction, error) {
+		return as.client.SetPlatformFeeInfo(ctx, chainID, info)
+	})
+}
+
+// GrantRole grants a role to an address (requires the role's admin role)
+func (as *AdminService) GrantRole(ctx context.Context, chainID uint64, role blockchain.Role, address string) (*AdminResult, error) {
+	params := map[string]string{"role": string(role), "account": address}
+	account, err := parseAddress(address)
+	if err != nil {
+		return nil, as.reject(ctx, chainID, "grantRole", "", params, err)
+	}
+	params["account"] = account.Hex()
+
+	// The required role is not known until the lookup succeeds
+	adminRole, err := as.client.RoleAdmin(ctx, chainID, role)
+	if err != nil {
+		return nil, as.reject(ctx, chainID, "grantRole", "", params, err)
+	}
+
+	return as.execute(ctx, chainID, "grantRole", adminRole, params, func() (*types.Transaction, error) {
+		return as.client.GrantRole(ctx, chainID, role, account)
+	})
+}
+
+// RevokeRole revokes a role from an address (requires the role's admin role)
+func (as *AdminService) RevokeRole(ctx context.Context, chainID uint64, role blockchain.Role, address string) (*AdminResult, error) {
+	params := map[string]string{"role": string(role), "account": address}
+	account, err := parseAddress(address)
+	if err != nil {
+		return nil, as.reject(ctx, chainID, "revokeRole", "", params, err)
+	}
+	params["account"] = account.Hex()
+
+	// The required role is not known until the lookup succeeds
+	adminRole, err := as.client.RoleAdmin(ctx, chainID, role)
+	if err != nil {
+		return nil, as.reject(ctx, chainID, "revokeRole", "", params, err)
+	}
+
+	return as.execute(ctx, chainID, "revokeRole", adminRole, params, func() (*types.Transaction, error) {
+		return as.client.RevokeRole(ctx, chainID, role, account)
+	})
+}
+
+// RenounceRole gives up a role held by the wallet
+func (as *AdminService) RenounceRole(ctx context.Context, chainID uint64, role blockchain.Role) (*AdminResult, error) {
+	params := map[string]string{"role": string(role)}
+	return as.execute(ctx, chainID, "renounceRole", role, params, func() (*types.Transaction, error) {
+		return as.client.RenounceRole(ctx, chainID, role)
+	})
+}
+
+// execute checks the wallet holds role, sends the transaction and waits for it to be confirmed,
+// recording each stage. A result is returned alongside the error once a transaction was sent;
+// the error wraps ErrAdminPending when the outcome is not known yet.
+func (as *AdminService) execute(
+	ctx context.Context,
+	chainID uint64,
+	action string,
+	role blockchain.Role,
+	params map[string]string,
+	send func() (*types.Transaction, error),
+) (*AdminResult, error) {
+	operator := as.client.GetAddress()
+	event := AuditEvent{
+		Action:   action,
+		ChainID:  chainID,
+		Operator: operator.Hex(),
+		Role:     role,
+		Params:   params,
+	}
+
+	hasRole, err := as.client.HasRole(ctx, chainID, role, operator)
+	if err == nil && !hasRole {
+		err = fmt.Errorf("%w: %s does not hold %s on chain %d", blockchain.ErrMissingRole, operator.Hex(), role, chainID)
+	}
+	if err != nil {
+		as.record(ctx, event, AuditRejected, err)
+		return nil, err
+	}
+
+	tx, err := send()
+	if err != nil {
+		as.record(ctx, event, AuditFailed, err)
+		return nil, err
+	}
+
+	event.TxHash = tx.Hash().Hex()
+	as.record(ctx, event, AuditSubmitted, nil)
+
+	result := &AdminResult{
+		Action:  action,
+		TxHash:  event.TxHash,
+		ChainID: chainID,
+		Status:  "pending",
+	}
+
+	receipt, err := as.client.WaitForConfirmations(ctx, chainID, tx.Hash(), as.config.Confirmations)
+	if err != nil {
+		as.record(ctx, event, AuditPending, err)
+		return result, fmt.Errorf("%w: %s transaction %s: %w", ErrAdminPending, action, event.TxHash, err)
+	}
+
+	event.BlockNumber = receipt.BlockNumber.Uint64()
+	result.BlockNumber = event.BlockNumber
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		result.Status = "failed"
+		err := fmt.Errorf("%s transaction %s reverted", action, event.TxHash)
+		as.record(ctx, event, AuditFailed, err)
+		return result, err
+	}
+
+	result.Status = "confirmed"
+	as.record(ctx, event, AuditConfirmed, nil)
+	return result, nil
+}
+
+// reject records an action refused before execute could check the role, and returns err
+func (as *AdminService) reject(
+	ctx context.Context,
+	chainID uint64,
+	action string,
+	role blockchain.Role,
+	params map[string]string,
+	err error,
+) error {
+	as.record(ctx, AuditEvent{
+		Action:   action,
+		ChainID:  chainID,
+		Operator: as.client.GetAddress().Hex(),
+		Role:     role,
+		Params:   params,
+	}, AuditRejected, err)
+	return err
+}
+
+// record passes a stage of an action to the audit hook, if any
+func (as *AdminService) record(ctx context.Context, event AuditEvent, stage AuditStage, err error) {
+	if as.config.AuditHook == nil {
+		return
+	}
+	event.Time = time.Now()
+	event.Stage = stage
+	event.Err = err
+	as.config.AuditHook(ctx, event)
+}
+
+// HasRole checks if an address holds a role on the specified chain
+func (as *AdminService) HasRole(ctx context.Context, chainID uint64, role blockchain.Role, address string) (bool, error) {
+	account, err := parseAddress(address)
+	if err != nil {
+		return false, err
+	}
+	return as.client.HasRole(ctx, chainID, role, account)
+}
+
+// Operator returns the address of the wallet performing admin actions
+func (as *AdminService) Operator() common.Address {
+	return as.client.GetAddress()
+}
@@ -0,0 +1,224 @@
+package idrx
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/internal/backendtest"
+)
+
+// accessControlBackend emulates the role storage of the contract and mines every
+// transaction immediately in block 10
+type accessControlBackend struct {
+	backendtest.Chain
+
+	mu      sync.Mutex
+	holders map[blockchain.Role]map[common.Address]bool
+	sent    []string // Methods of the transactions sent
+	unmined bool     // Transactions are accepted but never mined
+}
+
+func (a *accessControlBackend) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (a *accessControlBackend) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return uint64(len(a.sent)), nil
+}
+
+func (a *accessControlBackend) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, args, err := backendtest.DecodeCall(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch method.Name {
+	case "hasRole":
+		role := roleByID(args[0].([32]byte))
+		return method.Outputs.Pack(a.holders[role][args[1].(common.Address)])
+	case "getRoleAdmin":
+		return method.Outputs.Pack(roleID(blockchain.DefaultAdminRole))
+	default:
+		// Role constant getters such as PAUSER_ROLE
+		return method.Outputs.Pack(roleID(blockchain.Role(method.Name)))
+	}
+}
+
+func (a *accessControlBackend) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	method, _, err := backendtest.DecodeCall(tx.Data())
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sent = append(a.sent, method.Name)
+	return nil
+}
+
+func (a *accessControlBackend) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	if a.unmined {
+		return nil, ethereum.NotFound
+	}
+	return &types.Receipt{
+		TxHash:      hash,
+		Status:      types.ReceiptStatusSuccessful,
+		BlockNumber: big.NewInt(10),
+		BlockHash:   common.Hash{0x10},
+	}, nil
+}
+
+// roleID follows the OpenZeppelin convention for role identifiers
+func roleID(role blockchain.Role) [32]byte {
+	if role == blockchain.DefaultAdminRole {
+		return [32]byte{}
+	}
+	return crypto.Keccak256Hash([]byte(role))
+}
+
+func roleByID(id [32]byte) blockchain.Role {
+	for _, role := range blockchain.Roles() {
+		if roleID(role) == id {
+			return role
+		}
+	}
+	return ""
+}
+
+// newAdminService builds an admin service whose wallet holds the given roles
+func newAdminService(t *testing.T, roles ...blockchain.Role) (*AdminService, *accessControlBackend, *[]AuditEvent) {
+	t.Helper()
+
+	backend := &accessControlBackend{
+		Chain:   backendtest.Chain{Head: 12},
+		holders: map[blockchain.Role]map[common.Address]bool{},
+	}
+	client := backendtest.NewSigningClient(t, backend, backendtest.FastBlocks)
+
+	for _, role := range roles {
+		backend.holders[role] = map[common.Address]bool{client.GetAddress(): true}
+	}
+
+	var events []AuditEvent
+	service := NewAdminService(client, AdminConfig{
+		Confirmations: 3,
+		AuditHook:     func(_ context.Context, event AuditEvent) { events = append(events, event) },
+	})
+	return service, backend, &events
+}
+
+func TestAdminPauseWithRole(t *testing.T) {
+	service, backend, events := newAdminService(t, blockchain.PauserRole)
+
+	result, err := service.Pause(context.Background(), backendtest.ChainID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != "confirmed" || result.BlockNumber != 10 {
+		t.Errorf("expected confirmed in block 10, got %s in block %d", result.Status, result.BlockNumber)
+	}
+	if len(backend.sent) != 1 || backend.sent[0] != "pause" {
+		t.Errorf("expected a single pause transaction, got %v", backend.sent)
+	}
+
+	if len(*events) != 2 {
+		t.Fatalf("expected submitted and confirmed audit events, got %d", len(*events))
+	}
+	if (*events)[0].Stage != AuditSubmitted || (*events)[1].Stage != AuditConfirmed {
+		t.Errorf("unexpected audit stages: %s, %s", (*events)[0].Stage, (*events)[1].Stage)
+	}
+	if (*events)[1].TxHash != result.TxHash || (*events)[1].Role != blockchain.PauserRole {
+		t.Errorf("unexpected audit event: %+v", (*events)[1])
+	}
+}
+
+func TestAdminRejectsWithoutRole(t *testing.T) {
+	service, backend, events := newAdminService(t, blockchain.PauserRole)
+
+	account := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	_, err := service.Blacklist(context.Background(), backendtest.ChainID, account.Hex())
+	if !errors.Is(err, blockchain.ErrMissingRole) {
+		t.Fatalf("expected ErrMissingRole, got: %v", err)
+	}
+	if len(backend.sent) != 0 {
+		t.Errorf("no transaction should be sent without the role, got %v", backend.sent)
+	}
+
+	if len(*events) != 1 || (*events)[0].Stage != AuditRejected {
+		t.Fatalf("expected a single rejected audit event, got %+v", *events)
+	}
+	if (*events)[0].Params["account"] != account.Hex() {
+		t.Errorf("expected account param %s, got %q", account.Hex(), (*events)[0].Params["account"])
+	}
+}
+
+func TestAdminGrantRoleRequiresAdminRole(t *testing.T) {
+	service, backend, _ := newAdminService(t, blockchain.MinterRole)
+
+	// Holding MINTER_ROLE does not allow granting it
+	_, err := service.GrantRole(context.Background(), backendtest.ChainID, blockchain.MinterRole, "0x00000000000000000000000000000000000000cc")
+	if !errors.Is(err, blockchain.ErrMissingRole) {
+		t.Fatalf("expected ErrMissingRole, got: %v", err)
+	}
+
+	backend.holders[blockchain.DefaultAdminRole] = map[common.Address]bool{service.Operator(): true}
+	if _, err := service.GrantRole(context.Background(), backendtest.ChainID, blockchain.MinterRole, "0x00000000000000000000000000000000000000cc"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(backend.sent) != 1 || backend.sent[0] != "grantRole" {
+		t.Errorf("expected a single grantRole transaction, got %v", backend.sent)
+	}
+}
+
+func TestAdminAuditsInvalidArguments(t *testing.T) {
+	service, backend, events := newAdminService(t, blockchain.DefaultAdminRole)
+
+	_, err := service.GrantRole(context.Background(), backendtest.ChainID, blockchain.MinterRole, "not-an-address")
+	if err == nil {
+		t.Fatal("expected an error for an invalid address")
+	}
+	if len(backend.sent) != 0 {
+		t.Errorf("no transaction should be sent, got %v", backend.sent)
+	}
+
+	if len(*events) != 1 || (*events)[0].Stage != AuditRejected || (*events)[0].Err == nil {
+		t.Fatalf("expected a single rejected audit event, got %+v", *events)
+	}
+	if (*events)[0].Action != "grantRole" || (*events)[0].Params["account"] != "not-an-address" {
+		t.Errorf("unexpected audit event: %+v", (*events)[0])
+	}
+}
+
+func TestAdminReportsUnconfirmedActionAsPending(t *testing.T) {
+	service, backend, events := newAdminService(t, blockchain.PauserRole)
+	backend.unmined = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	result, err := service.Pause(ctx, backendtest.ChainID)
+	if !errors.Is(err, ErrAdminPending) {
+		t.Fatalf("expected ErrAdminPending, got: %v", err)
+	}
+	if result == nil || result.Status != "pending" || result.TxHash == "" {
+		t.Errorf("expected a pending result with the transaction hash, got %+v", result)
+	}
+
+	if len(*events) != 2 || (*events)[1].Stage != AuditPending {
+		t.Fatalf("expected submitted and pending audit events, got %+v", *events)
+	}
+}
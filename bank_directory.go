@@ -0,0 +1,232 @@
+package idrx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/models"
+)
+
+// Errors returned by BankDirectory validation; they are wrapped with the offending values
+var (
+	ErrUnsupportedBank      = errors.New("bank is not supported")
+	ErrAmountAboveLimit     = errors.New("amount exceeds the bank's transfer limit")
+	ErrInvalidAccountNumber = errors.New("invalid bank account number")
+)
+
+// AccountNumberRule describes the account numbers a bank issues
+type AccountNumberRule struct {
+	MinLength int  // Shortest valid number, in characters
+	MaxLength int  // Longest valid number, in characters
+	Numeric   bool // Only digits are allowed
+}
+
+// DefaultAccountNumberRules cover the major Indonesian banks, keyed by both their short code and
+// their Bank Indonesia clearing code. Banks without a rule are only checked for a non-empty
+// number.
+var DefaultAccountNumberRules = map[string]AccountNumberRule{
+	"BCA":     {MinLength: 10, MaxLength: 10, Numeric: true},
+	"014":     {MinLength: 10, MaxLength: 10, Numeric: true},
+	"MANDIRI": {MinLength: 13, MaxLength: 13, Numeric: true},
+	"008":     {MinLength: 13, MaxLength: 13, Numeric: true},
+	"BRI":     {MinLength: 15, MaxLength: 15, Numeric: true},
+	"002":     {MinLength: 15, MaxLength: 15, Numeric: true},
+	"BNI":     {MinLength: 10, MaxLength: 10, Numeric: true},
+	"009":     {MinLength: 10, MaxLength: 10, Numeric: true},
+	"BSI":     {MinLength: 10, MaxLength: 10, Numeric: true},
+	"451":     {MinLength: 10, MaxLength: 10, Numeric: true},
+	"CIMB":    {MinLength: 12, MaxLength: 14, Numeric: true},
+	"022":     {MinLength: 12, MaxLength: 14, Numeric: true},
+	"PERMATA": {MinLength: 10, MaxLength: 10, Numeric: true},
+	"013":     {MinLength: 10, MaxLength: 10, Numeric: true},
+	"BTN":     {MinLength: 16, MaxLength: 16, Numeric: true},
+	"200":     {MinLength: 16, MaxLength: 16, Numeric: true},
+}
+
+// BankDirectoryConfig configures a BankDirectory
+type BankDirectoryConfig struct {
+	TTL                time.Duration                // How long the bank list is cached (defaults to 1 hour)
+	AccountNumberRules map[string]AccountNumberRule // Rules by bank code, added to or replacing DefaultAccountNumberRules
+}
+
+// BankDirectory caches the supported banks from GetMethods and validates bank details before
+// bank accounts are added or redemptions are requested. Bank codes are matched case-insensitively.
+type BankDirectory struct {
+	transactions *TransactionService
+	ttl          time.Duration
+	rules        map[string]AccountNumberRule
+	now          func() time.Time
+
+	mu      sync.Mutex
+	banks   map[string]models.Bank
+	fetched time.Time
+}
+
+// NewBankDirectory creates a bank directory. The bank list is loaded on first use.
+func NewBankDirectory(transactions *TransactionService, config BankDirectoryConfig) *BankDirectory {
+	ttl := config.TTL
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+
+	rules := make(map[string]AccountNumberRule, len(DefaultAccountNumberRules)+len(config.AccountNumberRules))
+	for code, rule := range DefaultAccountNumberRules {
+		rules[code] = rule
+	}
+	for code, rule := range config.AccountNumberRules {
+		rules[strings.ToUpper(code)] = rule
+	}
+
+	return &BankDirectory{
+		transactions: transactions,
+		ttl:          ttl,
+		rules:        rules,
+		now:          time.Now,
+	}
+}
+
+// Refresh reloads the bank list from GetMethods
+func (d *BankDirectory) Refresh(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.load(ctx)
+}
+
+// load fetches the bank list; the lock is held
+func (d *BankDirectory) load(ctx context.Context) error {
+	banks, err := d.transactions.GetMethods(ctx)
+	if err != nil {
+		return err
+	}
+
+	d.banks = make(map[string]models.Bank, len(banks))
+	for _, bank := range banks {
+		d.banks[strings.ToUpper(bank.BankCode)] = bank
+	}
+	d.fetched = d.now()
+	return nil
+}
+
+// directory returns the cached banks, loading them when missing or older than the TTL
+func (d *BankDirectory) directory(ctx context.Context) (map[string]models.Bank, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.banks == nil || d.now().Sub(d.fetched) >= d.ttl {
+		if err := d.load(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return d.banks, nil
+}
+
+// Banks returns the supported banks sorted by bank code
+func (d *BankDirectory) Banks(ctx context.Context) ([]models.Bank, error) {
+	banks, err := d.directory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.Bank, 0, len(banks))
+	for _, bank := range banks {
+		list = append(list, bank)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].BankCode < list[j].BankCode })
+	return list, nil
+}
+
+// Lookup returns the supported bank with the given code
+func (d *BankDirectory) Lookup(ctx context.Context, bankCode string) (*models.Bank, error) {
+	if strings.TrimSpace(bankCode) == "" {
+		return nil, fmt.Errorf("bankCode is required")
+	}
+
+	banks, err := d.directory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bank, exists := banks[strings.ToUpper(strings.TrimSpace(bankCode))]
+	if !exists {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedBank, bankCode)
+	}
+	return &bank, nil
+}
+
+// ValidateAccountNumber checks an account number against the bank's rule. Spaces and dashes
+// are ignored; the number is returned without them.
+func (d *BankDirectory) ValidateAccountNumber(bankCode, accountNumber string) (string, error) {
+	number := strings.NewReplacer(" ", "", "-", "").Replace(accountNumber)
+	if number == "" {
+		return "", fmt.Errorf("%w: account number is required", ErrInvalidAccountNumber)
+	}
+
+	rule, exists := d.rules[strings.ToUpper(strings.TrimSpace(bankCode))]
+	if !exists {
+		return number, nil
+	}
+	if rule.Numeric && strings.TrimLeft(number, "0123456789") != "" {
+		return "", fmt.Errorf("%w: %s account numbers contain only digits", ErrInvalidAccountNumber, bankCode)
+	}
+	if len(number) < rule.MinLength || (rule.MaxLength > 0 && len(number) > rule.MaxLength) {
+		if rule.MinLength == rule.MaxLength {
+			return "", fmt.Errorf("%w: %s account numbers have %d digits, got %d", ErrInvalidAccountNumber, bankCode, rule.MinLength, len(number))
+		}
+		return "", fmt.Errorf("%w: %s account numbers have %d to %d digits, got %d",
+			ErrInvalidAccountNumber, bankCode, rule.MinLength, rule.MaxLength, len(number))
+	}
+	return number, nil
+}
+
+// ValidateBankAccount checks that the bank is supported and the account number fits its rule.
+// The request's bank code and account number are normalised in place.
+func (d *BankDirectory) ValidateBankAccount(ctx context.Context, req *models.AddBankAccountRequest) error {
+	bank, err := d.Lookup(ctx, req.BankCode)
+	if err != nil {
+		return err
+	}
+	number, err := d.ValidateAccountNumber(bank.BankCode, req.BankAccountNumber)
+	if err != nil {
+		return err
+	}
+
+	req.BankCode = bank.BankCode
+	req.BankAccountNumber = number
+	return nil
+}
+
+// PrepareRedeem checks a redemption's bank details and amount against the supported banks and
+// fills in BankName when it is empty. The bank code and account number are normalised in place.
+func (d *BankDirectory) PrepareRedeem(ctx context.Context, req *models.RedeemRequest) error {
+	bank, err := d.Lookup(ctx, req.BankCode)
+	if err != nil {
+		return err
+	}
+	number, err := d.ValidateAccountNumber(bank.BankCode, req.BankAccount)
+	if err != nil {
+		return err
+	}
+
+	amount, err := decimal.NewFromString(req.AmountTransfer)
+	if err != nil || !amount.IsPositive() {
+		return fmt.Errorf("invalid amountTransfer %q", req.AmountTransfer)
+	}
+	// A missing or zero limit means the bank has none
+	if limit, err := decimal.NewFromString(bank.MaxAmountTransfer); err == nil && limit.IsPositive() && amount.GreaterThan(limit) {
+		return fmt.Errorf("%w: %s to %s, limit %s", ErrAmountAboveLimit, amount, bank.BankCode, limit)
+	}
+
+	req.BankCode = bank.BankCode
+	req.BankAccount = number
+	if req.BankName == "" {
+		req.BankName = bank.BankName
+	}
+	return nil
+}
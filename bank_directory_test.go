@@ -0,0 +1,145 @@
+package idrx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/widnyana/idrx-go/models"
+)
+
+// newBankTestServer serves two banks and records method lookups and redeem requests
+func newBankTestServer(t *testing.T, methodCalls *int, redeemed *models.RedeemRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/api/transaction/method"):
+			*methodCalls++
+			io.WriteString(w, `{"status": "ok", "data": [
+				{"bankCode": "BCA", "bankName": "Bank Central Asia", "maxAmountTransfer": "100000000"},
+				{"bankCode": "JAGO", "bankName": "Bank Jago", "maxAmountTransfer": ""}
+			]}`)
+		case strings.Contains(r.URL.Path, "/api/transaction/redeem-request"):
+			if err := json.NewDecoder(r.Body).Decode(redeemed); err != nil {
+				t.Errorf("invalid redeem body: %v", err)
+			}
+			io.WriteString(w, `{"status": "ok", "data": {"transactionId": "redeem1", "status": "PENDING"}}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newBankTestClient(server *httptest.Server) *Client {
+	return NewClient(
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithUserAuth("user-key", testSecretKey()),
+		WithBankValidation(BankDirectoryConfig{TTL: time.Minute}),
+	)
+}
+
+func TestRedeemRequestFillsBankName(t *testing.T) {
+	var methodCalls int
+	var redeemed models.RedeemRequest
+	client := newBankTestClient(newBankTestServer(t, &methodCalls, &redeemed))
+
+	req := &models.RedeemRequest{
+		TxHash:          "0xabc123",
+		NetworkChainID:  "137",
+		AmountTransfer:  "500000",
+		BankAccount:     "123-456 7890",
+		BankCode:        "bca",
+		BankAccountName: "John Doe",
+		WalletAddress:   "0x456",
+		Notes:           "Test redemption",
+	}
+	if _, err := client.Transaction.RedeemRequest(context.Background(), req); err != nil {
+		t.Fatalf("RedeemRequest: %v", err)
+	}
+	if redeemed.BankName != "Bank Central Asia" || redeemed.BankCode != "BCA" || redeemed.BankAccount != "1234567890" {
+		t.Errorf("expected normalised bank details to be sent, got %+v", redeemed)
+	}
+}
+
+func TestRedeemRequestRejectedBeforeSending(t *testing.T) {
+	var methodCalls int
+	var redeemed models.RedeemRequest
+	client := newBankTestClient(newBankTestServer(t, &methodCalls, &redeemed))
+
+	valid := models.RedeemRequest{
+		TxHash:          "0xabc123",
+		NetworkChainID:  "137",
+		AmountTransfer:  "500000",
+		BankAccount:     "1234567890",
+		BankCode:        "BCA",
+		BankAccountName: "John Doe",
+		WalletAddress:   "0x456",
+		Notes:           "Test redemption",
+	}
+	tests := []struct {
+		name   string
+		change func(*models.RedeemRequest)
+		want   error
+	}{
+		{"unsupported bank", func(r *models.RedeemRequest) { r.BankCode = "XYZ" }, ErrUnsupportedBank},
+		{"above limit", func(r *models.RedeemRequest) { r.AmountTransfer = "100000000.01" }, ErrAmountAboveLimit},
+		{"short account number", func(r *models.RedeemRequest) { r.BankAccount = "123456789" }, ErrInvalidAccountNumber},
+		{"letters in account number", func(r *models.RedeemRequest) { r.BankAccount = "12345678AB" }, ErrInvalidAccountNumber},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.change(&req)
+			if _, err := client.Transaction.RedeemRequest(context.Background(), &req); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if redeemed.TxHash != "" {
+		t.Error("expected no redeem request to be sent")
+	}
+	if methodCalls != 1 {
+		t.Errorf("expected the bank list to be fetched once, got %d", methodCalls)
+	}
+}
+
+func TestBankDirectoryCaching(t *testing.T) {
+	var methodCalls int
+	var redeemed models.RedeemRequest
+	client := newBankTestClient(newBankTestServer(t, &methodCalls, &redeemed))
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.Banks.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	banks, err := client.Banks.Banks(ctx)
+	if err != nil {
+		t.Fatalf("Banks: %v", err)
+	}
+	if len(banks) != 2 || banks[0].BankCode != "BCA" || banks[1].BankCode != "JAGO" {
+		t.Errorf("unexpected banks: %+v", banks)
+	}
+
+	// Banks without a rule or a limit only need a number
+	if _, err := client.Banks.ValidateAccountNumber("JAGO", "1"); err != nil {
+		t.Errorf("expected any number for a bank without a rule, got %v", err)
+	}
+	if _, err := client.Banks.Lookup(ctx, "jago"); err != nil || methodCalls != 1 {
+		t.Errorf("expected a cached lookup, got %v after %d calls", err, methodCalls)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := client.Banks.Lookup(ctx, "BCA"); err != nil || methodCalls != 2 {
+		t.Errorf("expected the list to be reloaded after the TTL, got %v after %d calls", err, methodCalls)
+	}
+}
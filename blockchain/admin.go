@@ -0,0 +1,241 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Pause stops transfers, mints and burns on the specified chain (requires PAUSER_ROLE)
+func (c *Client) Pause(ctx context.Context, chainID uint64) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "pause"); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.Pause(transactor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pause contract: %w", asRevertError(err))
+	}
+
+	return tx, nil
+}
+
+// Unpause resumes a paused contract on the specified chain (requires PAUSER_ROLE)
+func (c *Client) Unpause(ctx context.Context, chainID uint64) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "unpause"); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.Unpause(transactor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpause contract: %w", asRevertError(err))
+	}
+
+	return tx, nil
+}
+
+// AddBlackList blacklists an address so it can no longer send or receive IDRX (requires BLACKLIST_ROLE)
+func (c *Client) AddBlackList(ctx context.Context, chainID uint64, account common.Address) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "addBlackList", account); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.AddBlackList(transactor, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to blacklist %s: %w", account.Hex(), asRevertError(err))
+	}
+
+	return tx, nil
+}
+
+// RemoveBlackList removes an address from the blacklist (requires BLACKLIST_ROLE)
+func (c *Client) RemoveBlackList(ctx context.Context, chainID uint64, account common.Address) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "removeBlackList", account); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.RemoveBlackList(transactor, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove %s from blacklist: %w", account.Hex(), asRevertError(err))
+	}
+
+	return tx, nil
+}
+
+// DestroyBlackFunds burns the whole balance of a blacklisted address (requires BLACKLIST_ROLE)
+func (c *Client) DestroyBlackFunds(ctx context.Context, chainID uint64, account common.Address) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "destroyBlackFunds", account); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.DestroyBlackFunds(transactor, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to destroy funds of %s: %w", account.Hex(), asRevertError(err))
+	}
+
+	return tx, nil
+}
+
+// SetPlatformFeeInfo sets the platform fee recipient and bridge fees in basis points
+// (requires PLATFORM_FEE_SETTER_ROLE)
+func (c *Client) SetPlatformFeeInfo(ctx context.Context, chainID uint64, info *PlatformFeeInfo) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "setPlatformFeeInfo", info.Recipient, info.BurnBridgeFee, info.MintBridgeFee); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.SetPlatformFeeInfo(transactor, info.Recipient, info.BurnBridgeFee, info.MintBridgeFee)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set platform fee info: %w", asRevertError(err))
+	}
+
+	return tx, nil
+}
+
+// GrantRole grants a role to an account (requires the role's admin role)
+func (c *Client) GrantRole(ctx context.Context, chainID uint64, role Role, account common.Address) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := c.RoleID(ctx, chainID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "grantRole", id, account); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.GrantRole(transactor, id, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant %s to %s: %w", role, account.Hex(), asRevertError(err))
+	}
+
+	return tx, nil
+}
+
+// RevokeRole revokes a role from an account (requires the role's admin role)
+func (c *Client) RevokeRole(ctx context.Context, chainID uint64, role Role, account common.Address) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := c.RoleID(ctx, chainID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "revokeRole", id, account); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.RevokeRole(transactor, id, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke %s from %s: %w", role, account.Hex(), asRevertError(err))
+	}
+
+	return tx, nil
+}
+
+// RenounceRole gives up a role held by the client's address
+func (c *Client) RenounceRole(ctx context.Context, chainID uint64, role Role) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := c.RoleID(ctx, chainID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "renounceRole", id, c.address); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.RenounceRole(transactor, id, c.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to renounce %s: %w", role, asRevertError(err))
+	}
+
+	return tx, nil
+}
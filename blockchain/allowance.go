@@ -0,0 +1,206 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Allowance returns how many tokens a spender may still transfer on behalf of an owner
+func (c *Client) Allowance(ctx context.Context, chainID uint64, owner, spender common.Address) (*TokenAmount, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowance, err := contract.Allowance(callOpts(ctx, nil), owner, spender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allowance: %w", err)
+	}
+
+	return FromWei(allowance, int32(c.Decimals(chainID))), nil
+}
+
+// Approve sets the allowance of a spender over the client's tokens. Changing a non-zero
+// allowance to another non-zero value lets the spender front-run the change and spend both;
+// use SetExactAllowance for that.
+func (c *Client) Approve(ctx context.Context, chainID uint64, spender common.Address, amount *TokenAmount) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "approve", spender, amount.ToWei()); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.Approve(transactor, spender, amount.ToWei())
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve spender: %w", asRevertError(err))
+	}
+
+	return tx, nil
+}
+
+// IncreaseAllowance raises the allowance of a spender over the client's tokens
+func (c *Client) IncreaseAllowance(ctx context.Context, chainID uint64, spender common.Address, amount *TokenAmount) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "increaseAllowance", spender, amount.ToWei()); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.IncreaseAllowance(transactor, spender, amount.ToWei())
+	if err != nil {
+		return nil, fmt.Errorf("failed to increase allowance: %w", asRevertError(err))
+	}
+
+	return tx, nil
+}
+
+// DecreaseAllowance lowers the allowance of a spender over the client's tokens.
+// The contract reverts if the allowance would drop below zero.
+func (c *Client) DecreaseAllowance(ctx context.Context, chainID uint64, spender common.Address, amount *TokenAmount) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "decreaseAllowance", spender, amount.ToWei()); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.DecreaseAllowance(transactor, spender, amount.ToWei())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrease allowance: %w", asRevertError(err))
+	}
+
+	return tx, nil
+}
+
+// TransferFrom moves tokens from an owner to another address, spending the allowance
+// the owner granted to the client's address
+func (c *Client) TransferFrom(
+	ctx context.Context,
+	chainID uint64,
+	from common.Address,
+	to common.Address,
+	amount *TokenAmount,
+) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "transferFrom", from, to, amount.ToWei()); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.TransferFrom(transactor, from, to, amount.ToWei())
+	if err != nil {
+		return nil, fmt.Errorf("failed to transfer tokens from %s: %w", from.Hex(), asRevertError(err))
+	}
+
+	return tx, nil
+}
+
+// BurnFrom burns tokens from an account, spending the allowance it granted to the client's address
+func (c *Client) BurnFrom(ctx context.Context, chainID uint64, account common.Address, amount *TokenAmount) (*types.Transaction, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.preflight(ctx, chainID, "burnFrom", account, amount.ToWei()); err != nil {
+		return nil, err
+	}
+
+	transactor, err := c.CreateTransactor(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := contract.BurnFrom(transactor, account, amount.ToWei())
+	if err != nil {
+		return nil, fmt.Errorf("failed to burn tokens from %s: %w", account.Hex(), asRevertError(err))
+	}
+
+	return tx, nil
+}
+
+// ErrAllowanceReset is returned by SetExactAllowance when the reset to zero was sent but not
+// confirmed; the new amount has not been approved
+var ErrAllowanceReset = errors.New("allowance reset not confirmed")
+
+// SetExactAllowance sets the allowance of a spender to exactly amount without exposing the
+// approve race: when both the current and the new allowance are non-zero, it first resets
+// the allowance to zero and waits for that to be mined before approving the new amount.
+// It returns the transactions sent, which is none when the allowance already matches.
+// An error without ErrAllowanceReset after a reset means the reset was confirmed and only
+// the final approval failed.
+func (c *Client) SetExactAllowance(
+	ctx context.Context,
+	chainID uint64,
+	spender common.Address,
+	amount *TokenAmount,
+) ([]*types.Transaction, error) {
+	current, err := c.Allowance(ctx, chainID, c.address, spender)
+	if err != nil {
+		return nil, err
+	}
+
+	target := amount.ToWei()
+	if current.ToWei().Cmp(target) == 0 {
+		return nil, nil
+	}
+
+	var sent []*types.Transaction
+	if current.ToWei().Sign() > 0 && target.Sign() > 0 {
+		reset, err := c.Approve(ctx, chainID, spender, FromWei(common.Big0, int32(c.Decimals(chainID))))
+		if err != nil {
+			return nil, fmt.Errorf("failed to reset allowance: %w", err)
+		}
+		sent = append(sent, reset)
+
+		receipt, err := c.WaitForTransaction(ctx, chainID, reset.Hash())
+		if err != nil {
+			return sent, fmt.Errorf("%w: %w", ErrAllowanceReset, err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return sent, fmt.Errorf("%w: %s reverted", ErrAllowanceReset, reset.Hash().Hex())
+		}
+	}
+
+	tx, err := c.Approve(ctx, chainID, spender, amount)
+	if err != nil {
+		return sent, err
+	}
+
+	return append(sent, tx), nil
+}
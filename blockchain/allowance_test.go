@@ -0,0 +1,132 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+// allowanceBackend emulates the allowance storage of the contract and mines every
+// transaction immediately
+type allowanceBackend struct {
+	headerBackend
+
+	mu        sync.Mutex
+	allowance *big.Int
+	approvals []*big.Int // Amounts of the approve transactions in send order
+	revert    bool       // Every transaction reverts when set
+}
+
+func (a *allowanceBackend) CallContract(_ context.Context, _ ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return common.LeftPadBytes(a.allowance.Bytes(), 32), nil
+}
+
+func (a *allowanceBackend) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return uint64(len(a.approvals)), nil
+}
+
+func (a *allowanceBackend) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (a *allowanceBackend) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	args, err := parsed.Methods["approve"].Inputs.Unpack(tx.Data()[4:])
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.allowance = args[1].(*big.Int)
+	a.approvals = append(a.approvals, a.allowance)
+	return nil
+}
+
+func (a *allowanceBackend) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	if a.revert {
+		return &types.Receipt{TxHash: hash, Status: types.ReceiptStatusFailed}, nil
+	}
+	return &types.Receipt{TxHash: hash, Status: types.ReceiptStatusSuccessful}, nil
+}
+
+func TestSetExactAllowance(t *testing.T) {
+	spender := common.HexToAddress("0x00000000000000000000000000000000000000dd")
+
+	tests := []struct {
+		name      string
+		current   int64
+		target    string
+		approvals []int64
+	}{
+		{"unchanged", 500, "5", nil},
+		{"from zero", 0, "3", []int64{300}},
+		{"to zero", 500, "0", []int64{0}},
+		{"non-zero change resets first", 500, "3", []int64{0, 300}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &allowanceBackend{headerBackend: headerBackend{head: 1}, allowance: big.NewInt(tt.current)}
+			client := newHeaderClient(t, backend)
+
+			amount := &TokenAmount{Amount: decimal.RequireFromString(tt.target), Decimals: 2}
+			txs, err := client.SetExactAllowance(context.Background(), 31337, spender, amount)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(txs) != len(tt.approvals) || len(backend.approvals) != len(tt.approvals) {
+				t.Fatalf("expected %d approvals, sent %d", len(tt.approvals), len(backend.approvals))
+			}
+			for i, expected := range tt.approvals {
+				if backend.approvals[i].Int64() != expected {
+					t.Errorf("approval %d: expected %d, got %s", i, expected, backend.approvals[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSetExactAllowanceStopsOnRevertedReset(t *testing.T) {
+	backend := &allowanceBackend{headerBackend: headerBackend{head: 1}, allowance: big.NewInt(500), revert: true}
+	client := newHeaderClient(t, backend)
+
+	amount := &TokenAmount{Amount: decimal.RequireFromString("3"), Decimals: 2}
+	txs, err := client.SetExactAllowance(context.Background(), 31337, common.HexToAddress("0x00000000000000000000000000000000000000dd"), amount)
+	if !errors.Is(err, ErrAllowanceReset) {
+		t.Fatalf("expected ErrAllowanceReset, got %v", err)
+	}
+	if len(txs) != 1 || len(backend.approvals) != 1 {
+		t.Errorf("expected only the reset to be sent, sent %d", len(backend.approvals))
+	}
+}
+
+func TestAllowanceAppliesDecimals(t *testing.T) {
+	backend := &allowanceBackend{headerBackend: headerBackend{head: 1}, allowance: big.NewInt(12345)}
+	client := newHeaderClient(t, backend)
+
+	allowance, err := client.Allowance(context.Background(), 31337, client.GetAddress(), common.HexToAddress("0x00000000000000000000000000000000000000dd"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !allowance.Amount.Equal(decimal.RequireFromString("123.45")) {
+		t.Errorf("expected allowance 123.45, got %s", allowance.Amount)
+	}
+}
@@ -0,0 +1,552 @@
+// Package batch sends many IDRX transfers from one wallet, such as payroll payouts. Transfers
+// are validated up front, signed with sequential nonces, confirmed with bounded concurrency,
+// and their progress is saved after every step so an interrupted batch can be resumed.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+// Errors returned by Run
+var (
+	ErrStateMismatch = errors.New("saved batch state does not match the transfers")
+	ErrUnconfirmed   = errors.New("transfers are submitted but not confirmed yet; run the batch again to resume")
+)
+
+// Transfer is one payment in a batch
+type Transfer struct {
+	ID     string // Caller reference such as a payroll line (defaults to the position in the batch)
+	To     common.Address
+	Amount *blockchain.TokenAmount
+}
+
+// Config controls batch execution
+type Config struct {
+	Store         StateStore    // Progress persistence (defaults to an in-memory store)
+	Concurrency   int           // Transactions awaiting confirmation at once (defaults to 8)
+	Confirmations uint64        // Confirmations required per transfer (defaults to 1)
+	WaitTimeout   time.Duration // How long to wait for each transfer before leaving it submitted (defaults to the client's wait)
+	OnProgress    func(Item)    // Optional; called after every status change
+}
+
+// Report is the outcome of every transfer in a batch
+type Report struct {
+	ChainID   uint64
+	From      common.Address
+	Items     []Item // In batch order
+	Confirmed int
+	Failed    int
+	Invalid   int
+	Pending   int // Not finished when the batch stopped, including submitted transfers
+}
+
+// runner holds the state of one Run call
+type runner struct {
+	client  *blockchain.Client
+	chainID uint64
+	config  Config
+
+	mu          sync.Mutex
+	state       *State
+	saveErr     error // First failure to save progress
+	unconfirmed int   // Transfers left submitted after waiting for them
+}
+
+// Run sends a batch of transfers from the client's wallet. Pending transfers are validated
+// first: invalid recipients or amounts and blacklisted recipients are marked invalid, while a
+// blacklisted sender or a balance below the total stops the batch before anything is sent.
+//
+// Each transfer is signed, saved as submitted with its nonce and hash, and only then
+// broadcast, so a crash never loses track of a transaction that reached the node; if that
+// save fails, Run stops with the error before broadcasting. A transfer
+// that is not confirmed within the wait stays submitted and Run returns ErrUnconfirmed; it
+// is marked failed only when it reverted or another transaction took its nonce.
+//
+// If config.Store holds the state of an earlier run of the same batch, final transfers are
+// skipped and submitted ones are broadcast again and waited for rather than sent anew. When
+// ctx is cancelled, Run returns the report so far with ctx's error; unfinished transfers stay
+// resumable.
+func Run(ctx context.Context, client *blockchain.Client, chainID uint64, transfers []Transfer, config *Config) (*Report, error) {
+	r := &runner{client: client, chainID: chainID}
+	if config != nil {
+		r.config = *config
+	}
+	if r.config.Store == nil {
+		r.config.Store = NewMemoryStore()
+	}
+	if r.config.Concurrency <= 0 {
+		r.config.Concurrency = 8
+	}
+	if r.config.Confirmations == 0 {
+		r.config.Confirmations = 1
+	}
+
+	if err := r.load(ctx, transfers); err != nil {
+		return nil, err
+	}
+
+	amounts := make(map[string]*blockchain.TokenAmount, len(transfers))
+	for i, transfer := range transfers {
+		amounts[r.state.Items[i].ID] = transfer.Amount
+	}
+
+	if err := r.validate(ctx, amounts); err != nil {
+		return r.report(), err
+	}
+
+	err := r.send(ctx, amounts)
+	saveErr := r.saveError()
+	if errors.Is(err, saveErr) {
+		saveErr = nil // send stopped on it and returned it already
+	}
+	return r.report(), errors.Join(err, r.unconfirmedError(), saveErr)
+}
+
+// load restores the saved state of the batch, or starts a new one
+func (r *runner) load(ctx context.Context, transfers []Transfer) error {
+	items := make([]Item, len(transfers))
+	ids := make(map[string]bool, len(transfers))
+	for i, transfer := range transfers {
+		id := transfer.ID
+		if id == "" {
+			id = strconv.Itoa(i)
+		}
+		if ids[id] {
+			return fmt.Errorf("duplicate transfer ID %s", id)
+		}
+		ids[id] = true
+
+		if transfer.Amount == nil {
+			return fmt.Errorf("transfer %s has no amount", id)
+		}
+		items[i] = Item{ID: id, To: transfer.To, Amount: transfer.Amount.Amount.String(), Status: StatusPending}
+	}
+
+	saved, err := r.config.Store.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	from := r.client.GetAddress()
+	if saved == nil {
+		r.state = &State{ChainID: r.chainID, From: from, Items: items}
+		return r.save(ctx)
+	}
+
+	if saved.ChainID != r.chainID || saved.From != from || len(saved.Items) != len(items) {
+		return ErrStateMismatch
+	}
+	for i, item := range saved.Items {
+		if item.ID != items[i].ID || item.To != items[i].To || item.Amount != items[i].Amount {
+			return fmt.Errorf("%w: transfer %d is %s", ErrStateMismatch, i, items[i].ID)
+		}
+	}
+	r.state = saved
+	return nil
+}
+
+// validate marks invalid pending transfers and checks the sender can pay for the rest and
+// for transfers submitted by an earlier run, which the latest balance may not reflect yet
+func (r *runner) validate(ctx context.Context, amounts map[string]*blockchain.TokenAmount) error {
+	from := r.client.GetAddress()
+	decimals := int32(r.client.Decimals(r.chainID))
+
+	accounts := []common.Address{from}
+	for _, item := range r.state.Items {
+		if item.Status == StatusPending {
+			accounts = append(accounts, item.To)
+		}
+	}
+
+	blacklisted, err := r.client.BlacklistStatuses(ctx, r.chainID, accounts)
+	if err != nil {
+		return fmt.Errorf("failed to check blacklist: %w", err)
+	}
+	if blacklisted[from] {
+		return fmt.Errorf("sender %s: %w", from.Hex(), blockchain.ErrBlacklisted)
+	}
+
+	total := decimal.Zero
+	for i := range r.state.Items {
+		item := &r.state.Items[i]
+		amount := amounts[item.ID].Amount
+		if item.Status == StatusSubmitted {
+			total = total.Add(amount)
+			continue
+		}
+		if item.Status != StatusPending {
+			continue
+		}
+
+		var reason string
+		switch {
+		case item.To == (common.Address{}):
+			reason = "recipient is the zero address"
+		case !amount.IsPositive():
+			reason = "amount must be positive"
+		case !amount.Equal(amount.Truncate(decimals)):
+			reason = fmt.Sprintf("amount has more than %d decimal places", decimals)
+		case blacklisted[item.To]:
+			reason = "recipient is blacklisted"
+		}
+
+		if reason != "" {
+			r.update(ctx, item.ID, func(item *Item) {
+				item.Status = StatusInvalid
+				item.Error = reason
+			})
+			continue
+		}
+		total = total.Add(amount)
+	}
+
+	balance, err := r.client.BalanceOf(ctx, r.chainID, from)
+	if err != nil {
+		return err
+	}
+	if balance.Amount.LessThan(total) {
+		return fmt.Errorf("%w: balance %s is below the pending and submitted total %s", blockchain.ErrInsufficientBalance, balance.Amount, total)
+	}
+
+	return nil
+}
+
+// send submits pending transfers with sequential nonces, keeping at most Concurrency
+// transactions unconfirmed, and waits for transfers submitted by an earlier run
+func (r *runner) send(ctx context.Context, amounts map[string]*blockchain.TokenAmount) error {
+	contract, err := r.client.GetContract(r.chainID)
+	if err != nil {
+		return err
+	}
+	backend, err := r.client.GetBackend(r.chainID)
+	if err != nil {
+		return err
+	}
+
+	from := r.client.GetAddress()
+	slots := make(chan struct{}, r.config.Concurrency)
+	var wg sync.WaitGroup
+	confirm := func(id string, hash common.Hash, nonce uint64) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			r.confirm(ctx, backend, id, hash, nonce)
+		}()
+	}
+
+	acquire := func() bool {
+		select {
+		case slots <- struct{}{}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for _, item := range r.items(StatusSubmitted) {
+		if !acquire() {
+			break
+		}
+		// The earlier run may have stopped before the broadcast; a node that already has the
+		// transaction rejects it again, which is expected
+		if len(item.RawTx) > 0 {
+			tx := new(types.Transaction)
+			if err := tx.UnmarshalBinary(item.RawTx); err == nil {
+				_ = backend.SendTransaction(ctx, tx)
+			}
+		}
+		confirm(item.ID, *item.TxHash, *item.Nonce)
+	}
+
+	var nonce *uint64
+	for _, item := range r.items(StatusPending) {
+		if !acquire() {
+			break
+		}
+
+		if nonce == nil {
+			next, err := backend.PendingNonceAt(ctx, from)
+			if err != nil {
+				<-slots
+				wg.Wait()
+				return fmt.Errorf("failed to get nonce: %w", err)
+			}
+			nonce = &next
+		}
+
+		tx, err := r.sign(ctx, contract, item, amounts[item.ID], *nonce)
+		if err != nil {
+			<-slots
+			if ctx.Err() != nil {
+				break
+			}
+			// The nonce was not used; fetch it again in case another sender took it
+			nonce = nil
+			r.update(ctx, item.ID, func(item *Item) {
+				item.Status = StatusFailed
+				item.Error = err.Error()
+			})
+			continue
+		}
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			<-slots
+			wg.Wait()
+			return fmt.Errorf("failed to encode transaction: %w", err)
+		}
+
+		used := *nonce
+		*nonce++
+		hash := tx.Hash()
+		err = r.update(ctx, item.ID, func(item *Item) {
+			item.Status = StatusSubmitted
+			item.Nonce = &used
+			item.TxHash = &hash
+			item.RawTx = raw
+		})
+		if err != nil {
+			// Without a saved record a resumed batch would send the transfer again, so it
+			// is not broadcast and stays pending
+			r.mu.Lock()
+			for i := range r.state.Items {
+				if r.state.Items[i].ID == item.ID {
+					r.state.Items[i].Status = StatusPending
+					r.state.Items[i].Nonce = nil
+					r.state.Items[i].TxHash = nil
+					r.state.Items[i].RawTx = nil
+				}
+			}
+			r.mu.Unlock()
+			<-slots
+			wg.Wait()
+			return err
+		}
+
+		if err := backend.SendTransaction(ctx, tx); err != nil {
+			// The node may have accepted the transaction despite the error; it did if the
+			// nonce is taken, so wait for it as usual
+			next, nonceErr := backend.PendingNonceAt(ctx, from)
+			if nonceErr == nil && next > used {
+				nonce = &next
+				confirm(item.ID, hash, used)
+				continue
+			}
+
+			<-slots
+			if ctx.Err() != nil {
+				break
+			}
+			nonce = nil
+			r.update(ctx, item.ID, func(item *Item) {
+				item.Status = StatusFailed
+				item.Error = fmt.Sprintf("failed to send transaction: %v", err)
+				item.Nonce = nil
+				item.TxHash = nil
+				item.RawTx = nil
+			})
+			continue
+		}
+		confirm(item.ID, hash, used)
+	}
+
+	wg.Wait()
+	return ctx.Err()
+}
+
+// sign signs one transfer with an explicit nonce without broadcasting it
+func (r *runner) sign(
+	ctx context.Context,
+	contract *contracts.IDRX,
+	item Item,
+	amount *blockchain.TokenAmount,
+	nonce uint64,
+) (*types.Transaction, error) {
+	transactor, err := r.client.CreateTransactor(ctx, r.chainID)
+	if err != nil {
+		return nil, err
+	}
+	transactor.Context = ctx
+	transactor.Nonce = new(big.Int).SetUint64(nonce)
+	transactor.NoSend = true
+
+	tx, err := contract.Transfer(transactor, item.To, amount.ToWei())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transfer: %w", err)
+	}
+	return tx, nil
+}
+
+// confirm waits for a submitted transfer and records the outcome. A cancelled context or a
+// wait that ends without confirmations leaves the transfer submitted so a resumed batch
+// waits for it again, unless another transaction has taken its nonce.
+func (r *runner) confirm(ctx context.Context, backend blockchain.Backend, id string, hash common.Hash, nonce uint64) {
+	waitCtx := ctx
+	if r.config.WaitTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, r.config.WaitTimeout)
+		defer cancel()
+	}
+
+	receipt, err := r.client.WaitForConfirmations(waitCtx, r.chainID, hash, r.config.Confirmations)
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err != nil {
+		replaced, checkErr := r.replaced(ctx, backend, hash, nonce)
+		if checkErr != nil || !replaced {
+			r.mu.Lock()
+			r.unconfirmed++
+			r.mu.Unlock()
+			r.update(ctx, id, func(item *Item) {
+				item.Error = fmt.Sprintf("not confirmed yet: %v", err)
+			})
+			return
+		}
+	}
+
+	r.update(ctx, id, func(item *Item) {
+		switch {
+		case err != nil:
+			item.Status = StatusFailed
+			item.Error = fmt.Sprintf("nonce %d was used by another transaction", nonce)
+		case receipt.Status != types.ReceiptStatusSuccessful:
+			item.Status = StatusFailed
+			item.BlockNumber = receipt.BlockNumber.Uint64()
+			item.Error = "transaction reverted"
+		default:
+			item.Status = StatusConfirmed
+			item.BlockNumber = receipt.BlockNumber.Uint64()
+			item.Error = ""
+		}
+	})
+}
+
+// replaced reports whether a transfer that was not mined can no longer be: a mined
+// transaction of the sender has taken its nonce and it has no receipt
+func (r *runner) replaced(ctx context.Context, backend blockchain.Backend, hash common.Hash, nonce uint64) (bool, error) {
+	mined, err := backend.NonceAt(ctx, r.client.GetAddress(), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	if mined <= nonce {
+		return false, nil
+	}
+
+	_, err = backend.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return true, nil
+	}
+	return false, err
+}
+
+// items returns copies of the items with a status, in batch order
+func (r *runner) items(status Status) []Item {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var items []Item
+	for _, item := range r.state.Items {
+		if item.Status == status {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// update changes an item, saves the state and reports the progress. A failure to save is
+// returned and also kept for Run, so callers with transactions already in flight can keep going.
+func (r *runner) update(ctx context.Context, id string, change func(item *Item)) error {
+	r.mu.Lock()
+	var updated Item
+	for i := range r.state.Items {
+		if r.state.Items[i].ID == id {
+			change(&r.state.Items[i])
+			updated = r.state.Items[i]
+			break
+		}
+	}
+	var saveErr error
+	if err := r.saveLocked(context.WithoutCancel(ctx)); err != nil {
+		saveErr = fmt.Errorf("failed to save batch progress: %w", err)
+		if r.saveErr == nil {
+			r.saveErr = saveErr
+		}
+	}
+	r.mu.Unlock()
+
+	if r.config.OnProgress != nil {
+		r.config.OnProgress(updated)
+	}
+	return saveErr
+}
+
+// unconfirmedError reports transfers left submitted after waiting for them
+func (r *runner) unconfirmedError() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.unconfirmed == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %d transfers", ErrUnconfirmed, r.unconfirmed)
+}
+
+// saveError returns the first failure to save progress
+func (r *runner) saveError() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.saveErr
+}
+
+// save persists the state
+func (r *runner) save(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.saveLocked(ctx)
+}
+
+// saveLocked persists the state; r.mu must be held
+func (r *runner) saveLocked(ctx context.Context) error {
+	r.state.UpdatedAt = time.Now()
+	return r.config.Store.Save(ctx, r.state)
+}
+
+// report summarises the current state
+func (r *runner) report() *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &Report{
+		ChainID: r.state.ChainID,
+		From:    r.state.From,
+		Items:   append([]Item(nil), r.state.Items...),
+	}
+	for _, item := range report.Items {
+		switch item.Status {
+		case StatusConfirmed:
+			report.Confirmed++
+		case StatusFailed:
+			report.Failed++
+		case StatusInvalid:
+			report.Invalid++
+		default:
+			report.Pending++
+		}
+	}
+	return report
+}
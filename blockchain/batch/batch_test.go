@@ -0,0 +1,415 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/internal/backendtest"
+)
+
+var (
+	alice    = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	bob      = common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	listed   = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	rejected = common.HexToAddress("0x00000000000000000000000000000000000000dd")
+)
+
+// ledger emulates the token contract for a single sender: transfers to rejected are
+// refused by the node, transfers to held are accepted but never mined, and every other
+// transaction is mined immediately
+type ledger struct {
+	backendtest.Chain
+
+	mu         sync.Mutex
+	balance    int64 // Sender balance in wei
+	sent       map[common.Address]uint64
+	mined      map[common.Hash]bool
+	held       common.Address
+	beforeSend func(tx *types.Transaction)
+}
+
+func newLedger(balance int64) *ledger {
+	return &ledger{
+		Chain:   backendtest.Chain{Head: 50},
+		balance: balance,
+		sent:    map[common.Address]uint64{},
+		mined:   map[common.Hash]bool{},
+	}
+}
+
+func (l *ledger) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (l *ledger) CodeAt(_ context.Context, _ common.Address, _ *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (l *ledger) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return uint64(len(l.sent)), nil
+}
+
+func (l *ledger) NonceAt(_ context.Context, _ common.Address, _ *big.Int) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var nonce uint64
+	for _, mined := range l.mined {
+		if mined {
+			nonce++
+		}
+	}
+	return nonce, nil
+}
+
+func (l *ledger) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, args, err := backendtest.DecodeCall(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch method.Name {
+	case "getBlackListStatus":
+		return method.Outputs.Pack(args[0].(common.Address) == listed)
+	case "balanceOf":
+		return method.Outputs.Pack(big.NewInt(l.balance))
+	}
+	return nil, errors.New("unexpected call " + method.Name)
+}
+
+func (l *ledger) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	if l.beforeSend != nil {
+		l.beforeSend(tx)
+	}
+	_, args, err := backendtest.DecodeCall(tx.Data())
+	if err != nil {
+		return err
+	}
+	to := args[0].(common.Address)
+	if to == rejected {
+		return errors.New("transaction underpriced")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if tx.Nonce() != uint64(len(l.sent)) {
+		return errors.New("nonce gap")
+	}
+	l.sent[to] = tx.Nonce()
+	l.mined[tx.Hash()] = to != l.held
+	return nil
+}
+
+func (l *ledger) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.mined[hash] {
+		return nil, ethereum.NotFound
+	}
+	return &types.Receipt{TxHash: hash, Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(50), BlockHash: common.Hash{0x50}}, nil
+}
+
+func TestRunReportsEachTransfer(t *testing.T) {
+	backend := newLedger(100000)
+	client := backendtest.NewSigningClient(t, backend, backendtest.FastBlocks)
+
+	transfers := []Transfer{
+		{ID: "alice", To: alice, Amount: backendtest.Amount("10")},
+		{ID: "zero", To: common.Address{}, Amount: backendtest.Amount("10")},
+		{ID: "listed", To: listed, Amount: backendtest.Amount("10")},
+		{ID: "fraction", To: bob, Amount: backendtest.Amount("0.001")},
+		{ID: "rejected", To: rejected, Amount: backendtest.Amount("10")},
+		{ID: "bob", To: bob, Amount: backendtest.Amount("20.5")},
+	}
+
+	var progress []Item
+	report, err := Run(context.Background(), client, backendtest.ChainID, transfers, &Config{
+		Concurrency: 1,
+		OnProgress:  func(item Item) { progress = append(progress, item) },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Status{StatusConfirmed, StatusInvalid, StatusInvalid, StatusInvalid, StatusFailed, StatusConfirmed}
+	for i, item := range report.Items {
+		if item.Status != expected[i] {
+			t.Errorf("%s: expected %s, got %s (%s)", item.ID, expected[i], item.Status, item.Error)
+		}
+	}
+	if report.Confirmed != 2 || report.Invalid != 3 || report.Failed != 1 || report.Pending != 0 {
+		t.Errorf("unexpected counts: %+v", report)
+	}
+
+	// The rejected transfer must not leave a nonce gap
+	if backend.sent[alice] != 0 || backend.sent[bob] != 1 {
+		t.Errorf("expected nonces 0 and 1, got %v", backend.sent)
+	}
+	if len(progress) == 0 {
+		t.Error("expected progress callbacks")
+	}
+}
+
+func TestRunStopsOnInsufficientBalance(t *testing.T) {
+	backend := newLedger(1500)
+	client := backendtest.NewSigningClient(t, backend, backendtest.FastBlocks)
+
+	transfers := []Transfer{
+		{To: alice, Amount: backendtest.Amount("10")},
+		{To: bob, Amount: backendtest.Amount("10")},
+	}
+
+	report, err := Run(context.Background(), client, backendtest.ChainID, transfers, nil)
+	if !errors.Is(err, blockchain.ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance, got: %v", err)
+	}
+	if len(backend.sent) != 0 || report.Pending != 2 {
+		t.Errorf("nothing should be sent, got %v", backend.sent)
+	}
+}
+
+func TestRunCountsSubmittedTransfersAgainstBalance(t *testing.T) {
+	backend := newLedger(2500)
+	client := backendtest.NewSigningClient(t, backend, backendtest.FastBlocks)
+	store := NewMemoryStore()
+
+	transfers := []Transfer{
+		{ID: "alice", To: alice, Amount: backendtest.Amount("20")},
+		{ID: "bob", To: bob, Amount: backendtest.Amount("10")},
+	}
+
+	// A previous run submitted alice, which is not mined yet, so the balance still includes it
+	submitted := common.Hash{0xa1}
+	nonce := uint64(0)
+	err := store.Save(context.Background(), &State{
+		ChainID: backendtest.ChainID,
+		From:    client.GetAddress(),
+		Items: []Item{
+			{ID: "alice", To: alice, Amount: "20", Status: StatusSubmitted, Nonce: &nonce, TxHash: &submitted},
+			{ID: "bob", To: bob, Amount: "10", Status: StatusPending},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+
+	_, err = Run(context.Background(), client, backendtest.ChainID, transfers, &Config{Store: store})
+	if !errors.Is(err, blockchain.ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance, got: %v", err)
+	}
+	if len(backend.sent) != 0 {
+		t.Errorf("nothing should be sent, got %v", backend.sent)
+	}
+}
+
+func TestRunResumesFromStateFile(t *testing.T) {
+	backend := newLedger(100000)
+	client := backendtest.NewSigningClient(t, backend, backendtest.FastBlocks)
+	store := NewFileStore(filepath.Join(t.TempDir(), "payroll.json"))
+
+	transfers := []Transfer{
+		{ID: "alice", To: alice, Amount: backendtest.Amount("10")},
+		{ID: "bob", To: bob, Amount: backendtest.Amount("20")},
+	}
+
+	// A previous run confirmed alice and crashed after submitting bob
+	submitted := common.Hash{0xb0}
+	nonce := uint64(1)
+	backend.sent[alice] = 0
+	backend.sent[bob] = 1
+	backend.mined[submitted] = true
+	err := store.Save(context.Background(), &State{
+		ChainID: backendtest.ChainID,
+		From:    client.GetAddress(),
+		Items: []Item{
+			{ID: "alice", To: alice, Amount: "10", Status: StatusConfirmed},
+			{ID: "bob", To: bob, Amount: "20", Status: StatusSubmitted, Nonce: &nonce, TxHash: &submitted},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+
+	report, err := Run(context.Background(), client, backendtest.ChainID, transfers, &Config{Store: store})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Confirmed != 2 {
+		t.Errorf("expected both transfers confirmed, got %+v", report.Items)
+	}
+	if len(backend.sent) != 2 {
+		t.Errorf("no transfer should be sent again, got %v", backend.sent)
+	}
+
+	saved, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("failed to load state: %v", err)
+	}
+	if saved.Items[1].Status != StatusConfirmed || saved.Items[1].BlockNumber != 50 {
+		t.Errorf("expected bob confirmed in block 50 in the state file, got %+v", saved.Items[1])
+	}
+
+	// A different batch must not reuse the state
+	transfers[1].Amount = backendtest.Amount("25")
+	if _, err := Run(context.Background(), client, backendtest.ChainID, transfers, &Config{Store: store}); !errors.Is(err, ErrStateMismatch) {
+		t.Errorf("expected ErrStateMismatch, got: %v", err)
+	}
+}
+
+func TestRunSavesTransfersBeforeBroadcast(t *testing.T) {
+	backend := newLedger(100000)
+	client := backendtest.NewSigningClient(t, backend, backendtest.FastBlocks)
+	store := NewMemoryStore()
+
+	var saved []Item
+	backend.beforeSend = func(tx *types.Transaction) {
+		state, err := store.Load(context.Background())
+		if err != nil {
+			t.Errorf("failed to load state: %v", err)
+			return
+		}
+		for _, item := range state.Items {
+			if item.TxHash != nil && *item.TxHash == tx.Hash() {
+				saved = append(saved, item)
+			}
+		}
+	}
+
+	transfers := []Transfer{
+		{ID: "alice", To: alice, Amount: backendtest.Amount("10")},
+		{ID: "bob", To: bob, Amount: backendtest.Amount("20")},
+	}
+	if _, err := Run(context.Background(), client, backendtest.ChainID, transfers, &Config{Store: store}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(saved) != 2 {
+		t.Fatalf("expected both transfers saved before broadcast, got %+v", saved)
+	}
+	for _, item := range saved {
+		if item.Status != StatusSubmitted || item.Nonce == nil || len(item.RawTx) == 0 {
+			t.Errorf("%s: expected a submitted transfer with nonce and signed transaction, got %+v", item.ID, item)
+		}
+	}
+}
+
+// failingStore fails every save that records a submitted transfer
+type failingStore struct {
+	*MemoryStore
+}
+
+func (s failingStore) Save(ctx context.Context, state *State) error {
+	for _, item := range state.Items {
+		if item.Status == StatusSubmitted {
+			return errors.New("disk full")
+		}
+	}
+	return s.MemoryStore.Save(ctx, state)
+}
+
+func TestRunStopsWhenSubmittedTransferCannotBeSaved(t *testing.T) {
+	backend := newLedger(100000)
+	client := backendtest.NewSigningClient(t, backend, backendtest.FastBlocks)
+	store := failingStore{NewMemoryStore()}
+
+	broadcasts := 0
+	backend.beforeSend = func(_ *types.Transaction) { broadcasts++ }
+
+	transfers := []Transfer{
+		{ID: "alice", To: alice, Amount: backendtest.Amount("10")},
+		{ID: "bob", To: bob, Amount: backendtest.Amount("20")},
+	}
+	report, err := Run(context.Background(), client, backendtest.ChainID, transfers, &Config{Store: store})
+	if err == nil {
+		t.Fatal("expected the save error")
+	}
+	if broadcasts != 0 {
+		t.Errorf("expected nothing broadcast, got %d transactions", broadcasts)
+	}
+	if report.Pending != 2 {
+		t.Errorf("expected both transfers to stay pending, got %+v", report)
+	}
+}
+
+func TestRunLeavesUnconfirmedTransfersSubmitted(t *testing.T) {
+	backend := newLedger(100000)
+	backend.held = bob
+	client := backendtest.NewSigningClient(t, backend, backendtest.FastBlocks)
+	store := NewMemoryStore()
+
+	transfers := []Transfer{
+		{ID: "alice", To: alice, Amount: backendtest.Amount("10")},
+		{ID: "bob", To: bob, Amount: backendtest.Amount("20")},
+	}
+	config := &Config{Store: store, WaitTimeout: 100 * time.Millisecond}
+	report, err := Run(context.Background(), client, backendtest.ChainID, transfers, config)
+	if !errors.Is(err, ErrUnconfirmed) {
+		t.Fatalf("expected ErrUnconfirmed, got: %v", err)
+	}
+	if report.Items[1].Status != StatusSubmitted || report.Confirmed != 1 || report.Pending != 1 {
+		t.Fatalf("expected bob left submitted, got %+v", report.Items)
+	}
+
+	// Resuming broadcasts bob again; once mined the batch completes
+	backend.held = common.Address{}
+	backend.mu.Lock()
+	delete(backend.sent, bob)
+	backend.mu.Unlock()
+	report, err = Run(context.Background(), client, backendtest.ChainID, transfers, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Confirmed != 2 || report.Items[1].Error != "" {
+		t.Errorf("expected both transfers confirmed, got %+v", report.Items)
+	}
+}
+
+func TestRunFailsReplacedTransfers(t *testing.T) {
+	backend := newLedger(100000)
+	client := backendtest.NewSigningClient(t, backend, backendtest.FastBlocks)
+	store := NewMemoryStore()
+
+	// Nonce 1 of the submitted transfer was mined by another transaction
+	dropped := common.Hash{0xd0}
+	nonce := uint64(1)
+	backend.sent[alice] = 0
+	backend.sent[listed] = 1
+	backend.mined[common.Hash{0xa1}] = true
+	backend.mined[common.Hash{0xbb}] = true
+	err := store.Save(context.Background(), &State{
+		ChainID: backendtest.ChainID,
+		From:    client.GetAddress(),
+		Items: []Item{
+			{ID: "alice", To: alice, Amount: "10", Status: StatusConfirmed},
+			{ID: "bob", To: bob, Amount: "20", Status: StatusSubmitted, Nonce: &nonce, TxHash: &dropped},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+
+	transfers := []Transfer{
+		{ID: "alice", To: alice, Amount: backendtest.Amount("10")},
+		{ID: "bob", To: bob, Amount: backendtest.Amount("20")},
+	}
+	report, err := Run(context.Background(), client, backendtest.ChainID, transfers, &Config{Store: store, WaitTimeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Items[1].Status != StatusFailed || report.Failed != 1 {
+		t.Errorf("expected bob failed as replaced, got %+v", report.Items[1])
+	}
+}
@@ -0,0 +1,149 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/widnyana/idrx-go/blockchain/internal/atomicfile"
+)
+
+// Status is the progress of one transfer in a batch
+type Status string
+
+// Transfer statuses. Invalid, Confirmed and Failed are final; a resumed batch skips them.
+const (
+	StatusPending   Status = "pending"   // Not sent yet
+	StatusInvalid   Status = "invalid"   // Rejected by pre-validation; never sent
+	StatusSubmitted Status = "submitted" // Signed and broadcast, or about to be; waiting for confirmations
+	StatusConfirmed Status = "confirmed" // Mined successfully with the required confirmations
+	StatusFailed    Status = "failed"    // Could not be sent, reverted, or its nonce was used by another transaction
+)
+
+// Final reports whether a status will not change when the batch is resumed
+func (s Status) Final() bool {
+	return s == StatusInvalid || s == StatusConfirmed || s == StatusFailed
+}
+
+// Item records the progress of one transfer
+type Item struct {
+	ID          string         `json:"id"`
+	To          common.Address `json:"to"`
+	Amount      string         `json:"amount"`
+	Status      Status         `json:"status"`
+	Nonce       *uint64        `json:"nonce,omitempty"`
+	TxHash      *common.Hash   `json:"txHash,omitempty"`
+	RawTx       hexutil.Bytes  `json:"rawTx,omitempty"` // Signed transaction, broadcast again on resume
+	BlockNumber uint64         `json:"blockNumber,omitempty"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// State is the persisted progress of a batch
+type State struct {
+	ChainID   uint64         `json:"chainId"`
+	From      common.Address `json:"from"`
+	Items     []Item         `json:"items"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// clone returns a deep copy of the state
+func (s *State) clone() *State {
+	copied := *s
+	copied.Items = append([]Item(nil), s.Items...)
+	return &copied
+}
+
+// StateStore persists the progress of a batch so it can be resumed after a crash.
+// Implementations must be safe for concurrent use.
+type StateStore interface {
+	// Load returns the saved state, or nil if the batch has not been started
+	Load(ctx context.Context) (*State, error)
+	// Save replaces the saved state
+	Save(ctx context.Context, state *State) error
+}
+
+// MemoryStore keeps the state in memory; progress is lost when the process exits
+type MemoryStore struct {
+	mu    sync.Mutex
+	state *State
+}
+
+// NewMemoryStore creates an empty in-memory state store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Load implements StateStore
+func (s *MemoryStore) Load(_ context.Context) (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state == nil {
+		return nil, nil
+	}
+	return s.state.clone(), nil
+}
+
+// Save implements StateStore
+func (s *MemoryStore) Save(_ context.Context, state *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state = state.clone()
+	return nil
+}
+
+// FileStore keeps the state of one batch in a JSON file, replaced atomically on every save
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileStore creates a store backed by the JSON file at path; the file is created on first save
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load implements StateStore
+func (s *FileStore) Load(_ context.Context) (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch state file: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode batch state file %s: %w", s.path, err)
+	}
+	return &state, nil
+}
+
+// Save implements StateStore
+func (s *FileStore) Save(_ context.Context, state *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode batch state: %w", err)
+	}
+
+	if err := atomicfile.Write(s.path, data); err != nil {
+		return fmt.Errorf("failed to save batch state: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BlockAtTime returns the number of the last block on a chain whose timestamp is at or before t,
+// found by binary search over block headers. Use it with BalanceAt and TotalSupplyAt to report
+// state as of a point in time, e.g. the end of a month.
+func (c *Client) BlockAtTime(ctx context.Context, chainID uint64, t time.Time) (*big.Int, error) {
+	backend, err := c.GetBackend(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Unix() < 0 {
+		return nil, fmt.Errorf("time %s is before the Unix epoch", t)
+	}
+	target := uint64(t.Unix())
+
+	head, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	if head.Time <= target {
+		return head.Number, nil
+	}
+
+	low, high := uint64(0), head.Number.Uint64()
+	genesis, err := headerAt(ctx, backend, low)
+	if err != nil {
+		return nil, err
+	}
+	if genesis.Time > target {
+		return nil, fmt.Errorf("time %s is before the first block on chain %d", t.UTC().Format(time.RFC3339), chainID)
+	}
+
+	// Invariant: block low is at or before target, block high is after it
+	for high-low > 1 {
+		mid := low + (high-low)/2
+		header, err := headerAt(ctx, backend, mid)
+		if err != nil {
+			return nil, err
+		}
+		if header.Time <= target {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	return new(big.Int).SetUint64(low), nil
+}
+
+// headerAt fetches the header of a block by number
+func headerAt(ctx context.Context, backend Backend, number uint64) (*types.Header, error) {
+	header, err := backend.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header %d: %w", number, err)
+	}
+	return header, nil
+}
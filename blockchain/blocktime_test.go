@@ -0,0 +1,108 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// headerBackend serves headers for a chain with a fixed block interval
+type headerBackend struct {
+	Backend // unimplemented methods panic if called
+
+	genesis  uint64
+	interval uint64
+	head     uint64
+	lookups  atomic.Int32
+}
+
+func (h *headerBackend) ChainID(_ context.Context) (*big.Int, error) {
+	return big.NewInt(31337), nil
+}
+
+func (h *headerBackend) BlockNumber(_ context.Context) (uint64, error) {
+	return h.head, nil
+}
+
+func (h *headerBackend) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	h.lookups.Add(1)
+	n := h.head
+	if number != nil {
+		n = number.Uint64()
+	}
+	return &types.Header{Number: new(big.Int).SetUint64(n), Time: h.genesis + n*h.interval}, nil
+}
+
+func newHeaderClient(t *testing.T, backend Backend) *Client {
+	t.Helper()
+
+	registry := NewRegistry()
+	devnet := DevnetConfig(31337, "http://127.0.0.1:8545", common.HexToAddress("0x00000000000000000000000000000000000000aa"), 2)
+	if err := registry.Register("Devnet", devnet); err != nil {
+		t.Fatalf("failed to register devnet: %v", err)
+	}
+
+	client, err := NewClient(&ClientConfig{
+		PrivateKeyHex: testPrivateKey,
+		Registry:      registry,
+		Backends:      map[string]Backend{"Devnet": backend},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func TestBlockAtTime(t *testing.T) {
+	backend := &headerBackend{genesis: 1700000000, interval: 2, head: 1_000_000}
+	client := newHeaderClient(t, backend)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		time     time.Time
+		expected uint64
+	}{
+		{"exact block time", time.Unix(1700000000+2*4321, 0), 4321},
+		{"between blocks", time.Unix(1700000000+2*4321+1, 0), 4321},
+		{"genesis", time.Unix(1700000000, 0), 0},
+		{"after head", time.Unix(1800000000, 0), 1_000_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block, err := client.BlockAtTime(ctx, 31337, tt.time)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if block.Uint64() != tt.expected {
+				t.Errorf("expected block %d, got %d", tt.expected, block.Uint64())
+			}
+		})
+	}
+
+	if _, err := client.BlockAtTime(ctx, 31337, time.Unix(1600000000, 0)); err == nil {
+		t.Error("expected error for time before the first block")
+	}
+}
+
+func TestBlockAtTimeUsesBinarySearch(t *testing.T) {
+	backend := &headerBackend{genesis: 1700000000, interval: 2, head: 1_000_000}
+	client := newHeaderClient(t, backend)
+
+	if _, err := client.BlockAtTime(context.Background(), 31337, time.Unix(1700123457, 0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Latest and genesis headers plus about log2(1,000,000) probes
+	if lookups := backend.lookups.Load(); lookups > 25 {
+		t.Errorf("expected a logarithmic number of header lookups, got %d", lookups)
+	}
+}
@@ -3,6 +3,8 @@ package blockchain
 import (
 	"context"
 	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
@@ -18,13 +20,13 @@ import (
 	"github.com/widnyana/idrx-go/contracts"
 )
 
+// ErrReadOnly is returned when a client created without a private key is asked to sign
+var ErrReadOnly = errors.New("client has no private key")
+
 // Client represents a multi-chain blockchain client for IDRX operations
 type Client struct {
-	// clients maps chain ID to ethereum clients with failover support
-	clients map[uint64][]*ethclient.Client
-
-	// contracts maps chain ID to IDRX contract instances
-	contracts map[uint64]*contracts.IDRX
+	// networks maps chain ID to enabled networks; connections are dialled on first use
+	networks map[uint64]*networkConn
 
 	// privateKey for signing transactions
 	privateKey *ecdsa.PrivateKey
@@ -40,137 +42,386 @@ type Client struct {
 
 	// timeout for RPC requests
 	timeout time.Duration
+
+	// poolConfig controls endpoint health checks and routing
+	poolConfig *PoolConfig
+
+	// registry holds the network definitions this client resolves names and chain IDs against
+	registry *Registry
+
+	// simulateWrites enables the eth_call pre-check on writes
+	simulateWrites bool
+
+	// multicallDeployed caches, per chain ID, whether Multicall3 has code on that chain
+	multicallDeployed sync.Map
+
+	// roleIDs caches role identifiers read from the contract, keyed by roleKey
+	roleIDs sync.Map
 }
 
 // ClientConfig represents configuration for the blockchain client
 type ClientConfig struct {
-	PrivateKeyHex string        // Hex-encoded private key
-	Timeout       time.Duration // RPC request timeout
+	PrivateKeyHex string            // Hex-encoded private key (empty for a read-only client)
+	PrivateKey    *ecdsa.PrivateKey // Signing key, e.g. one derived from an HD wallet; overrides PrivateKeyHex
+	Timeout       time.Duration     // RPC request timeout
+	Pool          *PoolConfig       // Endpoint pool settings (defaults to DefaultPoolConfig)
+	Networks      []string          // Network names to enable (defaults to every registered network)
+	Registry      *Registry         // Network definitions (defaults to DefaultRegistry)
+
+	// SimulateWrites runs every write through Simulate before signing it, so reverts
+	// surface as a *RevertError instead of a mined transaction with failed status
+	SimulateWrites bool
+
+	// Backends replaces RPC dialling for the named networks with pre-built backends,
+	// such as go-ethereum's simulated backend in tests. The caller keeps ownership
+	// of these backends; Close does not close them.
+	Backends map[string]Backend
 }
 
-// NewClient creates a new blockchain client with multi-chain support
-func NewClient(config *ClientConfig) (*Client, error) {
-	// Parse private key
-	privateKey, err := crypto.HexToECDSA(config.PrivateKeyHex)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: %w", err)
-	}
+// NetworkError reports a failure to connect to a specific network
+type NetworkError struct {
+	Network string
+	ChainID uint64
+	Err     error
+}
 
-	// Derive public key and address
-	publicKeyInterface := privateKey.Public()
-	publicKey, ok := publicKeyInterface.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, fmt.Errorf("failed to cast public key to ECDSA public key")
+// Error implements the error interface
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("%s (chain ID %d): %v", e.Network, e.ChainID, e.Err)
+}
+
+// Unwrap returns the underlying connection error
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON encodes the network, chain ID and error message
+func (e *NetworkError) MarshalJSON() ([]byte, error) {
+	var message string
+	if e.Err != nil {
+		message = e.Err.Error()
 	}
-	address := crypto.PubkeyToAddress(*publicKey)
+	return json.Marshal(struct {
+		Network string `json:"network"`
+		ChainID uint64 `json:"chainId"`
+		Error   string `json:"error"`
+	}{e.Network, e.ChainID, message})
+}
+
+// networkConn holds the lazily established connection to a single network
+type networkConn struct {
+	name     string
+	config   *NetworkConfig
+	injected Backend // optional caller-provided backend used instead of RPC endpoints
 
+	mu       sync.Mutex
+	pool     *endpointPool
+	contract *contracts.IDRX
+	lastErr  error
+}
+
+// NewClient creates a new blockchain client with multi-chain support.
+// No connections are made until a network is first used; call Connect to dial eagerly.
+func NewClient(config *ClientConfig) (*Client, error) {
 	client := &Client{
-		clients:    make(map[uint64][]*ethclient.Client),
-		contracts:  make(map[uint64]*contracts.IDRX),
-		privateKey: privateKey,
-		publicKey:  publicKey,
-		address:    address,
+		networks:   make(map[uint64]*networkConn),
 		timeout:    config.Timeout,
+		poolConfig: config.Pool.withDefaults(),
+		registry:   config.Registry,
+
+		simulateWrites: config.SimulateWrites,
+	}
+
+	// Without a private key the client is read-only
+	privateKey := config.PrivateKey
+	if privateKey == nil && config.PrivateKeyHex != "" {
+		parsed, err := crypto.HexToECDSA(config.PrivateKeyHex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		privateKey = parsed
+	}
+
+	if privateKey != nil {
+		// Derive public key and address
+		publicKeyInterface := privateKey.Public()
+		publicKey, ok := publicKeyInterface.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("failed to cast public key to ECDSA public key")
+		}
+
+		client.privateKey = privateKey
+		client.publicKey = publicKey
+		client.address = crypto.PubkeyToAddress(*publicKey)
+	}
+
+	if client.registry == nil {
+		client.registry = DefaultRegistry()
 	}
 
 	if client.timeout == 0 {
 		client.timeout = 30 * time.Second
 	}
 
-	// Initialize connections to all supported networks
-	if err := client.initializeNetworks(); err != nil {
-		return nil, fmt.Errorf("failed to initialize networks: %w", err)
+	if err := client.registerNetworks(config.Networks); err != nil {
+		return nil, err
+	}
+
+	for name, backend := range config.Backends {
+		networkConfig, exists := client.registry.Get(name)
+		if !exists {
+			return nil, fmt.Errorf("backend provided for unknown network %s", name)
+		}
+		conn, enabled := client.networks[networkConfig.ChainID]
+		if !enabled {
+			return nil, fmt.Errorf("backend provided for network %s which is not enabled", name)
+		}
+		conn.injected = backend
 	}
 
 	return client, nil
 }
 
-// initializeNetworks connects to all supported blockchain networks
-func (c *Client) initializeNetworks() error {
-	for _, networkConfig := range SupportedNetworks {
+// registerNetworks records the networks this client may connect to
+func (c *Client) registerNetworks(names []string) error {
+	if len(names) == 0 {
+		names = c.registry.Names()
+	}
+
+	for _, name := range names {
+		networkConfig, exists := c.registry.Get(name)
+		if !exists {
+			return fmt.Errorf("network %s not supported", name)
+		}
+
 		// Skip if contract not deployed on this network
 		if networkConfig.ContractAddress == (common.Address{}) {
 			continue
 		}
 
-		var ethClients []*ethclient.Client
-		var contractInstance *contracts.IDRX
+		c.networks[networkConfig.ChainID] = &networkConn{name: name, config: networkConfig}
+	}
 
-		// Connect to each RPC endpoint for failover support
-		for _, rpcEndpoint := range networkConfig.RPCEndpoints {
-			ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
-			ethClient, err := ethclient.DialContext(ctx, rpcEndpoint)
-			cancel()
+	return nil
+}
 
-			if err != nil {
-				// Log error but continue with other endpoints
-				fmt.Printf("Failed to connect to %s (%s): %v\n", networkConfig.Name, rpcEndpoint, err)
-				continue
+// Connect dials the given networks (or every enabled network when none are given)
+// and returns a *NetworkError for each network that has no reachable endpoint
+func (c *Client) Connect(ctx context.Context, networkNames ...string) error {
+	var targets []*networkConn
+	if len(networkNames) == 0 {
+		for _, conn := range c.networks {
+			targets = append(targets, conn)
+		}
+	} else {
+		for _, name := range networkNames {
+			networkConfig, exists := c.registry.Get(name)
+			if !exists {
+				return fmt.Errorf("network %s not supported", name)
 			}
+			conn, enabled := c.networks[networkConfig.ChainID]
+			if !enabled {
+				return fmt.Errorf("network %s not enabled", name)
+			}
+			targets = append(targets, conn)
+		}
+	}
 
-			ethClients = append(ethClients, ethClient)
-
-			// Create contract instance using the first successful connection
-			if contractInstance == nil {
-				contractInstance, err = contracts.NewIDRX(networkConfig.ContractAddress, ethClient)
-				if err != nil {
-					return fmt.Errorf("failed to create contract instance for %s: %w", networkConfig.Name, err)
-				}
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, conn := range targets {
+		wg.Add(1)
+		go func(i int, conn *networkConn) {
+			defer wg.Done()
+			if _, err := c.connect(ctx, conn); err != nil {
+				errs[i] = err
 			}
+		}(i, conn)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// NetworkErrors returns the most recent connection error for each enabled network that failed to connect
+func (c *Client) NetworkErrors() map[string]error {
+	result := make(map[string]error)
+	for _, conn := range c.networks {
+		conn.mu.Lock()
+		if conn.pool == nil && conn.lastErr != nil {
+			result[conn.name] = conn.lastErr
 		}
+		conn.mu.Unlock()
+	}
+	return result
+}
+
+// EnabledNetworks returns the names of the networks this client is configured for
+func (c *Client) EnabledNetworks() []string {
+	names := make([]string, 0, len(c.networks))
+	for _, conn := range c.networks {
+		names = append(names, conn.name)
+	}
+	return names
+}
 
-		if len(ethClients) == 0 {
-			return fmt.Errorf("failed to connect to any RPC endpoint for %s", networkConfig.Name)
+// connect dials a network's RPC endpoints on first use. Failed attempts are
+// not cached, so the next call tries again.
+func (c *Client) connect(ctx context.Context, conn *networkConn) (*networkConn, error) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	if conn.pool != nil {
+		return conn, nil
+	}
+
+	networkConfig := conn.config
+	var endpoints []*endpoint
+	var dialErrs []error
+
+	if conn.injected != nil {
+		endpoints = append(endpoints, &endpoint{url: "injected:" + conn.name, backend: conn.injected, external: true})
+	} else {
+		// Connect to every RPC endpoint at once for failover support, within one timeout
+		dialCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		clients := make([]*ethclient.Client, len(networkConfig.RPCEndpoints))
+		errs := make([]error, len(networkConfig.RPCEndpoints))
+		var wg sync.WaitGroup
+		for i, rpcEndpoint := range networkConfig.RPCEndpoints {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				clients[i], errs[i] = ethclient.DialContext(dialCtx, rpcEndpoint)
+			}()
 		}
+		wg.Wait()
+		cancel()
 
-		c.clients[networkConfig.ChainID] = ethClients
-		c.contracts[networkConfig.ChainID] = contractInstance
+		for i, rpcEndpoint := range networkConfig.RPCEndpoints {
+			if errs[i] != nil {
+				dialErrs = append(dialErrs, fmt.Errorf("%s: %w", rpcEndpoint, errs[i]))
+				continue
+			}
+			endpoints = append(endpoints, &endpoint{url: rpcEndpoint, backend: clients[i]})
+		}
 	}
 
-	return nil
+	if len(endpoints) == 0 {
+		return nil, c.connectFailed(conn, fmt.Errorf("failed to connect to any RPC endpoint: %w", errors.Join(dialErrs...)))
+	}
+
+	pool := newEndpointPool(networkConfig.ChainID, endpoints, c.poolConfig)
+	pool.start()
+
+	if !pool.reachable() {
+		err := pool.lastError()
+		pool.close()
+		return nil, c.connectFailed(conn, fmt.Errorf("no reachable RPC endpoint: %w", err))
+	}
+
+	// Bind the contract to the pool so reads fail over between endpoints
+	contractInstance, err := contracts.NewIDRX(networkConfig.ContractAddress, &poolBackend{pool: pool})
+	if err != nil {
+		pool.close()
+		return nil, c.connectFailed(conn, fmt.Errorf("failed to create contract instance: %w", err))
+	}
+
+	conn.pool = pool
+	conn.contract = contractInstance
+	conn.lastErr = nil
+
+	return conn, nil
 }
 
-// GetClient returns an ethereum client for the specified chain ID with failover
-func (c *Client) GetClient(chainID uint64) (*ethclient.Client, error) {
+// connectFailed records and wraps a connection failure for a network; conn.mu must be held
+func (c *Client) connectFailed(conn *networkConn, err error) error {
+	networkErr := &NetworkError{Network: conn.name, ChainID: conn.config.ChainID, Err: err}
+	conn.lastErr = networkErr
+	return networkErr
+}
+
+// getNetwork returns the connected network for the specified chain ID, dialling it if needed.
+// Callers do not pass a context, so dialling is bounded by the client's timeout alone.
+func (c *Client) getNetwork(chainID uint64) (*networkConn, error) {
 	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+	conn, exists := c.networks[chainID]
+	c.mutex.RUnlock()
 
-	clients, exists := c.clients[chainID]
 	if !exists {
-		return nil, fmt.Errorf("chain ID %d not supported or not connected", chainID)
+		if c.registry.IsChainSupported(chainID) {
+			return nil, fmt.Errorf("chain ID %d not enabled", chainID)
+		}
+		return nil, fmt.Errorf("chain ID %d not supported", chainID)
 	}
 
-	// Return the first available client (simple failover strategy)
-	for _, client := range clients {
-		// Test if client is still connected
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		_, err := client.ChainID(ctx)
-		cancel()
+	return c.connect(context.Background(), conn)
+}
 
-		if err == nil {
-			return client, nil
-		}
+// getPool returns the endpoint pool for the specified chain ID
+func (c *Client) getPool(chainID uint64) (*endpointPool, error) {
+	conn, err := c.getNetwork(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return conn.pool, nil
+}
+
+// GetClient returns the ethereum client of the best-scoring endpoint for the specified chain ID.
+// Prefer GetBackend, which retries reads on other endpoints when one fails.
+func (c *Client) GetClient(chainID uint64) (*ethclient.Client, error) {
+	pool, err := c.getPool(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	best, err := pool.best()
+	if err != nil {
+		return nil, err
+	}
+
+	ethClient, ok := best.backend.(*ethclient.Client)
+	if !ok {
+		return nil, fmt.Errorf("chain ID %d is not served by an ethclient connection", chainID)
+	}
+
+	return ethClient, nil
+}
+
+// GetBackend returns a backend for the specified chain ID that routes each request to the
+// healthiest endpoint and retries idempotent reads on another endpoint on failure
+func (c *Client) GetBackend(chainID uint64) (Backend, error) {
+	pool, err := c.getPool(chainID)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("no available clients for chain ID %d", chainID)
+	return &poolBackend{pool: pool}, nil
+}
+
+// GetEndpointStatus returns the health of every RPC endpoint for the specified chain ID
+func (c *Client) GetEndpointStatus(chainID uint64) ([]EndpointStatus, error) {
+	pool, err := c.getPool(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pool.status(), nil
 }
 
 // GetContract returns the IDRX contract instance for the specified chain ID
 func (c *Client) GetContract(chainID uint64) (*contracts.IDRX, error) {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-
-	contract, exists := c.contracts[chainID]
-	if !exists {
-		return nil, fmt.Errorf("IDRX contract not available for chain ID %d", chainID)
+	conn, err := c.getNetwork(chainID)
+	if err != nil {
+		return nil, err
 	}
 
-	return contract, nil
+	return conn.contract, nil
 }
 
 // GetClientByNetwork returns an ethereum client for the specified network name with failover
 func (c *Client) GetClientByNetwork(networkName string) (*ethclient.Client, error) {
-	networkConfig, exists := GetNetworkConfig(networkName)
+	networkConfig, exists := c.registry.Get(networkName)
 	if !exists {
 		return nil, fmt.Errorf("network %s not supported", networkName)
 	}
@@ -179,33 +430,81 @@ func (c *Client) GetClientByNetwork(networkName string) (*ethclient.Client, erro
 
 // GetContractByNetwork returns the IDRX contract instance for the specified network name
 func (c *Client) GetContractByNetwork(networkName string) (*contracts.IDRX, error) {
-	networkConfig, exists := GetNetworkConfig(networkName)
+	networkConfig, exists := c.registry.Get(networkName)
 	if !exists {
 		return nil, fmt.Errorf("network %s not supported", networkName)
 	}
 	return c.GetContract(networkConfig.ChainID)
 }
 
-// GetAddress returns the wallet address associated with this client
-func (c *Client) GetAddress() common.Address {
-	return c.address
+// Registry returns the network registry used by this client
+func (c *Client) Registry() *Registry {
+	return c.registry
 }
 
-// CreateTransactor creates a transactor for signing transactions on the specified chain
-func (c *Client) CreateTransactor(ctx context.Context, chainID uint64) (*bind.TransactOpts, error) {
-	client, err := c.GetClient(chainID)
+// Timeout returns the per-request RPC timeout configured for the client
+func (c *Client) Timeout() time.Duration {
+	return c.timeout
+}
+
+// Decimals returns the token decimals configured for a chain ID
+func (c *Client) Decimals(chainID uint64) uint8 {
+	return c.registry.Decimals(chainID)
+}
+
+// ValidateNetwork checks that a registered network is usable: the IDRX contract
+// address must have code deployed and its decimals() must match the configured Decimals
+func (c *Client) ValidateNetwork(ctx context.Context, networkName string) error {
+	networkConfig, exists := c.registry.Get(networkName)
+	if !exists {
+		return fmt.Errorf("network %s not supported", networkName)
+	}
+
+	backend, err := c.GetBackend(networkConfig.ChainID)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	code, err := backend.CodeAt(ctx, networkConfig.ContractAddress, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get contract code for %s: %w", networkName, err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("no contract deployed at %s on %s", networkConfig.ContractAddress.Hex(), networkName)
 	}
 
-	// Get chain ID from client to ensure it matches
-	clientChainID, err := client.ChainID(ctx)
+	contract, err := c.GetContract(networkConfig.ChainID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+		return err
+	}
+
+	decimals, err := contract.Decimals(callOpts(ctx, nil))
+	if err != nil {
+		return fmt.Errorf("failed to get token decimals for %s: %w", networkName, err)
+	}
+	if decimals != networkConfig.Decimals {
+		return fmt.Errorf("decimals mismatch on %s: contract reports %d, configured %d", networkName, decimals, networkConfig.Decimals)
+	}
+
+	return nil
+}
+
+// GetAddress returns the wallet address associated with this client (zero for a read-only client)
+func (c *Client) GetAddress() common.Address {
+	return c.address
+}
+
+// CreateTransactor creates a transactor for signing transactions on the specified chain
+func (c *Client) CreateTransactor(_ context.Context, chainID uint64) (*bind.TransactOpts, error) {
+	// Only endpoints whose chain ID the pool health checks verified are ever used,
+	// so the configured chain ID can be used without another round trip
+	if _, err := c.getPool(chainID); err != nil {
+		return nil, err
 	}
+	clientChainID := new(big.Int).SetUint64(chainID)
 
-	if clientChainID.Uint64() != chainID {
-		return nil, fmt.Errorf("client chain ID %d does not match requested chain ID %d", clientChainID.Uint64(), chainID)
+	if c.privateKey == nil {
+		return nil, ErrReadOnly
 	}
 
 	// Create transactor with the private key
@@ -215,7 +514,7 @@ func (c *Client) CreateTransactor(ctx context.Context, chainID uint64) (*bind.Tr
 	}
 
 	// Get network config for gas settings
-	networkConfig, _, exists := GetNetworkConfigByChainID(chainID)
+	networkConfig, _, exists := c.registry.GetByChainID(chainID)
 	if exists {
 		transactor.GasLimit = networkConfig.GasLimit
 		if networkConfig.MaxGasPrice > 0 {
@@ -228,7 +527,7 @@ func (c *Client) CreateTransactor(ctx context.Context, chainID uint64) (*bind.Tr
 
 // EstimateGas estimates gas for a contract method call
 func (c *Client) EstimateGas(ctx context.Context, chainID uint64, msg ethereum.CallMsg) (uint64, error) {
-	client, err := c.GetClient(chainID)
+	client, err := c.GetBackend(chainID)
 	if err != nil {
 		return 0, err
 	}
@@ -238,13 +537,13 @@ func (c *Client) EstimateGas(ctx context.Context, chainID uint64, msg ethereum.C
 
 // WaitForTransaction waits for a transaction to be mined and returns the receipt
 func (c *Client) WaitForTransaction(ctx context.Context, chainID uint64, txHash common.Hash) (*types.Receipt, error) {
-	client, err := c.GetClient(chainID)
+	client, err := c.GetBackend(chainID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get network config for appropriate timeout
-	networkConfig, _, exists := GetNetworkConfigByChainID(chainID)
+	networkConfig, _, exists := c.registry.GetByChainID(chainID)
 	timeout := 5 * time.Minute // Default timeout
 	if exists {
 		// Calculate timeout based on block time (allow for ~10 blocks)
@@ -274,14 +573,60 @@ func (c *Client) WaitForTransaction(ctx context.Context, chainID uint64, txHash
 	}
 }
 
-// Close closes all ethereum client connections
+// WaitForConfirmations waits until a transaction has been mined and buried under enough blocks
+// that it has the given number of confirmations (the block including it counts as one).
+// The receipt is fetched again once the depth is reached, so a transaction dropped by a
+// reorg in the meantime is waited for again rather than reported as confirmed.
+func (c *Client) WaitForConfirmations(ctx context.Context, chainID uint64, txHash common.Hash, confirmations uint64) (*types.Receipt, error) {
+	receipt, err := c.WaitForTransaction(ctx, chainID, txHash)
+	if err != nil || confirmations <= 1 {
+		return receipt, err
+	}
+
+	client, err := c.GetBackend(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := time.Second
+	if networkConfig, _, exists := c.registry.GetByChainID(chainID); exists {
+		interval = networkConfig.BlockTime / 2
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		head, err := client.BlockNumber(ctx)
+		if err == nil && head+1 >= receipt.BlockNumber.Uint64()+confirmations {
+			current, err := client.TransactionReceipt(ctx, txHash)
+			if err == nil && current.BlockHash == receipt.BlockHash {
+				return current, nil
+			}
+			if receipt, err = c.WaitForTransaction(ctx, chainID, txHash); err != nil {
+				return nil, err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timeout waiting for %d confirmations of %s: %w", confirmations, txHash.Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops endpoint health checks and closes all ethereum client connections
 func (c *Client) Close() {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	for _, clients := range c.clients {
-		for _, client := range clients {
-			client.Close()
+	for _, conn := range c.networks {
+		conn.mu.Lock()
+		if conn.pool != nil {
+			conn.pool.close()
+			conn.pool = nil
+			conn.contract = nil
 		}
+		conn.mu.Unlock()
 	}
 }
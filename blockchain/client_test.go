@@ -0,0 +1,242 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testPrivateKey is a throwaway key used only by tests
+const testPrivateKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+func TestNewClientDoesNotDial(t *testing.T) {
+	client, err := NewClient(&ClientConfig{PrivateKeyHex: testPrivateKey})
+	if err != nil {
+		t.Fatalf("NewClient should not need network access, got: %v", err)
+	}
+	defer client.Close()
+
+	if len(client.EnabledNetworks()) != len(SupportedNetworks) {
+		t.Errorf("expected all %d networks enabled, got %d", len(SupportedNetworks), len(client.EnabledNetworks()))
+	}
+
+	for _, conn := range client.networks {
+		if conn.pool != nil {
+			t.Errorf("network %s should not be connected before first use", conn.name)
+		}
+	}
+}
+
+func TestNewClientSelectedNetworks(t *testing.T) {
+	client, err := NewClient(&ClientConfig{
+		PrivateKeyHex: testPrivateKey,
+		Networks:      []string{BaseMainnet},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer client.Close()
+
+	enabled := client.EnabledNetworks()
+	if len(enabled) != 1 || enabled[0] != BaseMainnet {
+		t.Errorf("expected only %s to be enabled, got %v", BaseMainnet, enabled)
+	}
+
+	_, err = client.GetContract(PolygonChainID)
+	if err == nil || !strings.Contains(err.Error(), "not enabled") {
+		t.Errorf("expected not enabled error for Polygon, got: %v", err)
+	}
+
+	_, err = client.GetContract(999999)
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("expected not supported error for unknown chain, got: %v", err)
+	}
+}
+
+func TestNewClientUnknownNetwork(t *testing.T) {
+	_, err := NewClient(&ClientConfig{
+		PrivateKeyHex: testPrivateKey,
+		Networks:      []string{"UnknownMainnet"},
+	})
+	if err == nil {
+		t.Fatal("expected error for unknown network")
+	}
+}
+
+func TestNetworkErrorUnwrap(t *testing.T) {
+	netErr := &NetworkError{Network: BaseMainnet, ChainID: BaseChainID, Err: errTest}
+	if !strings.Contains(netErr.Error(), "BaseMainnet") {
+		t.Errorf("error should name the network, got: %s", netErr.Error())
+	}
+	if netErr.Unwrap() != errTest {
+		t.Error("Unwrap should return the underlying error")
+	}
+}
+
+var errTest = errors.New("test error")
+
+// rpcHandler answers a single JSON-RPC method with a result or an error
+type rpcHandler func(params []json.RawMessage) (any, error)
+
+// rpcRequest is a single JSON-RPC request
+type rpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// newTestRPCServer starts a JSON-RPC server that answers eth_chainId and eth_blockNumber
+// plus any extra methods supplied by the test. Batch requests are supported.
+func newTestRPCServer(t *testing.T, chainID uint64, handlers map[string]rpcHandler) *httptest.Server {
+	t.Helper()
+
+	answer := func(req rpcRequest) map[string]any {
+		var (
+			result any
+			err    error
+		)
+		switch req.Method {
+		case "eth_chainId":
+			result = fmt.Sprintf("0x%x", chainID)
+		case "eth_blockNumber":
+			result = "0x64"
+		default:
+			handler, ok := handlers[req.Method]
+			if !ok {
+				err = fmt.Errorf("method %s not found", req.Method)
+			} else {
+				result, err = handler(req.Params)
+			}
+		}
+
+		response := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		if err != nil {
+			rpcErr := map[string]any{"code": -32000, "message": err.Error()}
+			var dataErr rpc.DataError
+			if errors.As(err, &dataErr) {
+				rpcErr["code"] = 3
+				rpcErr["data"] = dataErr.ErrorData()
+			}
+			response["error"] = rpcErr
+		} else {
+			response["result"] = result
+		}
+		return response
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var response any
+		if len(body) > 0 && body[0] == '[' {
+			var batch []rpcRequest
+			if err := json.Unmarshal(body, &batch); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			responses := make([]map[string]any, len(batch))
+			for i, req := range batch {
+				responses[i] = answer(req)
+			}
+			response = responses
+		} else {
+			var req rpcRequest
+			if err := json.Unmarshal(body, &req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			response = answer(req)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// newTestDevnetClient creates a client whose only network is a devnet served by url
+func newTestDevnetClient(t *testing.T, url string, decimals uint8) *Client {
+	t.Helper()
+
+	registry := NewRegistry()
+	devnet := DevnetConfig(31337, url, common.HexToAddress("0x00000000000000000000000000000000000000aa"), decimals)
+	if err := registry.Register("Devnet", devnet); err != nil {
+		t.Fatalf("failed to register devnet: %v", err)
+	}
+
+	client, err := NewClient(&ClientConfig{PrivateKeyHex: testPrivateKey, Registry: registry})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+// uint256Result ABI-encodes a small unsigned integer as an eth_call result
+func uint256Result(value uint64) string {
+	return fmt.Sprintf("0x%064x", value)
+}
+
+func TestValidateNetwork(t *testing.T) {
+	server := newTestRPCServer(t, 31337, map[string]rpcHandler{
+		"eth_getCode": func(_ []json.RawMessage) (any, error) { return "0x6080", nil },
+		"eth_call":    func(_ []json.RawMessage) (any, error) { return uint256Result(2), nil },
+	})
+
+	client := newTestDevnetClient(t, server.URL, 2)
+	if err := client.ValidateNetwork(context.Background(), "Devnet"); err != nil {
+		t.Errorf("expected network to validate, got: %v", err)
+	}
+
+	mismatched := newTestDevnetClient(t, server.URL, 0)
+	err := mismatched.ValidateNetwork(context.Background(), "Devnet")
+	if err == nil || !strings.Contains(err.Error(), "decimals mismatch") {
+		t.Errorf("expected decimals mismatch, got: %v", err)
+	}
+}
+
+func TestValidateNetworkWithoutCode(t *testing.T) {
+	server := newTestRPCServer(t, 31337, map[string]rpcHandler{
+		"eth_getCode": func(_ []json.RawMessage) (any, error) { return "0x", nil },
+	})
+
+	client := newTestDevnetClient(t, server.URL, 2)
+	err := client.ValidateNetwork(context.Background(), "Devnet")
+	if err == nil || !strings.Contains(err.Error(), "no contract deployed") {
+		t.Errorf("expected missing contract error, got: %v", err)
+	}
+}
+
+func TestConnectReportsNetworkErrors(t *testing.T) {
+	// A server for the wrong chain is never considered reachable
+	server := newTestRPCServer(t, 1, nil)
+	client := newTestDevnetClient(t, server.URL, 2)
+
+	err := client.Connect(context.Background())
+	var networkErr *NetworkError
+	if !errors.As(err, &networkErr) {
+		t.Fatalf("expected *NetworkError, got: %v", err)
+	}
+	if networkErr.Network != "Devnet" || networkErr.ChainID != 31337 {
+		t.Errorf("unexpected network error: %+v", networkErr)
+	}
+
+	if _, exists := client.NetworkErrors()["Devnet"]; !exists {
+		t.Error("expected the failure to be reported by NetworkErrors")
+	}
+}
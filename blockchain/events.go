@@ -0,0 +1,554 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/widnyana/idrx-go/contracts"
+	"github.com/widnyana/idrx-go/models"
+)
+
+// EventType identifies an IDRX contract event
+type EventType string
+
+// Events that can be subscribed to and decoded into models types
+const (
+	EventTransfer              EventType = "Transfer"
+	EventBurnBridge            EventType = "BurnBridge"
+	EventMintBridge            EventType = "MintBridge"
+	EventBurnWithAccountNumber EventType = "BurnWithAccountNumber"
+)
+
+// allEventTypes is the default subscription set
+var allEventTypes = []EventType{EventTransfer, EventBurnBridge, EventMintBridge, EventBurnWithAccountNumber}
+
+// maxLogRange is the largest block range requested in a single eth_getLogs call
+const maxLogRange = 2000
+
+// reorgWindow is how many blocks below the head are checked for reorganisations
+const reorgWindow = 64
+
+// Event is a decoded IDRX contract event
+type Event struct {
+	Type    EventType
+	ChainID uint64
+	Log     types.Log // Raw log, including block, transaction and log index
+	Removed bool      // The log was reverted by a chain reorganisation
+
+	// Data is the decoded event with decimals applied: *models.TransferEvent,
+	// *models.BurnBridgeEvent, *models.MintBridgeEvent or *models.BurnWithAccountNumberEvent
+	Data interface{}
+}
+
+// SubscribeOptions configures an event subscription
+type SubscribeOptions struct {
+	Events       []EventType   // Events to deliver (defaults to all supported events)
+	FromBlock    *uint64       // Replay events from this block before following new ones (defaults to the next block)
+	PollInterval time.Duration // Log polling interval (defaults to the network block time)
+	ForcePolling bool          // Poll eth_getLogs even if the endpoint supports subscriptions
+	MaxBackoff   time.Duration // Upper bound for the resubscribe delay after errors (defaults to 30s)
+}
+
+// EventSubscription delivers decoded events until it is unsubscribed or its context is cancelled.
+// Transient failures are reported on Err while the subscription reconnects; no events are
+// skipped because missed blocks are backfilled with eth_getLogs after every reconnect.
+//
+// When a chain reorganisation reverts a block, its events are delivered again with Removed
+// set and the block is rescanned, so events of the replacing block follow. Subscriptions
+// learn of reorgs from the node; polling compares the hashes of recently scanned blocks
+// with the chain, up to reorgWindow blocks deep.
+type EventSubscription struct {
+	events chan *Event
+	errs   chan error
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Events returns the channel of decoded events; it is closed when the subscription ends
+func (s *EventSubscription) Events() <-chan *Event {
+	return s.events
+}
+
+// Err returns a channel of non-fatal errors; it is closed when the subscription ends.
+// Errors are dropped if the channel is not drained.
+func (s *EventSubscription) Err() <-chan error {
+	return s.errs
+}
+
+// Unsubscribe stops the subscription and waits for it to shut down
+func (s *EventSubscription) Unsubscribe() {
+	s.cancel()
+	<-s.done
+}
+
+// SubscribeEvents follows IDRX contract events on a chain. It uses a log subscription when
+// the endpoint supports one (websocket or IPC) and falls back to polling eth_getLogs otherwise.
+func (c *Client) SubscribeEvents(ctx context.Context, chainID uint64, opts *SubscribeOptions) (*EventSubscription, error) {
+	if opts == nil {
+		opts = &SubscribeOptions{}
+	}
+
+	backend, err := c.GetBackend(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	networkConfig, _, exists := c.registry.GetByChainID(chainID)
+	if !exists {
+		return nil, fmt.Errorf("chain ID %d not supported", chainID)
+	}
+
+	query, err := eventQuery(networkConfig.ContractAddress, opts.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher := &eventWatcher{
+		client:       c,
+		chainID:      chainID,
+		backend:      backend,
+		query:        query,
+		pollInterval: opts.PollInterval,
+		maxBackoff:   opts.MaxBackoff,
+		pollOnly:     opts.ForcePolling,
+	}
+	if watcher.pollInterval <= 0 {
+		watcher.pollInterval = networkConfig.BlockTime
+	}
+	if watcher.maxBackoff <= 0 {
+		watcher.maxBackoff = 30 * time.Second
+	}
+	if opts.FromBlock != nil {
+		watcher.from = *opts.FromBlock
+		watcher.started = true
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	sub := &EventSubscription{
+		events: make(chan *Event, 64),
+		errs:   make(chan error, 8),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	watcher.sub = sub
+
+	go watcher.run(ctx)
+
+	return sub, nil
+}
+
+// DecodeEvent converts a raw IDRX log into an Event with decimals applied
+func (c *Client) DecodeEvent(chainID uint64, log types.Log) (*Event, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("log has no topics")
+	}
+
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse IDRX ABI: %w", err)
+	}
+	abiEvent, err := parsed.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, fmt.Errorf("unknown event topic %s", log.Topics[0].Hex())
+	}
+
+	filterer, err := contracts.NewIDRXFilterer(log.Address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event filterer: %w", err)
+	}
+
+	decimals := int32(c.Decimals(chainID))
+	event := &Event{Type: EventType(abiEvent.Name), ChainID: chainID, Log: log, Removed: log.Removed}
+
+	switch event.Type {
+	case EventTransfer:
+		raw, err := filterer.ParseTransfer(log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Transfer event: %w", err)
+		}
+		event.Data = &models.TransferEvent{
+			From:        raw.From,
+			To:          raw.To,
+			Value:       FromWei(raw.Value, decimals).Amount,
+			BlockNumber: log.BlockNumber,
+			TxHash:      log.TxHash,
+		}
+	case EventBurnBridge:
+		raw, err := filterer.ParseBurnBridge(log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse BurnBridge event: %w", err)
+		}
+		event.Data = &models.BurnBridgeEvent{
+			User:           raw.User,
+			Amount:         FromWei(raw.Amount, decimals).Amount,
+			AmountAfterCut: FromWei(raw.AmountAfterCut, decimals).Amount,
+			ToChain:        raw.ToChain.Uint64(),
+			BridgeNonce:    raw.BridgeNonce,
+			PlatformFee:    FromWei(raw.PlatformFee, decimals).Amount,
+			BlockNumber:    log.BlockNumber,
+			TxHash:         log.TxHash,
+		}
+	case EventMintBridge:
+		raw, err := filterer.ParseMintBridge(log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse MintBridge event: %w", err)
+		}
+		event.Data = &models.MintBridgeEvent{
+			User:            raw.User,
+			Amount:          FromWei(raw.Amount, decimals).Amount,
+			AmountAfterCut:  FromWei(raw.AmountAfterCut, decimals).Amount,
+			FromChain:       raw.FromChain.Uint64(),
+			FromBridgeNonce: raw.FromBridgeNonce,
+			PlatformFee:     FromWei(raw.PlatformFee, decimals).Amount,
+			BlockNumber:     log.BlockNumber,
+			TxHash:          log.TxHash,
+		}
+	case EventBurnWithAccountNumber:
+		raw, err := filterer.ParseBurnWithAccountNumber(log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse BurnWithAccountNumber event: %w", err)
+		}
+		event.Data = &models.BurnWithAccountNumberEvent{
+			User:                raw.User,
+			Amount:              FromWei(raw.Amount, decimals).Amount,
+			HashedAccountNumber: raw.HashedAccountNumber,
+			BlockNumber:         log.BlockNumber,
+			TxHash:              log.TxHash,
+		}
+	default:
+		return nil, fmt.Errorf("unsupported event %s", abiEvent.Name)
+	}
+
+	return event, nil
+}
+
+// eventQuery builds a log filter for the contract and the requested event types
+func eventQuery(contractAddress common.Address, eventTypes []EventType) (ethereum.FilterQuery, error) {
+	if len(eventTypes) == 0 {
+		eventTypes = allEventTypes
+	}
+
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		return ethereum.FilterQuery{}, fmt.Errorf("failed to parse IDRX ABI: %w", err)
+	}
+
+	topics := make([]common.Hash, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		abiEvent, exists := parsed.Events[string(eventType)]
+		if !exists {
+			return ethereum.FilterQuery{}, fmt.Errorf("unknown event type %s", eventType)
+		}
+		topics = append(topics, abiEvent.ID)
+	}
+
+	return ethereum.FilterQuery{
+		Addresses: []common.Address{contractAddress},
+		Topics:    [][]common.Hash{topics},
+	}, nil
+}
+
+// logPosition orders logs within the chain
+type logPosition struct {
+	block uint64
+	index uint
+}
+
+// after reports whether p comes strictly after other
+func (p logPosition) after(other logPosition) bool {
+	return p.block > other.block || (p.block == other.block && p.index > other.index)
+}
+
+// scannedBlock is a recently scanned block and the logs delivered from it
+type scannedBlock struct {
+	number uint64
+	hash   common.Hash
+	logs   []types.Log
+}
+
+// eventWatcher runs a subscription, switching between streaming and polling as needed
+type eventWatcher struct {
+	client       *Client
+	chainID      uint64
+	backend      Backend
+	query        ethereum.FilterQuery
+	pollInterval time.Duration
+	maxBackoff   time.Duration
+	pollOnly     bool
+	sub          *EventSubscription
+
+	started   bool           // from has been initialised
+	from      uint64         // first block not yet scanned with eth_getLogs
+	cursor    logPosition    // last delivered log
+	delivered bool           // cursor is valid
+	recent    []scannedBlock // ascending; checked against the chain for reorgs
+	backoff   time.Duration
+}
+
+// run drives the subscription until ctx is cancelled
+func (w *eventWatcher) run(ctx context.Context) {
+	defer close(w.sub.done)
+	defer close(w.sub.errs)
+	defer close(w.sub.events)
+
+	w.resetBackoff()
+	for {
+		var err error
+		if !w.started {
+			err = w.start(ctx)
+		} else if w.pollOnly {
+			err = w.poll(ctx)
+		} else {
+			err = w.stream(ctx)
+			if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+				w.pollOnly = true
+				continue
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			continue
+		}
+		w.report(err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.backoff):
+		}
+		w.backoff = min(w.backoff*2, w.maxBackoff)
+	}
+}
+
+// start begins following from the block after the current head
+func (w *eventWatcher) start(ctx context.Context) error {
+	head, err := w.backend.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get block number: %w", err)
+	}
+	w.from = head + 1
+	w.started = true
+	return nil
+}
+
+// stream follows a log subscription, backfilling blocks missed while disconnected
+func (w *eventWatcher) stream(ctx context.Context) error {
+	logs := make(chan types.Log, 128)
+	subscription, err := w.backend.SubscribeFilterLogs(ctx, w.query, logs)
+	if err != nil {
+		return err
+	}
+	defer subscription.Unsubscribe()
+
+	// Subscribe before backfilling so nothing between the two is lost; duplicates are skipped by cursor
+	if err := w.catchUp(ctx); err != nil {
+		return err
+	}
+	w.resetBackoff()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-subscription.Err():
+			if err == nil {
+				err = errors.New("log subscription closed")
+			}
+			return fmt.Errorf("log subscription dropped: %w", err)
+		case log := <-logs:
+			if err := w.deliver(ctx, log); err != nil {
+				return err
+			}
+			// Rescan the current block after a reconnect in case it was only partly streamed
+			if !log.Removed && log.BlockNumber > w.from {
+				w.from = log.BlockNumber
+			}
+		}
+	}
+}
+
+// poll fetches new logs on every tick
+func (w *eventWatcher) poll(ctx context.Context) error {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.catchUp(ctx); err != nil {
+			return err
+		}
+		w.resetBackoff()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// catchUp reverts reorganised blocks and delivers logs from the first unscanned block up to
+// the current head
+func (w *eventWatcher) catchUp(ctx context.Context) error {
+	if err := w.checkReorg(ctx); err != nil {
+		return err
+	}
+
+	head, err := w.backend.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get block number: %w", err)
+	}
+	scanned := w.from <= head
+
+	for w.from <= head {
+		to := min(w.from+maxLogRange-1, head)
+
+		query := w.query
+		query.FromBlock = new(big.Int).SetUint64(w.from)
+		query.ToBlock = new(big.Int).SetUint64(to)
+
+		logs, err := w.backend.FilterLogs(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to fetch logs for blocks %d-%d: %w", w.from, to, err)
+		}
+		for _, log := range logs {
+			if err := w.deliver(ctx, log); err != nil {
+				return err
+			}
+		}
+
+		w.from = to + 1
+	}
+
+	if scanned {
+		header, err := w.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(head))
+		if err != nil {
+			return fmt.Errorf("failed to get header of block %d: %w", head, err)
+		}
+		w.track(head, header.Hash(), nil)
+	}
+
+	return nil
+}
+
+// checkReorg compares the most recently scanned blocks with the chain and delivers the logs
+// of every block that was replaced as removed, newest first, until a block still matches
+func (w *eventWatcher) checkReorg(ctx context.Context) error {
+	for len(w.recent) > 0 {
+		last := w.recent[len(w.recent)-1]
+		header, err := w.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(last.number))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("failed to get header of block %d: %w", last.number, err)
+		}
+		if err == nil && header.Hash() == last.hash {
+			return nil
+		}
+
+		w.recent = w.recent[:len(w.recent)-1]
+		w.rewind(last.number)
+		for i := len(last.logs) - 1; i >= 0; i-- {
+			log := last.logs[i]
+			log.Removed = true
+			if err := w.deliver(ctx, log); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// track records a scanned block, or a log delivered from it, and forgets blocks more than
+// reorgWindow below it. Logs without a block hash cannot be checked and are not recorded; a
+// block already recorded keeps the hash of its logs.
+func (w *eventWatcher) track(number uint64, hash common.Hash, log *types.Log) {
+	if hash == (common.Hash{}) {
+		return
+	}
+	if n := len(w.recent); n > 0 && w.recent[n-1].number >= number {
+		last := &w.recent[n-1]
+		if last.number == number && log != nil && last.hash == hash {
+			last.logs = append(last.logs, *log)
+		}
+		return
+	}
+
+	block := scannedBlock{number: number, hash: hash}
+	if log != nil {
+		block.logs = []types.Log{*log}
+	}
+	w.recent = append(w.recent, block)
+	for w.recent[0].number+reorgWindow < number {
+		w.recent = w.recent[1:]
+	}
+}
+
+// rewind makes a reverted block and everything after it be scanned and delivered again
+func (w *eventWatcher) rewind(block uint64) {
+	w.from = min(w.from, block)
+	if w.delivered && block <= w.cursor.block {
+		if block == 0 {
+			w.delivered = false
+		} else {
+			w.cursor = logPosition{block: block - 1, index: math.MaxUint}
+		}
+	}
+	for len(w.recent) > 0 && w.recent[len(w.recent)-1].number >= block {
+		w.recent = w.recent[:len(w.recent)-1]
+	}
+}
+
+// deliver decodes a log and sends it to the subscriber, skipping logs already delivered.
+// A removed log means its block was reverted, so delivery rewinds to just before that block.
+func (w *eventWatcher) deliver(ctx context.Context, log types.Log) error {
+	position := logPosition{block: log.BlockNumber, index: log.Index}
+	if log.Removed {
+		w.rewind(log.BlockNumber)
+	} else {
+		if w.delivered && !position.after(w.cursor) {
+			return nil
+		}
+		w.cursor = position
+		w.delivered = true
+		w.track(log.BlockNumber, log.BlockHash, &log)
+	}
+
+	event, err := w.client.DecodeEvent(w.chainID, log)
+	if err != nil {
+		w.report(fmt.Errorf("failed to decode log %s:%d: %w", log.TxHash.Hex(), log.Index, err))
+		return nil
+	}
+
+	select {
+	case w.sub.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// report forwards a non-fatal error without blocking
+func (w *eventWatcher) report(err error) {
+	if err == nil {
+		return
+	}
+	select {
+	case w.sub.errs <- err:
+	default:
+	}
+}
+
+// resetBackoff restores the initial reconnect delay after a healthy connection
+func (w *eventWatcher) resetBackoff() {
+	w.backoff = min(time.Second, w.maxBackoff)
+}
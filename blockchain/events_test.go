@@ -0,0 +1,330 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/models"
+)
+
+var (
+	testContract = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	testUser     = common.HexToAddress("0x00000000000000000000000000000000000000cc")
+)
+
+func TestDecodeEventAppliesDecimals(t *testing.T) {
+	client, err := NewClient(&ClientConfig{PrivateKeyHex: testPrivateKey})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	log := burnBridgeLog(t, testContract, testUser, 12345, PolygonChainID, 3)
+	log.BlockNumber = 42
+
+	event, err := client.DecodeEvent(BaseChainID, *log)
+	if err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+	if event.Type != EventBurnBridge {
+		t.Errorf("expected %s, got %s", EventBurnBridge, event.Type)
+	}
+
+	burn, ok := event.Data.(*models.BurnBridgeEvent)
+	if !ok {
+		t.Fatalf("expected *models.BurnBridgeEvent, got %T", event.Data)
+	}
+	if !burn.Amount.Equal(decimal.RequireFromString("123.45")) {
+		t.Errorf("expected amount 123.45, got %s", burn.Amount)
+	}
+	if burn.ToChain != PolygonChainID || burn.BridgeNonce.Int64() != 3 || burn.BlockNumber != 42 {
+		t.Errorf("unexpected event: %+v", burn)
+	}
+
+	if _, err := client.DecodeEvent(BaseChainID, types.Log{Topics: []common.Hash{{0x01}}}); err == nil {
+		t.Error("expected error for unknown event topic")
+	}
+}
+
+func TestEventQueryRejectsUnknownEvent(t *testing.T) {
+	if _, err := eventQuery(testContract, []EventType{"Nope"}); err == nil {
+		t.Error("expected error for unknown event type")
+	}
+
+	query, err := eventQuery(testContract, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(query.Topics) != 1 || len(query.Topics[0]) != len(allEventTypes) {
+		t.Errorf("expected a topic per supported event, got %v", query.Topics)
+	}
+}
+
+func TestEventWatcherSkipsDeliveredLogs(t *testing.T) {
+	client, err := NewClient(&ClientConfig{PrivateKeyHex: testPrivateKey})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	watcher := &eventWatcher{
+		client:  client,
+		chainID: BaseChainID,
+		sub:     &EventSubscription{events: make(chan *Event, 8), errs: make(chan error, 8)},
+	}
+
+	first := burnBridgeLog(t, testContract, testUser, 100, PolygonChainID, 1)
+	first.BlockNumber, first.Index = 10, 2
+	second := burnBridgeLog(t, testContract, testUser, 100, PolygonChainID, 2)
+	second.BlockNumber, second.Index = 10, 3
+
+	// A reconnect replays block 10 from the start
+	for _, log := range []*types.Log{first, second, first, second} {
+		if err := watcher.deliver(context.Background(), *log); err != nil {
+			t.Fatalf("deliver failed: %v", err)
+		}
+	}
+
+	if len(watcher.sub.events) != 2 {
+		t.Errorf("expected 2 events after replay, got %d", len(watcher.sub.events))
+	}
+}
+
+func TestEventWatcherRewindsOnRemovedLog(t *testing.T) {
+	client, err := NewClient(&ClientConfig{PrivateKeyHex: testPrivateKey})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	watcher := &eventWatcher{
+		client:  client,
+		chainID: BaseChainID,
+		from:    11,
+		sub:     &EventSubscription{events: make(chan *Event, 8), errs: make(chan error, 8)},
+	}
+
+	original := burnBridgeLog(t, testContract, testUser, 100, PolygonChainID, 1)
+	original.BlockNumber, original.Index, original.BlockHash = 10, 2, common.Hash{0x0a}
+	removed := *original
+	removed.Removed = true
+	// The replacing block puts another log at the same position
+	replacement := burnBridgeLog(t, testContract, testUser, 200, PolygonChainID, 2)
+	replacement.BlockNumber, replacement.Index, replacement.BlockHash = 10, 2, common.Hash{0x0b}
+
+	for _, log := range []types.Log{*original, removed, *replacement} {
+		if err := watcher.deliver(context.Background(), log); err != nil {
+			t.Fatalf("deliver failed: %v", err)
+		}
+	}
+
+	if len(watcher.sub.events) != 3 {
+		t.Fatalf("expected the log, its removal and the replacement, got %d events", len(watcher.sub.events))
+	}
+	<-watcher.sub.events
+	if event := <-watcher.sub.events; !event.Removed {
+		t.Error("expected the second event to be removed")
+	}
+	if event := <-watcher.sub.events; event.Removed || event.Data.(*models.BurnBridgeEvent).BridgeNonce.Int64() != 2 {
+		t.Errorf("expected the replacement, got %+v", event)
+	}
+	if watcher.from != 10 {
+		t.Errorf("expected block 10 to be rescanned, got from %d", watcher.from)
+	}
+}
+
+func TestEventWatcherForgetsBlocksBelowReorgWindow(t *testing.T) {
+	client, err := NewClient(&ClientConfig{PrivateKeyHex: testPrivateKey})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	watcher := &eventWatcher{
+		client:  client,
+		chainID: BaseChainID,
+		sub:     &EventSubscription{events: make(chan *Event, 1), errs: make(chan error, 8)},
+	}
+
+	// Streamed logs track their blocks without a catch-up in between
+	for block := uint64(1); block <= 3*reorgWindow; block++ {
+		log := burnBridgeLog(t, testContract, testUser, 100, PolygonChainID, int64(block))
+		log.BlockNumber, log.BlockHash = block, common.BigToHash(new(big.Int).SetUint64(block))
+		if err := watcher.deliver(context.Background(), *log); err != nil {
+			t.Fatalf("deliver failed: %v", err)
+		}
+		<-watcher.sub.events
+	}
+
+	if len(watcher.recent) != reorgWindow+1 || watcher.recent[0].number != 2*reorgWindow {
+		t.Errorf("expected blocks %d-%d to be tracked, got %d from %d",
+			2*reorgWindow, 3*reorgWindow, len(watcher.recent), watcher.recent[0].number)
+	}
+}
+
+// forkingChain serves logs and headers of a chain whose blocks can be replaced
+type forkingChain struct {
+	Backend // unimplemented methods panic if called
+
+	mu   sync.Mutex
+	head uint64
+	fork map[uint64]byte // Fork of each replaced block; blocks not listed are on fork 0
+	logs map[uint64][]types.Log
+}
+
+func (c *forkingChain) header(number uint64) *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(number), Difficulty: big.NewInt(0), Extra: []byte{c.fork[number]}}
+}
+
+// replace swaps a block for one on another fork with the given logs
+func (c *forkingChain) replace(number uint64, fork byte, logs ...types.Log) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fork[number] = fork
+	c.logs[number] = nil
+	for _, log := range logs {
+		log.BlockNumber, log.BlockHash = number, c.header(number).Hash()
+		c.logs[number] = append(c.logs[number], log)
+	}
+}
+
+func (c *forkingChain) BlockNumber(_ context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.head, nil
+}
+
+func (c *forkingChain) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if number.Uint64() > c.head {
+		return nil, ethereum.NotFound
+	}
+	return c.header(number.Uint64()), nil
+}
+
+func (c *forkingChain) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var logs []types.Log
+	for number := query.FromBlock.Uint64(); number <= query.ToBlock.Uint64(); number++ {
+		logs = append(logs, c.logs[number]...)
+	}
+	return logs, nil
+}
+
+func TestEventWatcherDetectsReorgWhenPolling(t *testing.T) {
+	client, err := NewClient(&ClientConfig{PrivateKeyHex: testPrivateKey})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	chain := &forkingChain{head: 12, fork: map[uint64]byte{}, logs: map[uint64][]types.Log{}}
+	chain.replace(11, 0, *burnBridgeLog(t, testContract, testUser, 100, PolygonChainID, 1))
+
+	watcher := &eventWatcher{
+		client:  client,
+		chainID: BaseChainID,
+		backend: chain,
+		from:    10,
+		sub:     &EventSubscription{events: make(chan *Event, 8), errs: make(chan error, 8)},
+	}
+	if err := watcher.catchUp(context.Background()); err != nil {
+		t.Fatalf("catch up failed: %v", err)
+	}
+	if event := <-watcher.sub.events; event.Data.(*models.BurnBridgeEvent).BridgeNonce.Int64() != 1 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	// Blocks 11 and 12 are replaced; the new block 12 holds another burn
+	chain.replace(11, 1)
+	chain.replace(12, 1, *burnBridgeLog(t, testContract, testUser, 300, PolygonChainID, 3))
+	if err := watcher.catchUp(context.Background()); err != nil {
+		t.Fatalf("catch up failed: %v", err)
+	}
+
+	if len(watcher.sub.events) != 2 {
+		t.Fatalf("expected the removal and the new burn, got %d events", len(watcher.sub.events))
+	}
+	if event := <-watcher.sub.events; !event.Removed || event.Data.(*models.BurnBridgeEvent).BridgeNonce.Int64() != 1 {
+		t.Errorf("expected burn 1 removed, got %+v", event)
+	}
+	if event := <-watcher.sub.events; event.Removed || event.Data.(*models.BurnBridgeEvent).BridgeNonce.Int64() != 3 {
+		t.Errorf("expected burn 3, got %+v", event)
+	}
+
+	// Nothing changes on the next poll
+	if err := watcher.catchUp(context.Background()); err != nil {
+		t.Fatalf("catch up failed: %v", err)
+	}
+	if len(watcher.sub.events) != 0 {
+		t.Errorf("expected no further events, got %d", len(watcher.sub.events))
+	}
+}
+
+func TestSubscribeEventsFallsBackToPolling(t *testing.T) {
+	log := burnBridgeLog(t, testContract, testUser, 500, PolygonChainID, 9)
+	log.BlockNumber = 95
+	log.TxHash = common.HexToHash("0x01")
+
+	server := newTestRPCServer(t, 31337, map[string]rpcHandler{
+		"eth_getLogs": func(_ []json.RawMessage) (any, error) { return []*types.Log{log}, nil },
+		"eth_getBlockByNumber": func(_ []json.RawMessage) (any, error) {
+			return &types.Header{Number: big.NewInt(100), Difficulty: big.NewInt(0)}, nil
+		},
+	})
+	client := newTestDevnetClient(t, server.URL, 2)
+
+	from := uint64(90)
+	sub, err := client.SubscribeEvents(context.Background(), 31337, &SubscribeOptions{
+		FromBlock:    &from,
+		PollInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	select {
+	case event := <-sub.Events():
+		burn, ok := event.Data.(*models.BurnBridgeEvent)
+		if !ok {
+			t.Fatalf("expected *models.BurnBridgeEvent, got %T", event.Data)
+		}
+		if burn.BridgeNonce.Int64() != 9 || event.ChainID != 31337 {
+			t.Errorf("unexpected event: %+v", burn)
+		}
+	case err := <-sub.Err():
+		t.Fatalf("unexpected subscription error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+}
+
+func TestEventSubscriptionUnsubscribeClosesChannels(t *testing.T) {
+	server := newTestRPCServer(t, 31337, map[string]rpcHandler{
+		"eth_getLogs": func(_ []json.RawMessage) (any, error) { return nil, errors.New("boom") },
+	})
+	client := newTestDevnetClient(t, server.URL, 2)
+
+	sub, err := client.SubscribeEvents(context.Background(), 31337, &SubscribeOptions{ForcePolling: true})
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	sub.Unsubscribe()
+
+	if _, open := <-sub.Events(); open {
+		t.Error("expected events channel to be closed")
+	}
+}
@@ -0,0 +1,311 @@
+package hdwallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // BIP-32 fingerprints are defined with RIPEMD-160
+
+	"github.com/widnyana/idrx-go/blockchain/internal/base58"
+)
+
+// HardenedOffset is added to a child index to derive a hardened child
+const HardenedOffset uint32 = 0x80000000
+
+// Serialization versions of mainnet extended keys
+var (
+	versionPrivate = [4]byte{0x04, 0x88, 0xad, 0xe4} // xprv
+	versionPublic  = [4]byte{0x04, 0x88, 0xb2, 0x1e} // xpub
+)
+
+var (
+	// ErrHardenedFromPublic is returned when a hardened child is derived from a public key
+	ErrHardenedFromPublic = errors.New("cannot derive a hardened child from a public key")
+	// ErrInvalidChild is returned for the rare index whose derived key is invalid; use the next index
+	ErrInvalidChild = errors.New("derived key is invalid")
+)
+
+// ExtendedKey is a BIP-32 private or public key with its chain code
+type ExtendedKey struct {
+	key               []byte // 32-byte private key, or 33-byte compressed public key
+	chainCode         []byte
+	depth             uint8
+	parentFingerprint [4]byte
+	childNumber       uint32
+	private           bool
+}
+
+// NewMasterKey derives the BIP-32 master key from a seed of 16 to 64 bytes
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed must be 16 to 64 bytes, got %d", len(seed))
+	}
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	key := sum[:32]
+	if k := new(big.Int).SetBytes(key); k.Sign() == 0 || k.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, ErrInvalidChild
+	}
+
+	return &ExtendedKey{key: key, chainCode: sum[32:], private: true}, nil
+}
+
+// ParseExtendedKey decodes a Base58Check xprv or xpub string
+func ParseExtendedKey(encoded string) (*ExtendedKey, error) {
+	data, err := base58.Decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 82 {
+		return nil, fmt.Errorf("extended key must be 82 bytes, got %d", len(data))
+	}
+
+	payload, checksum := data[:78], data[78:]
+	if !bytes.Equal(doubleSHA256(payload)[:4], checksum) {
+		return nil, fmt.Errorf("extended key checksum mismatch")
+	}
+
+	k := &ExtendedKey{
+		depth:       payload[4],
+		childNumber: binary.BigEndian.Uint32(payload[9:13]),
+		chainCode:   append([]byte(nil), payload[13:45]...),
+	}
+	copy(k.parentFingerprint[:], payload[5:9])
+
+	keyData := payload[45:78]
+	switch {
+	case bytes.Equal(payload[:4], versionPrivate[:]):
+		if keyData[0] != 0 {
+			return nil, fmt.Errorf("invalid private key padding")
+		}
+		k.key = append([]byte(nil), keyData[1:]...)
+		k.private = true
+		if n := new(big.Int).SetBytes(k.key); n.Sign() == 0 || n.Cmp(crypto.S256().Params().N) >= 0 {
+			return nil, fmt.Errorf("private key out of range")
+		}
+	case bytes.Equal(payload[:4], versionPublic[:]):
+		if _, err := crypto.DecompressPubkey(keyData); err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		k.key = append([]byte(nil), keyData...)
+	default:
+		return nil, fmt.Errorf("unsupported extended key version %x", payload[:4])
+	}
+
+	if k.depth == 0 && (k.childNumber != 0 || k.parentFingerprint != [4]byte{}) {
+		return nil, fmt.Errorf("master key has a parent")
+	}
+	return k, nil
+}
+
+// String returns the Base58Check xprv or xpub encoding
+func (k *ExtendedKey) String() string {
+	payload := make([]byte, 0, 82)
+	if k.private {
+		payload = append(payload, versionPrivate[:]...)
+	} else {
+		payload = append(payload, versionPublic[:]...)
+	}
+	payload = append(payload, k.depth)
+	payload = append(payload, k.parentFingerprint[:]...)
+	payload = binary.BigEndian.AppendUint32(payload, k.childNumber)
+	payload = append(payload, k.chainCode...)
+	if k.private {
+		payload = append(payload, 0)
+	}
+	payload = append(payload, k.key...)
+	payload = append(payload, doubleSHA256(payload)[:4]...)
+	return base58.Encode(payload)
+}
+
+// IsPrivate reports whether the key can sign and derive hardened children
+func (k *ExtendedKey) IsPrivate() bool {
+	return k.private
+}
+
+// Depth is the number of derivation steps from the master key
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+// Neuter returns the public extended key, for watch-only derivation
+func (k *ExtendedKey) Neuter() *ExtendedKey {
+	if !k.private {
+		return k
+	}
+
+	public := *k
+	public.key = k.compressedPublicKey()
+	public.private = false
+	return &public
+}
+
+// Child derives the child at index; indexes from HardenedOffset are hardened
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	hardened := index >= HardenedOffset
+	if hardened && !k.private {
+		return nil, ErrHardenedFromPublic
+	}
+
+	data := make([]byte, 0, 37)
+	if hardened {
+		data = append(data, 0)
+		data = append(data, k.key...)
+	} else {
+		data = append(data, k.compressedPublicKey()...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	curve := crypto.S256()
+	n := curve.Params().N
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(n) >= 0 {
+		return nil, ErrInvalidChild
+	}
+
+	child := &ExtendedKey{
+		chainCode:   sum[32:],
+		depth:       k.depth + 1,
+		childNumber: index,
+		private:     k.private,
+	}
+	copy(child.parentFingerprint[:], hash160(k.compressedPublicKey())[:4])
+
+	if k.private {
+		key := il.Add(il, new(big.Int).SetBytes(k.key))
+		key.Mod(key, n)
+		if key.Sign() == 0 {
+			return nil, ErrInvalidChild
+		}
+		child.key = key.FillBytes(make([]byte, 32))
+		return child, nil
+	}
+
+	parent, err := crypto.DecompressPubkey(k.key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	x, y := curve.ScalarBaseMult(sum[:32])
+	x, y = curve.Add(x, y, parent.X, parent.Y)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, ErrInvalidChild
+	}
+	child.key = crypto.CompressPubkey(&ecdsa.PublicKey{Curve: curve, X: x, Y: y})
+	return child, nil
+}
+
+// Derive follows a path such as "m/44'/60'/0'/0" from this key. A path starting with
+// "m" must be applied to a master key; otherwise it is relative, e.g. "0/5".
+func (k *ExtendedKey) Derive(path string) (*ExtendedKey, error) {
+	indexes, absolute, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if absolute && k.depth != 0 {
+		return nil, fmt.Errorf("path %s starts at the master key, but the key is at depth %d", path, k.depth)
+	}
+
+	key := k
+	for _, index := range indexes {
+		if key, err = key.Child(index); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// PrivateKey returns the signing key; it fails for a public extended key
+func (k *ExtendedKey) PrivateKey() (*ecdsa.PrivateKey, error) {
+	if !k.private {
+		return nil, ErrWatchOnly
+	}
+	return crypto.ToECDSA(k.key)
+}
+
+// PublicKey returns the public key
+func (k *ExtendedKey) PublicKey() (*ecdsa.PublicKey, error) {
+	return crypto.DecompressPubkey(k.compressedPublicKey())
+}
+
+// Address returns the Ethereum address of the key
+func (k *ExtendedKey) Address() (common.Address, error) {
+	public, err := k.PublicKey()
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*public), nil
+}
+
+// compressedPublicKey returns the 33-byte public key
+func (k *ExtendedKey) compressedPublicKey() []byte {
+	if !k.private {
+		return k.key
+	}
+	x, y := crypto.S256().ScalarBaseMult(k.key)
+	return crypto.CompressPubkey(&ecdsa.PublicKey{Curve: crypto.S256(), X: x, Y: y})
+}
+
+// ParsePath parses a BIP-32 path such as "m/44'/60'/0'/0/0". Hardened steps are marked with
+// ' or h. absolute reports whether the path starts at the master key "m".
+func ParsePath(path string) (indexes []uint32, absolute bool, err error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, false, nil
+	}
+
+	parts := strings.Split(path, "/")
+	if parts[0] == "m" {
+		absolute = true
+		parts = parts[1:]
+	}
+
+	for _, part := range parts {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h")
+		if hardened {
+			part = part[:len(part)-1]
+		}
+
+		index, parseErr := strconv.ParseUint(part, 10, 32)
+		if parseErr != nil || uint32(index) >= HardenedOffset {
+			return nil, false, fmt.Errorf("invalid path segment %q in %s", part, path)
+		}
+		if hardened {
+			index += uint64(HardenedOffset)
+		}
+		indexes = append(indexes, uint32(index))
+	}
+	return indexes, absolute, nil
+}
+
+// hash160 is RIPEMD-160 of SHA-256
+func hash160(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	h := ripemd160.New()
+	h.Write(sum[:])
+	return h.Sum(nil)
+}
+
+// doubleSHA256 is the Base58Check checksum hash
+func doubleSHA256(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
@@ -0,0 +1,119 @@
+package hdwallet
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// englishWords is the BIP-39 English wordlist
+//
+//go:embed english.txt
+var englishWords string
+
+var (
+	wordlistOnce sync.Once
+	wordlist     []string
+	wordIndex    map[string]int
+)
+
+// words returns the wordlist and its reverse index
+func words() ([]string, map[string]int) {
+	wordlistOnce.Do(func() {
+		wordlist = strings.Fields(englishWords)
+		wordIndex = make(map[string]int, len(wordlist))
+		for i, word := range wordlist {
+			wordIndex[word] = i
+		}
+	})
+	return wordlist, wordIndex
+}
+
+// NewMnemonic generates a BIP-39 English mnemonic from fresh entropy of 128 to 256 bits
+// in steps of 32; 128 bits gives 12 words and 256 bits gives 24
+func NewMnemonic(bits int) (string, error) {
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", fmt.Errorf("entropy must be 128 to 256 bits in steps of 32, got %d", bits)
+	}
+
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", fmt.Errorf("failed to read entropy: %w", err)
+	}
+	return entropyToMnemonic(entropy), nil
+}
+
+// entropyToMnemonic encodes entropy followed by its SHA-256 checksum bits as words
+func entropyToMnemonic(entropy []byte) string {
+	list, _ := words()
+	checksumBits := len(entropy) / 4
+	checksum := sha256.Sum256(entropy)
+
+	value := new(big.Int).SetBytes(entropy)
+	value.Lsh(value, uint(checksumBits))
+	value.Or(value, big.NewInt(int64(checksum[0]>>(8-checksumBits))))
+
+	count := (len(entropy)*8 + checksumBits) / 11
+	mnemonic := make([]string, count)
+	mask := big.NewInt(2047)
+	for i := count - 1; i >= 0; i-- {
+		mnemonic[i] = list[new(big.Int).And(value, mask).Int64()]
+		value.Rsh(value, 11)
+	}
+	return strings.Join(mnemonic, " ")
+}
+
+// ValidateMnemonic checks that every word is in the BIP-39 English wordlist and that the
+// checksum matches, which catches most typos before they derive an unrelated wallet
+func ValidateMnemonic(mnemonic string) error {
+	_, index := words()
+	fields := strings.Fields(mnemonic)
+	if len(fields) < 12 || len(fields) > 24 || len(fields)%3 != 0 {
+		return fmt.Errorf("mnemonic must have 12, 15, 18, 21 or 24 words, got %d", len(fields))
+	}
+
+	value := new(big.Int)
+	for position, word := range fields {
+		i, ok := index[word]
+		if !ok {
+			return fmt.Errorf("word %d of the mnemonic is not in the BIP-39 English wordlist", position+1)
+		}
+		value.Lsh(value, 11)
+		value.Or(value, big.NewInt(int64(i)))
+	}
+
+	checksumBits := len(fields) / 3
+	entropyBytes := (len(fields)*11 - checksumBits) / 8
+	checksum := new(big.Int).And(value, big.NewInt(int64(1)<<checksumBits-1))
+	entropy := new(big.Int).Rsh(value, uint(checksumBits)).FillBytes(make([]byte, entropyBytes))
+
+	expected := sha256.Sum256(entropy)
+	if checksum.Int64() != int64(expected[0]>>(8-checksumBits)) {
+		return fmt.Errorf("mnemonic checksum mismatch")
+	}
+	return nil
+}
+
+// SeedFromMnemonic validates a mnemonic and stretches it with an optional passphrase into
+// the 64-byte BIP-39 seed
+func SeedFromMnemonic(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+
+	normalized := strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	seed, err := pbkdf2.Key(sha512.New, normalized, []byte(salt), 2048, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive seed: %w", err)
+	}
+	return seed, nil
+}
@@ -0,0 +1,189 @@
+package hdwallet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/blockchain/offline"
+)
+
+// SweepStatus is the outcome of sweeping one address
+type SweepStatus string
+
+// Sweep outcomes
+const (
+	SweepSent      SweepStatus = "sent"      // Transfer broadcast, not waited for
+	SweepConfirmed SweepStatus = "confirmed" // Transfer mined with the required confirmations
+	SweepSkipped   SweepStatus = "skipped"   // Nothing to sweep, or the address cannot send
+	SweepFailed    SweepStatus = "failed"    // Transfer could not be sent or did not succeed
+)
+
+// SweepConfig controls a Sweeper
+type SweepConfig struct {
+	Treasury common.Address // Receives every swept balance
+
+	// MinAmount leaves smaller balances in place, where the gas would cost more than they are
+	// worth (defaults to sweeping any positive balance)
+	MinAmount *blockchain.TokenAmount
+
+	// Confirmations to wait for after broadcasting; 0 returns as soon as transfers are sent
+	Confirmations uint64
+}
+
+// SweepResult is the outcome for one derived address
+type SweepResult struct {
+	Index   uint32
+	Address common.Address
+	Amount  *blockchain.TokenAmount // Balance found on the address
+	Status  SweepStatus
+	TxHash  *common.Hash
+	Reason  string // Why the address was skipped or failed
+}
+
+// Sweeper moves the IDRX balances of derived addresses into a treasury
+type Sweeper struct {
+	wallet *Wallet
+	client *blockchain.Client
+	config SweepConfig
+}
+
+// NewSweeper creates a sweeper. The client only reads and broadcasts, so it can be created
+// without a private key; every transfer is signed with the key of its derived address.
+func NewSweeper(wallet *Wallet, client *blockchain.Client, config SweepConfig) (*Sweeper, error) {
+	if wallet.WatchOnly() {
+		return nil, ErrWatchOnly
+	}
+	if config.Treasury == (common.Address{}) {
+		return nil, fmt.Errorf("sweep treasury address is required")
+	}
+	return &Sweeper{wallet: wallet, client: client, config: config}, nil
+}
+
+// Sweep transfers the whole IDRX balance of each address index to the treasury. Balances and
+// blacklist statuses are read in one batch. An address without enough native currency for
+// gas is skipped, since gas has to be funded separately. Errors for single addresses are
+// reported in their results; the returned error means the sweep could not run.
+func (s *Sweeper) Sweep(ctx context.Context, chainID uint64, indexes []uint32) ([]SweepResult, error) {
+	results := make([]SweepResult, len(indexes))
+	addresses := make([]common.Address, len(indexes))
+	for i, index := range indexes {
+		address, err := s.wallet.Address(index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive address %d: %w", index, err)
+		}
+		addresses[i] = address
+		results[i] = SweepResult{Index: index, Address: address}
+	}
+
+	balances, err := s.client.BalancesOf(ctx, chainID, addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read balances: %w", err)
+	}
+	blacklisted, err := s.client.BlacklistStatuses(ctx, chainID, append([]common.Address{s.config.Treasury}, addresses...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check blacklist: %w", err)
+	}
+	if blacklisted[s.config.Treasury] {
+		return nil, fmt.Errorf("treasury %s: %w", s.config.Treasury.Hex(), blockchain.ErrBlacklisted)
+	}
+
+	for i := range results {
+		result := &results[i]
+		result.Amount = balances[result.Address]
+
+		switch {
+		case result.Amount == nil || !result.Amount.Amount.IsPositive():
+			result.Status, result.Reason = SweepSkipped, "no balance"
+		case s.config.MinAmount != nil && result.Amount.Amount.LessThan(s.config.MinAmount.Amount):
+			result.Status, result.Reason = SweepSkipped, fmt.Sprintf("balance is below %s", s.config.MinAmount)
+		case blacklisted[result.Address]:
+			result.Status, result.Reason = SweepSkipped, "address is blacklisted"
+		default:
+			s.send(ctx, chainID, result)
+		}
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+	}
+
+	if s.config.Confirmations > 0 {
+		for i := range results {
+			if results[i].Status == SweepSent {
+				s.confirm(ctx, chainID, &results[i])
+			}
+		}
+	}
+
+	return results, ctx.Err()
+}
+
+// send builds, signs and broadcasts the transfer of one address's balance
+func (s *Sweeper) send(ctx context.Context, chainID uint64, result *SweepResult) {
+	finish := func(status SweepStatus, reason string) {
+		result.Status, result.Reason = status, reason
+	}
+
+	envelope, err := offline.BuildTransfer(ctx, s.client, chainID, result.Address, s.config.Treasury, result.Amount)
+	if err != nil {
+		finish(SweepFailed, err.Error())
+		return
+	}
+
+	tx, err := envelope.Transaction()
+	if err != nil {
+		finish(SweepFailed, err.Error())
+		return
+	}
+	backend, err := s.client.GetBackend(chainID)
+	if err != nil {
+		finish(SweepFailed, err.Error())
+		return
+	}
+	native, err := backend.BalanceAt(ctx, result.Address, nil)
+	if err != nil {
+		finish(SweepFailed, fmt.Sprintf("failed to get native balance: %v", err))
+		return
+	}
+	if native.Cmp(tx.Cost()) < 0 {
+		finish(SweepSkipped, fmt.Sprintf("needs %s wei for gas, has %s", tx.Cost(), native))
+		return
+	}
+
+	key, err := s.wallet.PrivateKey(result.Index)
+	if err != nil {
+		finish(SweepFailed, err.Error())
+		return
+	}
+	if err := offline.Sign(envelope, key, s.client.Registry()); err != nil {
+		finish(SweepFailed, err.Error())
+		return
+	}
+
+	signed, err := offline.Broadcast(ctx, s.client, envelope)
+	if err != nil {
+		finish(SweepFailed, err.Error())
+		return
+	}
+
+	hash := signed.Hash()
+	result.Status = SweepSent
+	result.TxHash = &hash
+}
+
+// confirm waits for a sent transfer
+func (s *Sweeper) confirm(ctx context.Context, chainID uint64, result *SweepResult) {
+	receipt, err := s.client.WaitForConfirmations(ctx, chainID, *result.TxHash, s.config.Confirmations)
+	switch {
+	case ctx.Err() != nil:
+	case err != nil:
+		result.Status, result.Reason = SweepFailed, fmt.Sprintf("not confirmed: %v", err)
+	case receipt.Status != types.ReceiptStatusSuccessful:
+		result.Status, result.Reason = SweepFailed, "transaction reverted"
+	default:
+		result.Status = SweepConfirmed
+	}
+}
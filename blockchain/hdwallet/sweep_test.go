@@ -0,0 +1,180 @@
+package hdwallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/internal/backendtest"
+)
+
+var treasury = common.HexToAddress("0x00000000000000000000000000000000000000ee")
+
+// chain holds token and native balances of derived addresses and records sent transfers
+type chain struct {
+	backendtest.Chain
+
+	tokens      map[common.Address]int64
+	native      map[common.Address]int64
+	blacklisted map[common.Address]bool
+	sent        []*types.Transaction
+}
+
+func (c *chain) SuggestGasTipCap(_ context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (c *chain) CodeAt(_ context.Context, _ common.Address, _ *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *chain) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	return 0, nil
+}
+
+func (c *chain) BalanceAt(_ context.Context, account common.Address, _ *big.Int) (*big.Int, error) {
+	return big.NewInt(c.native[account]), nil
+}
+
+func (c *chain) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, args, err := backendtest.DecodeCall(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	account := args[0].(common.Address)
+	switch method.Name {
+	case "balanceOf":
+		return method.Outputs.Pack(big.NewInt(c.tokens[account]))
+	case "getBlackListStatus":
+		return method.Outputs.Pack(c.blacklisted[account])
+	}
+	return nil, errors.New("unexpected call " + method.Name)
+}
+
+func (c *chain) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	c.sent = append(c.sent, tx)
+	return nil
+}
+
+// capGasPrice caps the gas price like the built-in networks do, which makes transfers legacy
+// transactions
+func capGasPrice(maxGasPrice uint64) func(*blockchain.NetworkConfig) {
+	return func(config *blockchain.NetworkConfig) { config.MaxGasPrice = maxGasPrice }
+}
+
+func TestSweep(t *testing.T) {
+	wallet, err := NewFromMnemonic(testMnemonic, "", "")
+	if err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+	addresses, err := wallet.Addresses(0, 5)
+	if err != nil {
+		t.Fatalf("failed to derive addresses: %v", err)
+	}
+
+	// Gas costs at most 3000000 * (2*10 + 1) wei on the test chain
+	funded := int64(3000000 * 21)
+	backend := &chain{
+		Chain: backendtest.Chain{Head: 50, BaseFee: big.NewInt(10)},
+		tokens: map[common.Address]int64{
+			addresses[0]: 150000, // swept
+			addresses[2]: 50,     // below the minimum
+			addresses[3]: 70000,  // no gas
+			addresses[4]: 90000,  // blacklisted
+		},
+		native:      map[common.Address]int64{addresses[0]: funded, addresses[3]: funded - 1, addresses[4]: funded},
+		blacklisted: map[common.Address]bool{addresses[4]: true},
+	}
+
+	client := backendtest.NewClient(t, backend)
+
+	sweeper, err := NewSweeper(wallet, client, SweepConfig{
+		Treasury:  treasury,
+		MinAmount: &blockchain.TokenAmount{Amount: decimal.NewFromInt(1), Decimals: 2},
+	})
+	if err != nil {
+		t.Fatalf("failed to create sweeper: %v", err)
+	}
+
+	results, err := sweeper.Sweep(context.Background(), backendtest.ChainID, []uint32{0, 1, 2, 3, 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []SweepStatus{SweepSent, SweepSkipped, SweepSkipped, SweepSkipped, SweepSkipped}
+	for i, result := range results {
+		if result.Status != expected[i] {
+			t.Errorf("index %d: expected %s, got %s (%s)", result.Index, expected[i], result.Status, result.Reason)
+		}
+	}
+	if results[0].Amount.String() != "1500.00" {
+		t.Errorf("unexpected swept amount %s", results[0].Amount)
+	}
+
+	if len(backend.sent) != 1 {
+		t.Fatalf("expected one transfer, got %d", len(backend.sent))
+	}
+	sent := backend.sent[0]
+	sender, err := types.Sender(types.LatestSignerForChainID(sent.ChainId()), sent)
+	if err != nil || sender != addresses[0] {
+		t.Errorf("expected the transfer signed by %s, got %s (%v)", addresses[0].Hex(), sender.Hex(), err)
+	}
+	if results[0].TxHash == nil || *results[0].TxHash != sent.Hash() {
+		t.Errorf("unexpected result hash %v", results[0].TxHash)
+	}
+
+	watcher, err := NewFromExtendedKey(wallet.ExtendedPublicKey(), "")
+	if err != nil {
+		t.Fatalf("failed to open watch-only wallet: %v", err)
+	}
+	if _, err := NewSweeper(watcher, client, SweepConfig{Treasury: treasury}); !errors.Is(err, ErrWatchOnly) {
+		t.Errorf("expected ErrWatchOnly, got: %v", err)
+	}
+}
+
+func TestSweepLegacyGas(t *testing.T) {
+	wallet, err := NewFromMnemonic(testMnemonic, "", "")
+	if err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+	address, err := wallet.Address(0)
+	if err != nil {
+		t.Fatalf("failed to derive address: %v", err)
+	}
+
+	const gasPrice = 30
+	backend := &chain{
+		Chain:  backendtest.Chain{Head: 50, BaseFee: big.NewInt(10)},
+		tokens: map[common.Address]int64{address: 150000},
+		native: map[common.Address]int64{address: 3000000 * gasPrice},
+	}
+	sweeper, err := NewSweeper(wallet, backendtest.NewClient(t, backend, capGasPrice(gasPrice)), SweepConfig{Treasury: treasury})
+	if err != nil {
+		t.Fatalf("failed to create sweeper: %v", err)
+	}
+
+	results, err := sweeper.Sweep(context.Background(), backendtest.ChainID, []uint32{0})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Status != SweepSent {
+		t.Fatalf("expected the balance swept, got %s (%s)", results[0].Status, results[0].Reason)
+	}
+
+	sent := backend.sent[0]
+	if sent.Type() != types.LegacyTxType || sent.ChainId().Uint64() != backendtest.ChainID {
+		t.Errorf("expected an EIP-155 legacy transaction, got type %d on chain %s", sent.Type(), sent.ChainId())
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(sent.ChainId()), sent)
+	if err != nil || sender != address {
+		t.Errorf("expected the transfer signed by %s, got %s (%v)", address.Hex(), sender.Hex(), err)
+	}
+}
@@ -0,0 +1,137 @@
+// Package hdwallet derives per-customer IDRX receiving addresses from one BIP-39 mnemonic or
+// BIP-32 extended key along the BIP-44 Ethereum path, and sweeps their balances into a
+// treasury. A wallet opened from an xpub derives the same addresses without holding any
+// private key, for watch-only services such as deposit monitors.
+package hdwallet
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/widnyana/idrx-go/blockchain"
+)
+
+// DefaultPath is the BIP-44 external chain of the first Ethereum account; address i is at
+// DefaultPath/i, matching MetaMask and hardware wallets
+const DefaultPath = "m/44'/60'/0'/0"
+
+// ErrWatchOnly is returned when a private key is requested from a wallet opened from an xpub
+var ErrWatchOnly = errors.New("wallet is watch-only")
+
+// Wallet derives indexed addresses and signing keys below a base extended key
+type Wallet struct {
+	base *ExtendedKey
+}
+
+// NewFromMnemonic opens the wallet of a BIP-39 mnemonic and optional passphrase. Addresses
+// are derived below path, which defaults to DefaultPath.
+func NewFromMnemonic(mnemonic, passphrase, path string) (*Wallet, error) {
+	seed, err := SeedFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromSeed(seed, path)
+}
+
+// NewFromSeed opens the wallet of a BIP-32 seed; path defaults to DefaultPath
+func NewFromSeed(seed []byte, path string) (*Wallet, error) {
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		path = DefaultPath
+	}
+	return newWallet(master, path)
+}
+
+// NewFromExtendedKey opens a wallet from an xprv or xpub. Path is applied to the key first:
+// use DefaultPath for a master xprv, or "" when the key is already the base, such as the
+// xpub exported with ExtendedPublicKey. An xpub gives a watch-only wallet.
+func NewFromExtendedKey(key, path string) (*Wallet, error) {
+	extended, err := ParseExtendedKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return newWallet(extended, path)
+}
+
+// newWallet derives the base key of a wallet
+func newWallet(key *ExtendedKey, path string) (*Wallet, error) {
+	base, err := key.Derive(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive %s: %w", path, err)
+	}
+	return &Wallet{base: base}, nil
+}
+
+// WatchOnly reports whether the wallet can only derive addresses
+func (w *Wallet) WatchOnly() bool {
+	return !w.base.IsPrivate()
+}
+
+// ExtendedPublicKey returns the xpub of the base key. NewFromExtendedKey(xpub, "") opens a
+// watch-only wallet with the same addresses.
+func (w *Wallet) ExtendedPublicKey() string {
+	return w.base.Neuter().String()
+}
+
+// Key returns the extended key of address index. Indexes are not hardened, so watch-only
+// wallets can derive them.
+func (w *Wallet) Key(index uint32) (*ExtendedKey, error) {
+	if index >= HardenedOffset {
+		return nil, fmt.Errorf("address index %d is out of range", index)
+	}
+	return w.base.Child(index)
+}
+
+// Address returns the address at index
+func (w *Wallet) Address(index uint32) (common.Address, error) {
+	key, err := w.Key(index)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return key.Address()
+}
+
+// Addresses returns count consecutive addresses starting at index start
+func (w *Wallet) Addresses(start, count uint32) ([]common.Address, error) {
+	addresses := make([]common.Address, 0, count)
+	for index := start; index < start+count; index++ {
+		address, err := w.Address(index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive address %d: %w", index, err)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+// PrivateKey returns the signing key of address index
+func (w *Wallet) PrivateKey(index uint32) (*ecdsa.PrivateKey, error) {
+	if w.WatchOnly() {
+		return nil, ErrWatchOnly
+	}
+
+	key, err := w.Key(index)
+	if err != nil {
+		return nil, err
+	}
+	return key.PrivateKey()
+}
+
+// NewClient creates a blockchain client that signs as address index. Config supplies the
+// networks, registry and other settings; its private key fields are ignored.
+func (w *Wallet) NewClient(index uint32, config blockchain.ClientConfig) (*blockchain.Client, error) {
+	key, err := w.PrivateKey(index)
+	if err != nil {
+		return nil, err
+	}
+
+	config.PrivateKeyHex = ""
+	config.PrivateKey = key
+	return blockchain.NewClient(&config)
+}
@@ -0,0 +1,168 @@
+package hdwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// BIP-32 test vector 1
+func TestExtendedKeyVector(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		t.Fatalf("failed to create master key: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		xprv string
+		xpub string
+	}{
+		{
+			path: "m",
+			xprv: "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+			xpub: "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+		},
+		{
+			path: "m/0'",
+			xprv: "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+			xpub: "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+		},
+		{
+			path: "m/0'/1",
+			xprv: "xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+			xpub: "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			key, err := master.Derive(tt.path)
+			if err != nil {
+				t.Fatalf("failed to derive: %v", err)
+			}
+			if key.String() != tt.xprv {
+				t.Errorf("unexpected xprv %s", key.String())
+			}
+			if key.Neuter().String() != tt.xpub {
+				t.Errorf("unexpected xpub %s", key.Neuter().String())
+			}
+
+			parsed, err := ParseExtendedKey(tt.xpub)
+			if err != nil {
+				t.Fatalf("failed to parse xpub: %v", err)
+			}
+			if parsed.String() != tt.xpub || parsed.IsPrivate() {
+				t.Errorf("xpub did not round-trip: %s", parsed.String())
+			}
+		})
+	}
+
+	// Public derivation of a non-hardened child matches private derivation
+	parent, _ := master.Derive("m/0'")
+	child, err := parent.Neuter().Child(1)
+	if err != nil {
+		t.Fatalf("failed to derive public child: %v", err)
+	}
+	if child.String() != tests[2].xpub {
+		t.Errorf("public child %s differs from the vector", child.String())
+	}
+
+	if _, err := parent.Neuter().Child(HardenedOffset); !errors.Is(err, ErrHardenedFromPublic) {
+		t.Errorf("expected ErrHardenedFromPublic, got: %v", err)
+	}
+}
+
+func TestMnemonicWallet(t *testing.T) {
+	wallet, err := NewFromMnemonic(testMnemonic, "", "")
+	if err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+
+	// Well-known first address of the all-"abandon" mnemonic on m/44'/60'/0'/0/0
+	address, err := wallet.Address(0)
+	if err != nil {
+		t.Fatalf("failed to derive address: %v", err)
+	}
+	if expected := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94"); address != expected {
+		t.Errorf("expected %s, got %s", expected.Hex(), address.Hex())
+	}
+
+	key, err := wallet.PrivateKey(0)
+	if err != nil {
+		t.Fatalf("failed to derive key: %v", err)
+	}
+	if hex.EncodeToString(key.D.FillBytes(make([]byte, 32))) != "1ab42cc412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b727" {
+		t.Errorf("unexpected private key")
+	}
+
+	// A watch-only wallet from the exported xpub derives the same addresses
+	watcher, err := NewFromExtendedKey(wallet.ExtendedPublicKey(), "")
+	if err != nil {
+		t.Fatalf("failed to open watch-only wallet: %v", err)
+	}
+	if !watcher.WatchOnly() {
+		t.Error("expected a watch-only wallet")
+	}
+
+	expected, err := wallet.Addresses(0, 5)
+	if err != nil {
+		t.Fatalf("failed to derive addresses: %v", err)
+	}
+	watched, err := watcher.Addresses(0, 5)
+	if err != nil {
+		t.Fatalf("failed to derive watched addresses: %v", err)
+	}
+	for i := range expected {
+		if watched[i] != expected[i] {
+			t.Errorf("address %d: expected %s, got %s", i, expected[i].Hex(), watched[i].Hex())
+		}
+	}
+
+	if _, err := watcher.PrivateKey(0); !errors.Is(err, ErrWatchOnly) {
+		t.Errorf("expected ErrWatchOnly, got: %v", err)
+	}
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	generated, err := NewMnemonic(256)
+	if err != nil {
+		t.Fatalf("failed to generate mnemonic: %v", err)
+	}
+	if words := strings.Fields(generated); len(words) != 24 {
+		t.Errorf("expected 24 words, got %d", len(words))
+	}
+
+	tests := []struct {
+		name     string
+		mnemonic string
+		expected string
+	}{
+		{"valid", testMnemonic, ""},
+		{"generated", generated, ""},
+		{"bad checksum", strings.Replace(testMnemonic, "about", "abandon", 1), "checksum mismatch"},
+		{"unknown word", strings.Replace(testMnemonic, "about", "abuot", 1), "word 12"},
+		{"too short", "abandon about", "must have 12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMnemonic(tt.mnemonic)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got: %v", tt.expected, err)
+			}
+		})
+	}
+}
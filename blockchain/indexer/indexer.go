@@ -0,0 +1,378 @@
+// Package indexer backfills and follows every IDRX contract event across chains,
+// persisting per-chain progress so indexing resumes where it stopped.
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/contracts"
+	"github.com/widnyana/idrx-go/models"
+)
+
+// ErrReorgTooDeep is returned when a reorganisation reaches past every block hash the checkpoint retains
+var ErrReorgTooDeep = errors.New("chain reorganisation deeper than tracked block history")
+
+// Handler receives indexed events. The checkpoint only advances after HandleEvents returns nil,
+// so events may be redelivered after a restart and handlers should be idempotent (EventLog.ID
+// is unique per log).
+type Handler interface {
+	// HandleEvents receives the events of one block range, in chain order
+	HandleEvents(ctx context.Context, chainID uint64, events []*models.EventLog) error
+	// Rollback discards every event above block after a chain reorganisation
+	Rollback(ctx context.Context, chainID uint64, block uint64) error
+}
+
+// Config controls indexing behaviour
+type Config struct {
+	Handler       Handler           // Receives events and rollbacks (required)
+	Store         CheckpointStore   // Checkpoint persistence (defaults to an in-memory store)
+	Chains        []uint64          // Chains to index (defaults to every enabled network)
+	StartBlocks   map[uint64]uint64 // First block to index per chain without a checkpoint (defaults to 0)
+	Confirmations uint64            // Blocks behind head that are considered final (defaults to 12)
+	PollInterval  time.Duration     // Delay between head checks when following (defaults to the network block time)
+	OnError       func(chainID uint64, err error)
+
+	InitialChunkSize uint64 // Blocks per eth_getLogs request to start with (defaults to 2000)
+	MinChunkSize     uint64 // Smallest range tried before giving up (defaults to 1)
+	MaxChunkSize     uint64 // Largest range the chunk size grows to (defaults to 10000)
+	ReorgHistory     int    // Block hashes kept in the checkpoint for reorg detection (defaults to 128)
+}
+
+// withDefaults fills unset config values
+func (c Config) withDefaults() Config {
+	if c.Store == nil {
+		c.Store = NewMemoryStore()
+	}
+	if c.Confirmations == 0 {
+		c.Confirmations = 12
+	}
+	if c.InitialChunkSize == 0 {
+		c.InitialChunkSize = 2000
+	}
+	if c.MinChunkSize == 0 {
+		c.MinChunkSize = 1
+	}
+	if c.MaxChunkSize == 0 {
+		c.MaxChunkSize = 10000
+	}
+	if c.ReorgHistory <= 0 {
+		c.ReorgHistory = 128
+	}
+	return c
+}
+
+// Indexer backfills IDRX events from a start block and follows the chain head
+type Indexer struct {
+	client *blockchain.Client
+	config Config
+
+	mu     sync.Mutex
+	chains map[uint64]*chainState
+}
+
+// chainState serialises indexing of one chain and tracks its adaptive chunk size
+type chainState struct {
+	mu    sync.Mutex
+	chunk uint64
+}
+
+// New creates an indexer for the client's networks
+func New(client *blockchain.Client, config *Config) (*Indexer, error) {
+	if config == nil || config.Handler == nil {
+		return nil, fmt.Errorf("indexer handler is required")
+	}
+
+	cfg := config.withDefaults()
+	if cfg.MinChunkSize > cfg.MaxChunkSize {
+		return nil, fmt.Errorf("min chunk size %d exceeds max chunk size %d", cfg.MinChunkSize, cfg.MaxChunkSize)
+	}
+	cfg.InitialChunkSize = min(max(cfg.InitialChunkSize, cfg.MinChunkSize), cfg.MaxChunkSize)
+
+	if len(cfg.Chains) == 0 {
+		for _, name := range client.EnabledNetworks() {
+			networkConfig, _ := client.Registry().Get(name)
+			cfg.Chains = append(cfg.Chains, networkConfig.ChainID)
+		}
+	}
+	for _, chainID := range cfg.Chains {
+		if !client.Registry().IsChainSupported(chainID) {
+			return nil, fmt.Errorf("chain ID %d not supported", chainID)
+		}
+	}
+
+	return &Indexer{
+		client: client,
+		config: cfg,
+		chains: make(map[uint64]*chainState),
+	}, nil
+}
+
+// Run indexes every configured chain until ctx is cancelled. Errors on one chain are
+// reported through OnError and retried without affecting the others.
+func (ix *Indexer) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, chainID := range ix.config.Chains {
+		wg.Add(1)
+		go func(chainID uint64) {
+			defer wg.Done()
+			ix.follow(ctx, chainID)
+		}(chainID)
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// follow repeatedly syncs one chain, waiting a block time between rounds
+func (ix *Indexer) follow(ctx context.Context, chainID uint64) {
+	interval := ix.config.PollInterval
+	if interval <= 0 {
+		networkConfig, _, _ := ix.client.Registry().GetByChainID(chainID)
+		interval = networkConfig.BlockTime
+	}
+
+	for {
+		if _, err := ix.Sync(ctx, chainID); err != nil && ctx.Err() == nil && ix.config.OnError != nil {
+			ix.config.OnError(chainID, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Sync indexes a chain up to the latest confirmed block and returns the checkpoint.
+// It is safe to call concurrently; calls for the same chain are serialised.
+func (ix *Indexer) Sync(ctx context.Context, chainID uint64) (*Checkpoint, error) {
+	state := ix.chainState(chainID)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	networkConfig, _, exists := ix.client.Registry().GetByChainID(chainID)
+	if !exists {
+		return nil, fmt.Errorf("chain ID %d not supported", chainID)
+	}
+
+	backend, err := ix.client.GetBackend(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint, err := ix.config.Store.Load(ctx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if checkpoint == nil {
+		checkpoint = &Checkpoint{ChainID: chainID, NextBlock: ix.config.StartBlocks[chainID]}
+	}
+
+	if err := ix.rollbackReorg(ctx, backend, checkpoint); err != nil {
+		return checkpoint, err
+	}
+
+	head, err := backend.BlockNumber(ctx)
+	if err != nil {
+		return checkpoint, fmt.Errorf("failed to get block number: %w", err)
+	}
+	if head < ix.config.Confirmations {
+		return checkpoint, nil
+	}
+	safe := head - ix.config.Confirmations
+
+	for checkpoint.NextBlock <= safe {
+		if err := ctx.Err(); err != nil {
+			return checkpoint, err
+		}
+
+		from := checkpoint.NextBlock
+		to := min(from+state.chunk-1, safe)
+
+		logs, err := backend.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{networkConfig.ContractAddress},
+		})
+		if err != nil {
+			// Providers cap results per request; retry the range in smaller pieces
+			if state.chunk > ix.config.MinChunkSize && ctx.Err() == nil {
+				state.chunk = max(state.chunk/2, ix.config.MinChunkSize)
+				continue
+			}
+			return checkpoint, fmt.Errorf("failed to fetch logs for blocks %d-%d: %w", from, to, err)
+		}
+		state.chunk = min(state.chunk*2, ix.config.MaxChunkSize)
+
+		header, err := backend.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+		if err != nil {
+			return checkpoint, fmt.Errorf("failed to get header %d: %w", to, err)
+		}
+
+		events, err := ix.convert(ctx, backend, chainID, logs, header)
+		if err != nil {
+			return checkpoint, err
+		}
+		if len(events) > 0 {
+			if err := ix.config.Handler.HandleEvents(ctx, chainID, events); err != nil {
+				return checkpoint, fmt.Errorf("handler failed for blocks %d-%d: %w", from, to, err)
+			}
+		}
+
+		checkpoint.NextBlock = to + 1
+		checkpoint.Recent = append(checkpoint.Recent, BlockRef{Number: to, Hash: header.Hash()})
+		if excess := len(checkpoint.Recent) - ix.config.ReorgHistory; excess > 0 {
+			checkpoint.Recent = checkpoint.Recent[excess:]
+		}
+		checkpoint.UpdatedAt = time.Now()
+
+		if err := ix.config.Store.Save(ctx, checkpoint); err != nil {
+			return checkpoint, fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+	}
+
+	return checkpoint, nil
+}
+
+// chainState returns the per-chain state, creating it on first use
+func (ix *Indexer) chainState(chainID uint64) *chainState {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	state, exists := ix.chains[chainID]
+	if !exists {
+		state = &chainState{chunk: ix.config.InitialChunkSize}
+		ix.chains[chainID] = state
+	}
+	return state
+}
+
+// rollbackReorg compares the checkpoint's block hashes with the chain and rewinds to the
+// newest block that is still canonical, telling the handler to drop everything above it
+func (ix *Indexer) rollbackReorg(ctx context.Context, backend blockchain.Backend, checkpoint *Checkpoint) error {
+	for i := len(checkpoint.Recent) - 1; i >= 0; i-- {
+		ref := checkpoint.Recent[i]
+
+		header, err := backend.HeaderByNumber(ctx, new(big.Int).SetUint64(ref.Number))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("failed to get header %d: %w", ref.Number, err)
+		}
+		if err == nil && header.Hash() == ref.Hash {
+			if i == len(checkpoint.Recent)-1 {
+				return nil
+			}
+
+			if err := ix.config.Handler.Rollback(ctx, checkpoint.ChainID, ref.Number); err != nil {
+				return fmt.Errorf("handler rollback to block %d failed: %w", ref.Number, err)
+			}
+			checkpoint.NextBlock = ref.Number + 1
+			checkpoint.Recent = checkpoint.Recent[:i+1]
+			checkpoint.UpdatedAt = time.Now()
+			if err := ix.config.Store.Save(ctx, checkpoint); err != nil {
+				return fmt.Errorf("failed to save checkpoint: %w", err)
+			}
+			return nil
+		}
+	}
+
+	if len(checkpoint.Recent) > 0 {
+		return fmt.Errorf("%w: no match in the last %d indexed ranges", ErrReorgTooDeep, len(checkpoint.Recent))
+	}
+	return nil
+}
+
+// convert turns raw logs into EventLog records stamped with their block time
+func (ix *Indexer) convert(
+	ctx context.Context,
+	backend blockchain.Backend,
+	chainID uint64,
+	logs []types.Log,
+	last *types.Header,
+) ([]*models.EventLog, error) {
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse IDRX ABI: %w", err)
+	}
+
+	blockTimes := map[uint64]time.Time{last.Number.Uint64(): time.Unix(int64(last.Time), 0).UTC()}
+
+	events := make([]*models.EventLog, 0, len(logs))
+	for _, log := range logs {
+		createdAt, exists := blockTimes[log.BlockNumber]
+		if !exists {
+			header, err := backend.HeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get header %d: %w", log.BlockNumber, err)
+			}
+			createdAt = time.Unix(int64(header.Time), 0).UTC()
+			blockTimes[log.BlockNumber] = createdAt
+		}
+
+		event := &models.EventLog{
+			ID:          fmt.Sprintf("%d:%s:%d", chainID, log.TxHash.Hex(), log.Index),
+			ChainID:     chainID,
+			Address:     log.Address,
+			Topics:      log.Topics,
+			Data:        log.Data,
+			BlockNumber: log.BlockNumber,
+			TxHash:      log.TxHash,
+			TxIndex:     log.TxIndex,
+			LogIndex:    log.Index,
+			CreatedAt:   createdAt,
+		}
+		event.EventName, event.ParsedData = ix.decode(parsed, chainID, log)
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// decode names a log and decodes its arguments. The events with models types are decoded
+// into them with decimals applied; every other IDRX event becomes a map keyed by argument name.
+func (ix *Indexer) decode(parsed *abi.ABI, chainID uint64, log types.Log) (string, interface{}) {
+	if len(log.Topics) == 0 {
+		return "", nil
+	}
+	abiEvent, err := parsed.EventByID(log.Topics[0])
+	if err != nil {
+		return "", nil
+	}
+
+	switch blockchain.EventType(abiEvent.Name) {
+	case blockchain.EventTransfer, blockchain.EventBurnBridge, blockchain.EventMintBridge, blockchain.EventBurnWithAccountNumber:
+		if event, err := ix.client.DecodeEvent(chainID, log); err == nil {
+			return abiEvent.Name, event.Data
+		}
+		return abiEvent.Name, nil
+	}
+
+	args := make(map[string]interface{})
+	if err := abiEvent.Inputs.UnpackIntoMap(args, log.Data); err != nil {
+		return abiEvent.Name, nil
+	}
+
+	var indexed abi.Arguments
+	for _, input := range abiEvent.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexed, log.Topics[1:]); err != nil {
+		return abiEvent.Name, nil
+	}
+
+	return abiEvent.Name, args
+}
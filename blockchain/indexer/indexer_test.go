@@ -0,0 +1,262 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/contracts"
+	"github.com/widnyana/idrx-go/internal/backendtest"
+	"github.com/widnyana/idrx-go/models"
+)
+
+// fakeChain is an in-memory chain whose blocks can be replaced to simulate reorgs
+type fakeChain struct {
+	backendtest.Chain
+
+	mu       sync.Mutex
+	headers  []*types.Header
+	logs     map[uint64][]types.Log
+	maxRange uint64 // FilterLogs fails for larger ranges when non-zero
+	requests int
+}
+
+func newFakeChain(length int) *fakeChain {
+	chain := &fakeChain{logs: make(map[uint64][]types.Log)}
+	chain.extend(length, 0)
+	return chain
+}
+
+// extend appends blocks; fork distinguishes block hashes on different branches
+func (f *fakeChain) extend(count int, fork byte) {
+	for i := 0; i < count; i++ {
+		number := uint64(len(f.headers))
+		f.headers = append(f.headers, &types.Header{
+			Number: new(big.Int).SetUint64(number),
+			Time:   1700000000 + number*2,
+			Extra:  []byte{fork},
+		})
+	}
+}
+
+// reorg replaces every block from number onwards with a new branch of the same length
+func (f *fakeChain) reorg(number uint64, fork byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	length := len(f.headers)
+	f.headers = f.headers[:number]
+	for block := range f.logs {
+		if block >= number {
+			delete(f.logs, block)
+		}
+	}
+	f.extend(length-int(number), fork)
+}
+
+// addTransfer records a Transfer log in a block
+func (f *fakeChain) addTransfer(t *testing.T, block uint64, value int64) {
+	t.Helper()
+
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+	data, err := parsed.Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(value))
+	if err != nil {
+		t.Fatalf("failed to pack Transfer: %v", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logs[block] = append(f.logs[block], types.Log{
+		Address: backendtest.Contract,
+		Topics: []common.Hash{
+			parsed.Events["Transfer"].ID,
+			common.BytesToHash(common.HexToAddress("0x01").Bytes()),
+			common.BytesToHash(common.HexToAddress("0x02").Bytes()),
+		},
+		Data:        data,
+		BlockNumber: block,
+		TxHash:      common.BigToHash(big.NewInt(int64(block))),
+		Index:       uint(len(f.logs[block])),
+	})
+}
+
+func (f *fakeChain) BlockNumber(_ context.Context) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return uint64(len(f.headers) - 1), nil
+}
+
+func (f *fakeChain) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if number.Uint64() >= uint64(len(f.headers)) {
+		return nil, ethereum.NotFound
+	}
+	return f.headers[number.Uint64()], nil
+}
+
+func (f *fakeChain) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests++
+	from, to := q.FromBlock.Uint64(), q.ToBlock.Uint64()
+	if f.maxRange > 0 && to-from+1 > f.maxRange {
+		return nil, errors.New("query returned more than 10000 results")
+	}
+
+	var logs []types.Log
+	for block := from; block <= to; block++ {
+		logs = append(logs, f.logs[block]...)
+	}
+	return logs, nil
+}
+
+// recorder is a Handler that keeps events in memory and honours rollbacks
+type recorder struct {
+	events    []*models.EventLog
+	rollbacks []uint64
+}
+
+func (r *recorder) HandleEvents(_ context.Context, _ uint64, events []*models.EventLog) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *recorder) Rollback(_ context.Context, _ uint64, block uint64) error {
+	r.rollbacks = append(r.rollbacks, block)
+	kept := r.events[:0]
+	for _, event := range r.events {
+		if event.BlockNumber <= block {
+			kept = append(kept, event)
+		}
+	}
+	r.events = kept
+	return nil
+}
+
+func newTestIndexer(t *testing.T, chain *fakeChain, config *Config) *Indexer {
+	t.Helper()
+
+	indexer, err := New(backendtest.NewSigningClient(t, chain), config)
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	return indexer
+}
+
+func TestSyncBackfillsWithAdaptiveChunks(t *testing.T) {
+	chain := newFakeChain(1000)
+	chain.maxRange = 100
+	chain.addTransfer(t, 10, 1250)
+	chain.addTransfer(t, 500, 300)
+	chain.addTransfer(t, 995, 1) // not yet confirmed
+
+	handler := &recorder{}
+	indexer := newTestIndexer(t, chain, &Config{Handler: handler, Confirmations: 10, InitialChunkSize: 400})
+
+	checkpoint, err := indexer.Sync(context.Background(), backendtest.ChainID)
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	if checkpoint.NextBlock != 990 {
+		t.Errorf("expected to index up to head minus confirmations, next block %d", checkpoint.NextBlock)
+	}
+	if len(handler.events) != 2 {
+		t.Fatalf("expected 2 confirmed events, got %d", len(handler.events))
+	}
+
+	transfer, ok := handler.events[0].ParsedData.(*models.TransferEvent)
+	if !ok {
+		t.Fatalf("expected *models.TransferEvent, got %T", handler.events[0].ParsedData)
+	}
+	if !transfer.Value.Equal(decimal.RequireFromString("12.5")) {
+		t.Errorf("expected value 12.5, got %s", transfer.Value)
+	}
+	if handler.events[0].EventName != "Transfer" || handler.events[0].CreatedAt.Unix() != 1700000020 {
+		t.Errorf("unexpected event record: %+v", handler.events[0])
+	}
+}
+
+func TestSyncResumesFromCheckpoint(t *testing.T) {
+	chain := newFakeChain(100)
+	chain.addTransfer(t, 50, 1)
+
+	store := NewFileStore(filepath.Join(t.TempDir(), "checkpoints.json"))
+	handler := &recorder{}
+	indexer := newTestIndexer(t, chain, &Config{Handler: handler, Store: store, Confirmations: 1})
+
+	if _, err := indexer.Sync(context.Background(), backendtest.ChainID); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	chain.mu.Lock()
+	chain.extend(20, 0)
+	chain.mu.Unlock()
+	chain.addTransfer(t, 110, 2)
+
+	// A new indexer with the same store picks up where the first stopped
+	resumed := &recorder{}
+	indexer = newTestIndexer(t, chain, &Config{Handler: resumed, Store: store, Confirmations: 1})
+	checkpoint, err := indexer.Sync(context.Background(), backendtest.ChainID)
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+
+	if len(resumed.events) != 1 || resumed.events[0].BlockNumber != 110 {
+		t.Errorf("expected only the new event after resuming, got %d events", len(resumed.events))
+	}
+	if checkpoint.NextBlock != 119 {
+		t.Errorf("expected next block 119, got %d", checkpoint.NextBlock)
+	}
+}
+
+func TestSyncRollsBackReorg(t *testing.T) {
+	chain := newFakeChain(100)
+	handler := &recorder{}
+	indexer := newTestIndexer(t, chain, &Config{Handler: handler, Confirmations: 1, InitialChunkSize: 10})
+
+	chain.addTransfer(t, 95, 1)
+	if _, err := indexer.Sync(context.Background(), backendtest.ChainID); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if len(handler.events) != 1 {
+		t.Fatalf("expected 1 event before reorg, got %d", len(handler.events))
+	}
+
+	// Blocks from 92 onwards are replaced; the event moves to block 97
+	chain.reorg(92, 1)
+	chain.addTransfer(t, 97, 1)
+
+	if _, err := indexer.Sync(context.Background(), backendtest.ChainID); err != nil {
+		t.Fatalf("sync after reorg failed: %v", err)
+	}
+
+	if len(handler.rollbacks) != 1 {
+		t.Fatalf("expected one rollback, got %v", handler.rollbacks)
+	}
+	if handler.rollbacks[0] >= 92 {
+		t.Errorf("expected rollback below the fork point, got %d", handler.rollbacks[0])
+	}
+	if len(handler.events) != 1 || handler.events[0].BlockNumber != 97 {
+		t.Errorf("expected only the re-orged event to remain, got %+v", handler.events)
+	}
+}
+
+func TestNewRequiresHandler(t *testing.T) {
+	if _, err := New(nil, &Config{}); err == nil {
+		t.Error("expected error without handler")
+	}
+}
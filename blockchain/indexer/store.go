@@ -0,0 +1,145 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/widnyana/idrx-go/blockchain/internal/atomicfile"
+)
+
+// BlockRef identifies an indexed block by number and hash
+type BlockRef struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// Checkpoint records indexing progress for one chain
+type Checkpoint struct {
+	ChainID   uint64     `json:"chainId"`
+	NextBlock uint64     `json:"nextBlock"` // First block that has not been indexed yet
+	Recent    []BlockRef `json:"recent"`    // Hashes of recently indexed blocks, oldest first, used to detect reorgs
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// clone returns a deep copy of the checkpoint
+func (cp *Checkpoint) clone() *Checkpoint {
+	copied := *cp
+	copied.Recent = append([]BlockRef(nil), cp.Recent...)
+	return &copied
+}
+
+// CheckpointStore persists per-chain checkpoints. Implementations must be safe for
+// concurrent use; a SQL-backed store only needs these two operations.
+type CheckpointStore interface {
+	// Load returns the checkpoint for a chain, or nil if the chain has never been indexed
+	Load(ctx context.Context, chainID uint64) (*Checkpoint, error)
+	// Save stores the checkpoint, replacing any previous one for the chain
+	Save(ctx context.Context, checkpoint *Checkpoint) error
+}
+
+// MemoryStore keeps checkpoints in memory; progress is lost when the process exits
+type MemoryStore struct {
+	mu          sync.Mutex
+	checkpoints map[uint64]*Checkpoint
+}
+
+// NewMemoryStore creates an empty in-memory checkpoint store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{checkpoints: make(map[uint64]*Checkpoint)}
+}
+
+// Load implements CheckpointStore
+func (s *MemoryStore) Load(_ context.Context, chainID uint64) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoint, exists := s.checkpoints[chainID]
+	if !exists {
+		return nil, nil
+	}
+	return checkpoint.clone(), nil
+}
+
+// Save implements CheckpointStore
+func (s *MemoryStore) Save(_ context.Context, checkpoint *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoints[checkpoint.ChainID] = checkpoint.clone()
+	return nil
+}
+
+// FileStore keeps checkpoints for all chains in a single JSON file, replaced atomically on
+// every save
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileStore creates a store backed by the JSON file at path; the file is created on first save
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load implements CheckpointStore
+func (s *FileStore) Load(_ context.Context, chainID uint64) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	return checkpoints[strconv.FormatUint(chainID, 10)], nil
+}
+
+// Save implements CheckpointStore
+func (s *FileStore) Save(_ context.Context, checkpoint *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[strconv.FormatUint(checkpoint.ChainID, 10)] = checkpoint
+
+	data, err := json.MarshalIndent(checkpoints, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoints: %w", err)
+	}
+
+	if err := atomicfile.Write(s.path, data); err != nil {
+		return fmt.Errorf("failed to save checkpoints: %w", err)
+	}
+
+	return nil
+}
+
+// read loads every checkpoint from disk; a missing file is an empty store
+func (s *FileStore) read() (map[string]*Checkpoint, error) {
+	checkpoints := make(map[string]*Checkpoint)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint file %s: %w", s.path, err)
+	}
+
+	return checkpoints, nil
+}
@@ -0,0 +1,376 @@
+// Package simbackend deploys the IDRX contract behind an ERC1967 proxy on go-ethereum's
+// simulated backend and wires it into a blockchain.Client, so contract operations can be
+// tested without live RPC endpoints. It is internal so the simulated node, and the
+// dependencies go.mod lists for it, are only linked into test binaries.
+//
+// The generated bindings in the contracts package are built from ABIs only. Deployment
+// needs the compiled implementation and proxy bytecode, either regenerated into the
+// bindings with `make generate-contracts` (abis/idrx.bin and abis/proxy.bin) or passed
+// in through Config. New returns ErrBytecodeUnavailable when neither is present.
+package simbackend
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+// NetworkName is the registry name of the simulated network
+const NetworkName = "Simulated"
+
+// ChainID is the chain ID used by go-ethereum's simulated backend
+const ChainID = 1337
+
+// ErrBytecodeUnavailable is returned when no contract bytecode is available for deployment
+var ErrBytecodeUnavailable = errors.New("IDRX implementation or ERC1967 proxy bytecode not available")
+
+// Config controls how the harness deploys the contracts
+type Config struct {
+	ImplementationBytecode string // Hex-encoded IDRX implementation bytecode (defaults to contracts.IDRXMetaData.Bin)
+	ProxyBytecode          string // Hex-encoded ERC1967Proxy bytecode (defaults to contracts.ERC1967ProxyMetaData.Bin)
+	Decimals               uint8  // Decimals configured for the simulated network; must match the contract
+}
+
+// Harness is a simulated chain with IDRX deployed and a blockchain.Client connected to it
+type Harness struct {
+	Backend  *simulated.Backend
+	Client   *blockchain.Client // signs with UserKey
+	Registry *blockchain.Registry
+
+	AdminKey *ecdsa.PrivateKey // holds DEFAULT_ADMIN_ROLE and every role granted by initialize
+	Admin    common.Address
+	UserKey  *ecdsa.PrivateKey // the key used by Client
+	User     common.Address
+
+	Contract              *contracts.IDRX // bound to the proxy through the simulated backend
+	ProxyAddress          common.Address
+	ImplementationAddress common.Address
+}
+
+// fundingAmount is the ether balance given to every harness account
+var fundingAmount = new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))
+
+// New starts a simulated chain, deploys the IDRX implementation behind an ERC1967 proxy
+// initialised by the admin account, and returns a harness with a client for the user account.
+// The client simulates writes, so a write that would revert fails before it is sent.
+func New(config *Config) (*Harness, error) {
+	if config == nil {
+		config = &Config{Decimals: 2}
+	}
+
+	implementation := common.FromHex(firstNonEmpty(config.ImplementationBytecode, contracts.IDRXMetaData.Bin))
+	proxy := common.FromHex(firstNonEmpty(config.ProxyBytecode, contracts.ERC1967ProxyMetaData.Bin))
+	if len(implementation) == 0 || len(proxy) == 0 {
+		return nil, ErrBytecodeUnavailable
+	}
+
+	adminKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate admin key: %w", err)
+	}
+	userKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate user key: %w", err)
+	}
+
+	h := &Harness{
+		AdminKey: adminKey,
+		Admin:    crypto.PubkeyToAddress(adminKey.PublicKey),
+		UserKey:  userKey,
+		User:     crypto.PubkeyToAddress(userKey.PublicKey),
+	}
+
+	h.Backend = simulated.NewBackend(types.GenesisAlloc{
+		h.Admin: {Balance: fundingAmount},
+		h.User:  {Balance: fundingAmount},
+	})
+
+	if err := h.deploy(implementation, proxy); err != nil {
+		_ = h.Backend.Close()
+		return nil, err
+	}
+
+	h.Registry = blockchain.NewRegistry()
+	network := blockchain.DevnetConfig(ChainID, "http://127.0.0.1:8545", h.ProxyAddress, config.Decimals)
+	network.Name = "Simulated Backend"
+	if err := h.Registry.Register(NetworkName, network); err != nil {
+		_ = h.Backend.Close()
+		return nil, err
+	}
+
+	h.Client, err = blockchain.NewClient(&blockchain.ClientConfig{
+		PrivateKeyHex: common.Bytes2Hex(crypto.FromECDSA(userKey)),
+		Registry:      h.Registry,
+		Backends:      map[string]blockchain.Backend{NetworkName: h.Backend.Client()},
+		// Writes use the network's fixed gas limit, so reverts surface only through simulation
+		SimulateWrites: true,
+	})
+	if err != nil {
+		_ = h.Backend.Close()
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return h, nil
+}
+
+// deploy deploys the implementation and a proxy that calls initialize() on it
+func (h *Harness) deploy(implementationCode, proxyCode []byte) error {
+	idrxABI, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		return fmt.Errorf("failed to parse IDRX ABI: %w", err)
+	}
+	proxyABI, err := contracts.ERC1967ProxyMetaData.GetAbi()
+	if err != nil {
+		return fmt.Errorf("failed to parse proxy ABI: %w", err)
+	}
+
+	admin, err := h.adminTransactor()
+	if err != nil {
+		return err
+	}
+	client := h.Backend.Client()
+
+	implementation, tx, _, err := bind.DeployContract(admin, *idrxABI, implementationCode, client)
+	if err != nil {
+		return fmt.Errorf("failed to deploy IDRX implementation: %w", err)
+	}
+	if err := h.commitAndCheck(tx); err != nil {
+		return fmt.Errorf("IDRX implementation deployment failed: %w", err)
+	}
+
+	initData, err := idrxABI.Pack("initialize")
+	if err != nil {
+		return fmt.Errorf("failed to encode initialize call: %w", err)
+	}
+
+	proxy, tx, _, err := bind.DeployContract(admin, *proxyABI, proxyCode, client, implementation, initData)
+	if err != nil {
+		return fmt.Errorf("failed to deploy ERC1967 proxy: %w", err)
+	}
+	if err := h.commitAndCheck(tx); err != nil {
+		return fmt.Errorf("ERC1967 proxy deployment failed: %w", err)
+	}
+
+	h.ImplementationAddress = implementation
+	h.ProxyAddress = proxy
+	h.Contract, err = contracts.NewIDRX(proxy, client)
+	if err != nil {
+		return fmt.Errorf("failed to bind IDRX proxy: %w", err)
+	}
+
+	return nil
+}
+
+// firstNonEmpty returns the first hex string with content
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimPrefix(value, "0x") != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Close shuts down the client and the simulated chain
+func (h *Harness) Close() {
+	h.Client.Close()
+	_ = h.Backend.Close()
+}
+
+// Commit seals a block containing all pending transactions
+func (h *Harness) Commit() {
+	h.Backend.Commit()
+}
+
+// ChainID returns the simulated chain ID as a uint64, matching blockchain.Client method signatures
+func (h *Harness) ChainID() uint64 {
+	return ChainID
+}
+
+// NewAccount creates and funds a fresh account on the simulated chain
+func (h *Harness) NewAccount() (*ecdsa.PrivateKey, common.Address, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to generate key: %w", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	admin, err := h.adminTransactor()
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	client := h.Backend.Client()
+	nonce, err := client.PendingNonceAt(context.Background(), h.Admin)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	tip, err := client.SuggestGasTipCap(context.Background())
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to suggest gas tip: %w", err)
+	}
+	head, err := client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to get head: %w", err)
+	}
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(ChainID),
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: new(big.Int).Add(tip, new(big.Int).Mul(head.BaseFee, big.NewInt(2))),
+		Gas:       params.TxGas,
+		To:        &address,
+		Value:     new(big.Int).Div(fundingAmount, big.NewInt(10)),
+	})
+	signed, err := admin.Signer(h.Admin, tx)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to sign funding transaction: %w", err)
+	}
+	if err := client.SendTransaction(context.Background(), signed); err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to fund account: %w", err)
+	}
+	if err := h.commitAndCheck(signed); err != nil {
+		return nil, common.Address{}, err
+	}
+
+	return key, address, nil
+}
+
+// Role returns the identifier of a role by its contract constant name, e.g. "MINTER_ROLE"
+func (h *Harness) Role(name string) ([32]byte, error) {
+	opts := &bind.CallOpts{Context: context.Background()}
+	switch name {
+	case "DEFAULT_ADMIN_ROLE":
+		return h.Contract.DEFAULTADMINROLE(opts)
+	case "MINTER_ROLE":
+		return h.Contract.MINTERROLE(opts)
+	case "PAUSER_ROLE":
+		return h.Contract.PAUSERROLE(opts)
+	case "BLACKLIST_ROLE":
+		return h.Contract.BLACKLISTROLE(opts)
+	case "PLATFORM_FEE_SETTER_ROLE":
+		return h.Contract.PLATFORMFEESETTERROLE(opts)
+	case "UPGRADER_ROLE":
+		return h.Contract.UPGRADERROLE(opts)
+	default:
+		return [32]byte{}, fmt.Errorf("unknown role %s", name)
+	}
+}
+
+// GrantRole grants a role (by constant name) to account from the admin account
+func (h *Harness) GrantRole(name string, account common.Address) error {
+	role, err := h.Role(name)
+	if err != nil {
+		return err
+	}
+	return h.asAdmin(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return h.Contract.GrantRole(opts, role, account)
+	})
+}
+
+// GrantMinterRole grants MINTER_ROLE to account
+func (h *Harness) GrantMinterRole(account common.Address) error {
+	return h.GrantRole("MINTER_ROLE", account)
+}
+
+// Mint mints raw token units to an address from the admin account
+func (h *Harness) Mint(to common.Address, amount *big.Int) error {
+	return h.asAdmin(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return h.Contract.Mint(opts, to, amount)
+	})
+}
+
+// Blacklist adds account to the contract blacklist
+func (h *Harness) Blacklist(account common.Address) error {
+	return h.asAdmin(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return h.Contract.AddBlackList(opts, account)
+	})
+}
+
+// RemoveFromBlacklist removes account from the contract blacklist
+func (h *Harness) RemoveFromBlacklist(account common.Address) error {
+	return h.asAdmin(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return h.Contract.RemoveBlackList(opts, account)
+	})
+}
+
+// Pause pauses the contract
+func (h *Harness) Pause() error {
+	return h.asAdmin(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return h.Contract.Pause(opts)
+	})
+}
+
+// Unpause unpauses the contract
+func (h *Harness) Unpause() error {
+	return h.asAdmin(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return h.Contract.Unpause(opts)
+	})
+}
+
+// SetPlatformFeeInfo sets the platform fee recipient and bridge fees in basis points
+func (h *Harness) SetPlatformFeeInfo(recipient common.Address, burnBridgeFee, mintBridgeFee uint64) error {
+	return h.asAdmin(func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return h.Contract.SetPlatformFeeInfo(opts, recipient, burnBridgeFee, mintBridgeFee)
+	})
+}
+
+// Receipt commits pending transactions and returns the receipt of tx
+func (h *Harness) Receipt(tx *types.Transaction) (*types.Receipt, error) {
+	h.Commit()
+	receipt, err := h.Backend.Client().TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt: %w", err)
+	}
+	return receipt, nil
+}
+
+// asAdmin sends a transaction from the admin account and waits for it to succeed
+func (h *Harness) asAdmin(send func(*bind.TransactOpts) (*types.Transaction, error)) error {
+	admin, err := h.adminTransactor()
+	if err != nil {
+		return err
+	}
+
+	tx, err := send(admin)
+	if err != nil {
+		return err
+	}
+
+	return h.commitAndCheck(tx)
+}
+
+// adminTransactor creates transaction options for the admin account
+func (h *Harness) adminTransactor() (*bind.TransactOpts, error) {
+	opts, err := bind.NewKeyedTransactorWithChainID(h.AdminKey, big.NewInt(ChainID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin transactor: %w", err)
+	}
+	return opts, nil
+}
+
+// commitAndCheck mines pending transactions and verifies tx succeeded
+func (h *Harness) commitAndCheck(tx *types.Transaction) error {
+	receipt, err := h.Receipt(tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction %s reverted", tx.Hash().Hex())
+	}
+	return nil
+}
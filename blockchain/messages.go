@@ -0,0 +1,167 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// ErrSignerMismatch is returned when a signature recovers to an address other than the expected one
+var ErrSignerMismatch = errors.New("signature is not from the expected address")
+
+// SignMessage signs a message the way personal_sign does (EIP-191 version 0x45). The
+// 65-byte signature uses a recovery ID of 27 or 28, as wallets return it.
+func (c *Client) SignMessage(message []byte) ([]byte, error) {
+	return c.signHash(accounts.TextHash(message))
+}
+
+// VerifyMessage recovers the address that signed a personal_sign message
+func VerifyMessage(message, signature []byte) (common.Address, error) {
+	return recoverSigner(accounts.TextHash(message), signature)
+}
+
+// SignTypedData signs EIP-712 typed data the way eth_signTypedData_v4 does
+func (c *Client) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	return c.signHash(hash)
+}
+
+// VerifyTypedData recovers the address that signed EIP-712 typed data
+func VerifyTypedData(typedData apitypes.TypedData, signature []byte) (common.Address, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	return recoverSigner(hash, signature)
+}
+
+// signHash signs a 32-byte digest with the client's key
+func (c *Client) signHash(hash []byte) ([]byte, error) {
+	if c.privateKey == nil {
+		return nil, ErrReadOnly
+	}
+
+	signature, err := crypto.Sign(hash, c.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+// recoverSigner returns the address behind a signature over hash. Recovery IDs of 0/1 and
+// 27/28 are both accepted, since wallets differ. Signatures with s in the upper half of the
+// curve order are rejected, as EIP-2 requires, so a signature has only one valid form.
+func recoverSigner(hash, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature must be %d bytes, got %d", crypto.SignatureLength, len(signature))
+	}
+
+	normalized := append([]byte(nil), signature...)
+	if normalized[crypto.RecoveryIDOffset] >= 27 {
+		normalized[crypto.RecoveryIDOffset] -= 27
+	}
+	if normalized[crypto.RecoveryIDOffset] > 1 {
+		return common.Address{}, fmt.Errorf("invalid signature recovery ID %d", signature[crypto.RecoveryIDOffset])
+	}
+	r, s := new(big.Int).SetBytes(normalized[:32]), new(big.Int).SetBytes(normalized[32:64])
+	if !crypto.ValidateSignatureValues(normalized[crypto.RecoveryIDOffset], r, s, true) {
+		return common.Address{}, errors.New("invalid signature: r or s out of range, or s not in the lower half of the curve order")
+	}
+
+	publicKey, err := crypto.SigToPub(hash, normalized)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// OwnershipAttestation is a statement that a wallet belongs to an IDRX member on a chain,
+// signed by the wallet before it is registered as a mint destination. Nonce should be a
+// fresh challenge from the verifier so an old signature cannot be replayed.
+type OwnershipAttestation struct {
+	Wallet    common.Address
+	MemberID  int // IDRX member ID, as in models.Member
+	ChainID   uint64
+	Nonce     string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// OwnershipAttestationTypes is the EIP-712 schema of OwnershipAttestation. The chain ID is
+// part of the domain, so a signature for one chain does not verify on another.
+var OwnershipAttestationTypes = apitypes.Types{
+	"EIP712Domain": {
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+	},
+	"WalletOwnership": {
+		{Name: "wallet", Type: "address"},
+		{Name: "memberId", Type: "uint256"},
+		{Name: "nonce", Type: "string"},
+		{Name: "issuedAt", Type: "uint256"},
+		{Name: "expiresAt", Type: "uint256"},
+	},
+}
+
+// TypedData returns the attestation as EIP-712 typed data, e.g. to pass to a browser
+// wallet's eth_signTypedData_v4. Times are Unix seconds.
+func (a *OwnershipAttestation) TypedData() apitypes.TypedData {
+	chainID := math.HexOrDecimal256(*new(big.Int).SetUint64(a.ChainID))
+	return apitypes.TypedData{
+		Types:       OwnershipAttestationTypes,
+		PrimaryType: "WalletOwnership",
+		Domain: apitypes.TypedDataDomain{
+			Name:    "IDRX Wallet Ownership",
+			Version: "1",
+			ChainId: &chainID,
+		},
+		Message: apitypes.TypedDataMessage{
+			"wallet":    a.Wallet.Hex(),
+			"memberId":  strconv.Itoa(a.MemberID),
+			"nonce":     a.Nonce,
+			"issuedAt":  strconv.FormatInt(a.IssuedAt.Unix(), 10),
+			"expiresAt": strconv.FormatInt(a.ExpiresAt.Unix(), 10),
+		},
+	}
+}
+
+// SignOwnershipAttestation signs an attestation for the client's own wallet
+func (c *Client) SignOwnershipAttestation(attestation *OwnershipAttestation) ([]byte, error) {
+	if attestation.Wallet != c.address {
+		return nil, fmt.Errorf("attestation is for %s, client wallet is %s", attestation.Wallet.Hex(), c.address.Hex())
+	}
+	return c.SignTypedData(attestation.TypedData())
+}
+
+// VerifyOwnershipAttestation checks that an attestation is signed by its wallet and is
+// valid at the given time. Checking the nonce against the issued challenge is up to the caller.
+func VerifyOwnershipAttestation(attestation *OwnershipAttestation, signature []byte, now time.Time) error {
+	if now.Before(attestation.IssuedAt) {
+		return fmt.Errorf("attestation is issued in the future")
+	}
+	if !now.Before(attestation.ExpiresAt) {
+		return fmt.Errorf("attestation expired at %s", attestation.ExpiresAt.Format(time.RFC3339))
+	}
+
+	signer, err := VerifyTypedData(attestation.TypedData(), signature)
+	if err != nil {
+		return err
+	}
+	if signer != attestation.Wallet {
+		return fmt.Errorf("%w: signed by %s, attestation is for %s", ErrSignerMismatch, signer.Hex(), attestation.Wallet.Hex())
+	}
+	return nil
+}
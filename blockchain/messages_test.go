@@ -0,0 +1,107 @@
+package blockchain
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSignMessage(t *testing.T) {
+	client := newTestDevnetClient(t, "http://127.0.0.1:8545", 2)
+	message := []byte("IDRX ownership check 42")
+
+	signature, err := client.SignMessage(message)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	if v := signature[crypto.RecoveryIDOffset]; v != 27 && v != 28 {
+		t.Errorf("expected a recovery ID of 27 or 28, got %d", v)
+	}
+
+	signer, err := VerifyMessage(message, signature)
+	if err != nil {
+		t.Fatalf("failed to verify: %v", err)
+	}
+	if signer != client.GetAddress() {
+		t.Errorf("expected %s, got %s", client.GetAddress().Hex(), signer.Hex())
+	}
+
+	// Signatures with a raw 0/1 recovery ID verify too
+	signature[crypto.RecoveryIDOffset] -= 27
+	if signer, err := VerifyMessage(message, signature); err != nil || signer != client.GetAddress() {
+		t.Errorf("expected raw recovery ID to verify, got %s (%v)", signer.Hex(), err)
+	}
+
+	if signer, _ := VerifyMessage([]byte("another message"), signature); signer == client.GetAddress() {
+		t.Error("signature must not verify another message")
+	}
+}
+
+func TestVerifyMessageRejectsHighS(t *testing.T) {
+	client := newTestDevnetClient(t, "http://127.0.0.1:8545", 2)
+	message := []byte("IDRX ownership check 42")
+
+	signature, err := client.SignMessage(message)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	// (r, n-s) with the other recovery ID recovers the same key, but is not canonical
+	n := crypto.S256().Params().N
+	s := new(big.Int).Sub(n, new(big.Int).SetBytes(signature[32:64]))
+	malleated := append([]byte(nil), signature...)
+	s.FillBytes(malleated[32:64])
+	malleated[crypto.RecoveryIDOffset] ^= 1
+
+	if _, err := VerifyMessage(message, malleated); err == nil {
+		t.Error("expected a signature with a high s value to be rejected")
+	}
+}
+
+func TestOwnershipAttestation(t *testing.T) {
+	client := newTestDevnetClient(t, "http://127.0.0.1:8545", 2)
+	issued := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	attestation := &OwnershipAttestation{
+		Wallet:    client.GetAddress(),
+		MemberID:  1234,
+		ChainID:   BaseChainID,
+		Nonce:     "c0ffee",
+		IssuedAt:  issued,
+		ExpiresAt: issued.Add(10 * time.Minute),
+	}
+
+	signature, err := client.SignOwnershipAttestation(attestation)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	if err := VerifyOwnershipAttestation(attestation, signature, issued.Add(time.Minute)); err != nil {
+		t.Fatalf("expected the attestation to verify: %v", err)
+	}
+
+	if err := VerifyOwnershipAttestation(attestation, signature, issued.Add(time.Hour)); err == nil {
+		t.Error("expected an expired attestation to be rejected")
+	}
+
+	otherChain := *attestation
+	otherChain.ChainID = PolygonChainID
+	if err := VerifyOwnershipAttestation(&otherChain, signature, issued.Add(time.Minute)); !errors.Is(err, ErrSignerMismatch) {
+		t.Errorf("expected ErrSignerMismatch for another chain, got: %v", err)
+	}
+
+	otherMember := *attestation
+	otherMember.MemberID = 4321
+	if err := VerifyOwnershipAttestation(&otherMember, signature, issued.Add(time.Minute)); !errors.Is(err, ErrSignerMismatch) {
+		t.Errorf("expected ErrSignerMismatch for another member, got: %v", err)
+	}
+
+	otherWallet := *attestation
+	otherWallet.Wallet[0] ^= 1
+	if _, err := client.SignOwnershipAttestation(&otherWallet); err == nil {
+		t.Error("expected signing for another wallet to fail")
+	}
+}
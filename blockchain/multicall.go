@@ -0,0 +1,342 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+// Multicall3Address is the canonical Multicall3 deployment, at the same address on most EVM chains
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+const (
+	// multicallBatchSize caps the calls aggregated into one eth_call
+	multicallBatchSize = 500
+
+	// rpcBatchSize caps the requests sent in one JSON-RPC batch
+	rpcBatchSize = 100
+)
+
+// multicall3ABI covers the aggregate3 entry point of Multicall3
+const multicall3ABI = `[{"inputs":[{"components":[{"internalType":"address","name":"target","type":"address"},` +
+	`{"internalType":"bool","name":"allowFailure","type":"bool"},{"internalType":"bytes","name":"callData","type":"bytes"}],` +
+	`"internalType":"struct Multicall3.Call3[]","name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":` +
+	`[{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],` +
+	`"internalType":"struct Multicall3.Result[]","name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+// Call is a read-only IDRX contract call in a batch, e.g. {Method: "allowance", Args: []interface{}{owner, spender}}
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// CallResult holds the decoded outputs of one call in a batch
+type CallResult struct {
+	Values []interface{}
+	Err    error // The call reverted or could not be decoded
+}
+
+// multicallCall mirrors Multicall3.Call3
+type multicallCall struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// multicallResult mirrors Multicall3.Result
+type multicallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// BatchCall executes read-only IDRX calls in as few requests as possible. It aggregates them
+// through Multicall3 when the chain has it deployed, otherwise sends JSON-RPC batch requests,
+// and finally falls back to individual calls for backends without batch support.
+// Results are returned in call order; a failed call only sets that result's Err.
+func (c *Client) BatchCall(ctx context.Context, chainID uint64, calls []Call) ([]CallResult, error) {
+	networkConfig, _, exists := c.registry.GetByChainID(chainID)
+	if !exists {
+		return nil, fmt.Errorf("chain ID %d not supported", chainID)
+	}
+
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse IDRX ABI: %w", err)
+	}
+
+	encoded := make([][]byte, len(calls))
+	for i, call := range calls {
+		encoded[i], err = parsed.Pack(call.Method, call.Args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s call: %w", call.Method, err)
+		}
+	}
+
+	var returnData [][]byte
+	var callErrs []error
+
+	multicallAddress := networkConfig.MulticallAddress
+	if multicallAddress == (common.Address{}) {
+		multicallAddress = Multicall3Address
+	}
+
+	deployed, err := c.hasMulticall(ctx, chainID, multicallAddress)
+	if err != nil {
+		return nil, err
+	}
+	if deployed {
+		returnData, callErrs, err = c.multicall(ctx, chainID, multicallAddress, networkConfig.ContractAddress, encoded)
+	} else {
+		returnData, callErrs, err = c.rpcBatchCall(ctx, chainID, networkConfig.ContractAddress, encoded)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]CallResult, len(calls))
+	for i, call := range calls {
+		if callErrs[i] != nil {
+			results[i].Err = asRevertError(callErrs[i])
+			continue
+		}
+		results[i].Values, results[i].Err = parsed.Unpack(call.Method, returnData[i])
+		if results[i].Err != nil {
+			results[i].Err = fmt.Errorf("failed to decode %s result: %w", call.Method, results[i].Err)
+		}
+	}
+
+	return results, nil
+}
+
+// BalancesOf returns the IDRX balance of each address, batched into a few requests
+func (c *Client) BalancesOf(ctx context.Context, chainID uint64, addresses []common.Address) (map[common.Address]*TokenAmount, error) {
+	calls := make([]Call, len(addresses))
+	for i, address := range addresses {
+		calls[i] = Call{Method: "balanceOf", Args: []interface{}{address}}
+	}
+
+	results, err := c.BatchCall(ctx, chainID, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals := int32(c.Decimals(chainID))
+	balances := make(map[common.Address]*TokenAmount, len(addresses))
+	var errs []error
+	for i, result := range results {
+		balance, err := singleValue[*big.Int](result)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("balance of %s: %w", addresses[i].Hex(), err))
+			continue
+		}
+		balances[addresses[i]] = FromWei(balance, decimals)
+	}
+
+	return balances, errors.Join(errs...)
+}
+
+// BlacklistStatuses reports whether each address is blacklisted, batched into a few requests
+func (c *Client) BlacklistStatuses(ctx context.Context, chainID uint64, addresses []common.Address) (map[common.Address]bool, error) {
+	calls := make([]Call, len(addresses))
+	for i, address := range addresses {
+		calls[i] = Call{Method: "getBlackListStatus", Args: []interface{}{address}}
+	}
+
+	results, err := c.BatchCall(ctx, chainID, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[common.Address]bool, len(addresses))
+	var errs []error
+	for i, result := range results {
+		blacklisted, err := singleValue[bool](result)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("blacklist status of %s: %w", addresses[i].Hex(), err))
+			continue
+		}
+		statuses[addresses[i]] = blacklisted
+	}
+
+	return statuses, errors.Join(errs...)
+}
+
+// HasRoles reports whether each account holds a role, batched into a few requests
+func (c *Client) HasRoles(ctx context.Context, chainID uint64, role [32]byte, accounts []common.Address) (map[common.Address]bool, error) {
+	calls := make([]Call, len(accounts))
+	for i, account := range accounts {
+		calls[i] = Call{Method: "hasRole", Args: []interface{}{role, account}}
+	}
+
+	results, err := c.BatchCall(ctx, chainID, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make(map[common.Address]bool, len(accounts))
+	var errs []error
+	for i, result := range results {
+		hasRole, err := singleValue[bool](result)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("role of %s: %w", accounts[i].Hex(), err))
+			continue
+		}
+		roles[accounts[i]] = hasRole
+	}
+
+	return roles, errors.Join(errs...)
+}
+
+// singleValue extracts the only output of a call result
+func singleValue[T any](result CallResult) (T, error) {
+	var zero T
+	if result.Err != nil {
+		return zero, result.Err
+	}
+	if len(result.Values) != 1 {
+		return zero, fmt.Errorf("expected 1 output, got %d", len(result.Values))
+	}
+	value, ok := result.Values[0].(T)
+	if !ok {
+		return zero, fmt.Errorf("unexpected output type %T", result.Values[0])
+	}
+	return value, nil
+}
+
+// hasMulticall checks once per chain whether the Multicall3 contract has code
+func (c *Client) hasMulticall(ctx context.Context, chainID uint64, address common.Address) (bool, error) {
+	if deployed, cached := c.multicallDeployed.Load(chainID); cached {
+		return deployed.(bool), nil
+	}
+
+	backend, err := c.GetBackend(chainID)
+	if err != nil {
+		return false, err
+	}
+
+	code, err := backend.CodeAt(ctx, address, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to check Multicall3 deployment: %w", err)
+	}
+
+	deployed := len(code) > 0
+	c.multicallDeployed.Store(chainID, deployed)
+	return deployed, nil
+}
+
+// multicall aggregates calls through Multicall3's aggregate3, allowing individual failures
+func (c *Client) multicall(
+	ctx context.Context,
+	chainID uint64,
+	multicallAddress, target common.Address,
+	encoded [][]byte,
+) ([][]byte, []error, error) {
+	backend, err := c.GetBackend(chainID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse Multicall3 ABI: %w", err)
+	}
+
+	returnData := make([][]byte, len(encoded))
+	callErrs := make([]error, len(encoded))
+
+	for start := 0; start < len(encoded); start += multicallBatchSize {
+		end := min(start+multicallBatchSize, len(encoded))
+
+		batch := make([]multicallCall, 0, end-start)
+		for _, data := range encoded[start:end] {
+			batch = append(batch, multicallCall{Target: target, AllowFailure: true, CallData: data})
+		}
+
+		input, err := parsed.Pack("aggregate3", batch)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode aggregate3 call: %w", err)
+		}
+
+		output, err := backend.CallContract(ctx, ethereum.CallMsg{To: &multicallAddress, Data: input}, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("multicall failed: %w", err)
+		}
+
+		unpacked, err := parsed.Unpack("aggregate3", output)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode aggregate3 result: %w", err)
+		}
+		results := *abi.ConvertType(unpacked[0], new([]multicallResult)).(*[]multicallResult)
+		if len(results) != end-start {
+			return nil, nil, fmt.Errorf("multicall returned %d results for %d calls", len(results), end-start)
+		}
+
+		for i, result := range results {
+			if result.Success {
+				returnData[start+i] = result.ReturnData
+			} else {
+				callErrs[start+i] = decodeRevertData(result.ReturnData)
+			}
+		}
+	}
+
+	return returnData, callErrs, nil
+}
+
+// rpcBatchCall sends the calls as JSON-RPC batches of eth_call, or one by one when the
+// backend is not an RPC client (such as the simulated backend)
+func (c *Client) rpcBatchCall(ctx context.Context, chainID uint64, target common.Address, encoded [][]byte) ([][]byte, []error, error) {
+	returnData := make([][]byte, len(encoded))
+	callErrs := make([]error, len(encoded))
+
+	ethClient, err := c.GetClient(chainID)
+	if err != nil {
+		backend, backendErr := c.GetBackend(chainID)
+		if backendErr != nil {
+			return nil, nil, backendErr
+		}
+		for i, data := range encoded {
+			returnData[i], callErrs[i] = backend.CallContract(ctx, ethereum.CallMsg{To: &target, Data: data}, nil)
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+		}
+		return returnData, callErrs, nil
+	}
+
+	rpcClient := ethClient.Client()
+	for start := 0; start < len(encoded); start += rpcBatchSize {
+		end := min(start+rpcBatchSize, len(encoded))
+
+		batch := make([]rpc.BatchElem, 0, end-start)
+		results := make([]hexutil.Bytes, end-start)
+		for i, data := range encoded[start:end] {
+			batch = append(batch, rpc.BatchElem{
+				Method: "eth_call",
+				Args:   []interface{}{map[string]interface{}{"to": target, "data": hexutil.Bytes(data)}, "latest"},
+				Result: &results[i],
+			})
+		}
+
+		if err := rpcClient.BatchCallContext(ctx, batch); err != nil {
+			return nil, nil, fmt.Errorf("batch request failed: %w", err)
+		}
+
+		for i, elem := range batch {
+			returnData[start+i] = results[i]
+			callErrs[start+i] = elem.Error
+		}
+	}
+
+	return returnData, callErrs, nil
+}
@@ -0,0 +1,178 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+// callArgs is the transaction object of an eth_call request
+type callArgs struct {
+	To    common.Address `json:"to"`
+	Data  hexutil.Bytes  `json:"data"`
+	Input hexutil.Bytes  `json:"input"`
+}
+
+func (a callArgs) payload() []byte {
+	if len(a.Input) > 0 {
+		return a.Input
+	}
+	return a.Data
+}
+
+// answerIDRXCall emulates the IDRX contract: every address has a balance equal to its last
+// byte, addresses ending in 0x01 are blacklisted and 0xff reverts
+func answerIDRXCall(t *testing.T, data []byte) ([]byte, bool) {
+	t.Helper()
+
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+	method, err := parsed.MethodById(data[:4])
+	if err != nil {
+		t.Fatalf("unknown selector %x", data[:4])
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		t.Fatalf("failed to unpack %s: %v", method.Name, err)
+	}
+
+	address := args[0].(common.Address)
+	if address[19] == 0xff {
+		return encodeRevertReason(t, "boom"), false
+	}
+
+	var output []byte
+	switch method.Name {
+	case "balanceOf":
+		output, err = method.Outputs.Pack(big.NewInt(int64(address[19])))
+	case "getBlackListStatus":
+		output, err = method.Outputs.Pack(address[19] == 0x01)
+	default:
+		t.Fatalf("unexpected method %s", method.Name)
+	}
+	if err != nil {
+		t.Fatalf("failed to pack %s output: %v", method.Name, err)
+	}
+	return output, true
+}
+
+func addressList(count int) []common.Address {
+	addresses := make([]common.Address, count)
+	for i := range addresses {
+		addresses[i] = common.BigToAddress(big.NewInt(int64(0x1000 + i%200)))
+	}
+	return addresses
+}
+
+func TestBlacklistStatusesUsesMulticall(t *testing.T) {
+	multicallABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		t.Fatalf("failed to parse Multicall3 ABI: %v", err)
+	}
+
+	var ethCalls atomic.Int32
+	server := newTestRPCServer(t, 31337, map[string]rpcHandler{
+		"eth_getCode": func(_ []json.RawMessage) (any, error) { return "0x6080", nil },
+		"eth_call": func(params []json.RawMessage) (any, error) {
+			ethCalls.Add(1)
+
+			var args callArgs
+			if err := json.Unmarshal(params[0], &args); err != nil {
+				return nil, err
+			}
+			if args.To != Multicall3Address {
+				t.Errorf("expected call to Multicall3, got %s", args.To.Hex())
+			}
+
+			input, err := multicallABI.Methods["aggregate3"].Inputs.Unpack(args.payload()[4:])
+			if err != nil {
+				return nil, err
+			}
+			calls := *abi.ConvertType(input[0], new([]multicallCall)).(*[]multicallCall)
+
+			results := make([]multicallResult, len(calls))
+			for i, call := range calls {
+				results[i].ReturnData, results[i].Success = answerIDRXCall(t, call.CallData)
+			}
+			output, err := multicallABI.Methods["aggregate3"].Outputs.Pack(results)
+			if err != nil {
+				return nil, err
+			}
+			return hexutil.Encode(output), nil
+		},
+	})
+	client := newTestDevnetClient(t, server.URL, 2)
+
+	listed := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	clean := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	broken := common.HexToAddress("0x00000000000000000000000000000000000000ff")
+
+	statuses, err := client.BlacklistStatuses(context.Background(), 31337, []common.Address{listed, clean, broken})
+	if err == nil {
+		t.Error("expected an error for the reverting call")
+	}
+	if !statuses[listed] || statuses[clean] {
+		t.Errorf("unexpected statuses: %v", statuses)
+	}
+	if _, exists := statuses[broken]; exists {
+		t.Error("failed calls should be left out of the result")
+	}
+	if ethCalls.Load() != 1 {
+		t.Errorf("expected a single aggregated eth_call, got %d", ethCalls.Load())
+	}
+}
+
+func TestBalancesOfFallsBackToRPCBatch(t *testing.T) {
+	var codeChecks atomic.Int32
+	server := newTestRPCServer(t, 31337, map[string]rpcHandler{
+		"eth_getCode": func(_ []json.RawMessage) (any, error) {
+			codeChecks.Add(1)
+			return "0x", nil
+		},
+		"eth_call": func(params []json.RawMessage) (any, error) {
+			var args callArgs
+			if err := json.Unmarshal(params[0], &args); err != nil {
+				return nil, err
+			}
+			output, ok := answerIDRXCall(t, args.payload())
+			if !ok {
+				return nil, errors.New("execution reverted")
+			}
+			return hexutil.Encode(output), nil
+		},
+	})
+	client := newTestDevnetClient(t, server.URL, 2)
+
+	addresses := addressList(250)
+	for i := 0; i < 2; i++ {
+		balances, err := client.BalancesOf(context.Background(), 31337, addresses)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(balances) != 200 {
+			t.Fatalf("expected 200 distinct balances, got %d", len(balances))
+		}
+
+		expected := decimal.New(int64(addresses[5][19]), -2)
+		if !balances[addresses[5]].Amount.Equal(expected) {
+			t.Errorf("expected balance %s, got %s", expected, balances[addresses[5]].Amount)
+		}
+	}
+
+	if codeChecks.Load() != 1 {
+		t.Errorf("expected Multicall3 detection to be cached, got %d checks", codeChecks.Load())
+	}
+}
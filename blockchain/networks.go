@@ -18,6 +18,10 @@ type NetworkConfig struct {
 	MaxGasPrice     uint64 // in wei
 	IsTestnet       bool
 	Decimals        uint8 // Token decimals for this deployment
+
+	// MulticallAddress is the Multicall3 deployment used to batch reads
+	// (defaults to the canonical Multicall3Address when zero)
+	MulticallAddress common.Address
 }
 
 // Network identifier constants for better type safety
@@ -0,0 +1,189 @@
+// Package offline splits an IDRX write into three steps for air-gapped wallets: an online
+// host builds an unsigned transaction into a portable envelope, an offline host signs it,
+// and an online host broadcasts the signed transaction. Every step checks the envelope
+// against a network registry, so a tampered envelope cannot redirect a signature.
+package offline
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+// Version is the envelope format written by this package
+const Version = 1
+
+// Methods that can be built into an envelope
+const (
+	MethodTransfer              = "transfer"
+	MethodBurnWithAccountNumber = "burnWithAccountNumber"
+	MethodBurnBridge            = "burnBridge"
+)
+
+// Envelope carries one IDRX transaction between the online and offline hosts. Tx is the
+// RLP encoding of the unsigned transaction; Method and Args describe its calldata for the
+// person approving the signature and are checked against it.
+type Envelope struct {
+	Version   int               `json:"version"`
+	Network   string            `json:"network"`
+	ChainID   uint64            `json:"chainId"` // Signed into the transaction; legacy transactions do not encode it until signed
+	Contract  common.Address    `json:"contract"`
+	From      common.Address    `json:"from"`
+	Method    string            `json:"method"`
+	Args      map[string]string `json:"args"`
+	Tx        hexutil.Bytes     `json:"tx"`
+	SignedTx  hexutil.Bytes     `json:"signedTx,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// ReadFile loads an envelope from a JSON file
+func ReadFile(path string) (*Envelope, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read envelope: %w", err)
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope %s: %w", path, err)
+	}
+	return &envelope, nil
+}
+
+// WriteFile saves the envelope as indented JSON
+func (e *Envelope) WriteFile(path string) error {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode envelope: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write envelope: %w", err)
+	}
+	return nil
+}
+
+// Transaction decodes the unsigned transaction
+func (e *Envelope) Transaction() (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(e.Tx); err != nil {
+		return nil, fmt.Errorf("failed to decode unsigned transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// SignedTransaction decodes the signed transaction
+func (e *Envelope) SignedTransaction() (*types.Transaction, error) {
+	if len(e.SignedTx) == 0 {
+		return nil, fmt.Errorf("envelope is not signed")
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(e.SignedTx); err != nil {
+		return nil, fmt.Errorf("failed to decode signed transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// Verify checks the envelope against a registry: the chain must be registered with the
+// envelope's contract address, and the unsigned transaction must call that contract on that
+// chain with exactly the method and arguments the envelope describes
+func (e *Envelope) Verify(registry *blockchain.Registry) (*types.Transaction, error) {
+	if e.Version != Version {
+		return nil, fmt.Errorf("unsupported envelope version %d", e.Version)
+	}
+
+	networkConfig, _, exists := registry.GetByChainID(e.ChainID)
+	if !exists {
+		return nil, fmt.Errorf("chain ID %d not supported", e.ChainID)
+	}
+	if networkConfig.ContractAddress != e.Contract {
+		return nil, fmt.Errorf("envelope contract %s is not the IDRX contract %s on chain %d",
+			e.Contract.Hex(), networkConfig.ContractAddress.Hex(), e.ChainID)
+	}
+
+	tx, err := e.Transaction()
+	if err != nil {
+		return nil, err
+	}
+	if tx.To() == nil || *tx.To() != e.Contract {
+		return nil, fmt.Errorf("transaction is not sent to contract %s", e.Contract.Hex())
+	}
+	// Unsigned legacy transactions carry no chain ID; it is bound by the EIP-155 signature
+	if tx.Type() != types.LegacyTxType && tx.ChainId().Cmp(new(big.Int).SetUint64(e.ChainID)) != 0 {
+		return nil, fmt.Errorf("transaction chain ID %s does not match envelope chain ID %d", tx.ChainId(), e.ChainID)
+	}
+	if tx.Value().Sign() != 0 {
+		return nil, fmt.Errorf("transaction carries a native value of %s wei", tx.Value())
+	}
+
+	method, args, err := describe(tx.Data(), int32(networkConfig.Decimals))
+	if err != nil {
+		return nil, err
+	}
+	if method != e.Method {
+		return nil, fmt.Errorf("transaction calls %s, envelope says %s", method, e.Method)
+	}
+	if len(args) != len(e.Args) {
+		return nil, fmt.Errorf("envelope arguments do not match the %s calldata", method)
+	}
+	for name, value := range args {
+		if e.Args[name] != value {
+			return nil, fmt.Errorf("envelope argument %s is %q, calldata has %q", name, e.Args[name], value)
+		}
+	}
+
+	return tx, nil
+}
+
+// describe decodes IDRX calldata into its method name and human-readable arguments;
+// methods other than the supported writes are rejected
+func describe(data []byte, decimals int32) (string, map[string]string, error) {
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse IDRX ABI: %w", err)
+	}
+	if len(data) < 4 {
+		return "", nil, fmt.Errorf("transaction has no method selector")
+	}
+
+	method, err := parsed.MethodById(data[:4])
+	if err != nil {
+		return "", nil, fmt.Errorf("unknown method selector %x", data[:4])
+	}
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to decode %s arguments: %w", method.Name, err)
+	}
+
+	amount := func(value interface{}) string {
+		return blockchain.FromWei(value.(*big.Int), decimals).String()
+	}
+
+	switch method.Name {
+	case MethodTransfer:
+		return method.Name, map[string]string{
+			"to":     values[0].(common.Address).Hex(),
+			"amount": amount(values[1]),
+		}, nil
+	case MethodBurnWithAccountNumber:
+		return method.Name, map[string]string{
+			"amount":        amount(values[0]),
+			"accountNumber": values[1].(string),
+		}, nil
+	case MethodBurnBridge:
+		return method.Name, map[string]string{
+			"amount":    amount(values[0]),
+			"toChainId": values[1].(*big.Int).String(),
+		}, nil
+	}
+	return "", nil, fmt.Errorf("method %s cannot be signed offline", method.Name)
+}
@@ -0,0 +1,245 @@
+package offline
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+// BuildTransfer builds an unsigned transfer from one address. The client only reads from
+// the chain, so it can be created without a private key.
+func BuildTransfer(
+	ctx context.Context,
+	client *blockchain.Client,
+	chainID uint64,
+	from, to common.Address,
+	amount *blockchain.TokenAmount,
+) (*Envelope, error) {
+	return build(ctx, client, chainID, from, MethodTransfer, to, amount.ToWei())
+}
+
+// BuildBurnWithAccountNumber builds an unsigned burn for fiat redemption to a bank account
+func BuildBurnWithAccountNumber(
+	ctx context.Context,
+	client *blockchain.Client,
+	chainID uint64,
+	from common.Address,
+	amount *blockchain.TokenAmount,
+	accountNumber string,
+) (*Envelope, error) {
+	return build(ctx, client, chainID, from, MethodBurnWithAccountNumber, amount.ToWei(), accountNumber)
+}
+
+// BuildBurnBridge builds an unsigned bridge burn towards another chain
+func BuildBurnBridge(
+	ctx context.Context,
+	client *blockchain.Client,
+	chainID uint64,
+	from common.Address,
+	amount *blockchain.TokenAmount,
+	toChainID uint64,
+) (*Envelope, error) {
+	return build(ctx, client, chainID, from, MethodBurnBridge, amount.ToWei(), new(big.Int).SetUint64(toChainID))
+}
+
+// build encodes a call and fills in the sender's pending nonce, the gas limit and fees the
+// way the client's own transactor would: the network's gas limit and maximum gas price when
+// configured, otherwise an estimate and the node's fee suggestions
+func build(
+	ctx context.Context,
+	client *blockchain.Client,
+	chainID uint64,
+	from common.Address,
+	method string,
+	args ...interface{},
+) (*Envelope, error) {
+	networkConfig, networkName, exists := client.Registry().GetByChainID(chainID)
+	if !exists {
+		return nil, fmt.Errorf("chain ID %d not supported", chainID)
+	}
+	contract := networkConfig.ContractAddress
+
+	backend, err := client.GetBackend(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse IDRX ABI: %w", err)
+	}
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s call: %w", method, err)
+	}
+
+	nonce, err := backend.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	gas := networkConfig.GasLimit
+	if gas == 0 {
+		gas, err = backend.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &contract, Data: data})
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate gas for %s: %w", method, err)
+		}
+	}
+
+	txData, err := fees(ctx, backend, networkConfig, nonce, gas, contract, data)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := types.NewTx(txData).MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+
+	_, described, err := describe(data, int32(networkConfig.Decimals))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		Version:   Version,
+		Network:   networkName,
+		ChainID:   chainID,
+		Contract:  contract,
+		From:      from,
+		Method:    method,
+		Args:      described,
+		Tx:        encoded,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// fees returns the transaction with its fee fields set: a legacy transaction at the network's
+// maximum gas price when one is configured, an EIP-1559 transaction on London chains, and a
+// legacy transaction at the suggested gas price otherwise
+func fees(
+	ctx context.Context,
+	backend blockchain.Backend,
+	networkConfig *blockchain.NetworkConfig,
+	nonce, gas uint64,
+	contract common.Address,
+	data []byte,
+) (types.TxData, error) {
+	chainID := new(big.Int).SetUint64(networkConfig.ChainID)
+	legacy := func(gasPrice *big.Int) types.TxData {
+		return &types.LegacyTx{Nonce: nonce, GasPrice: gasPrice, Gas: gas, To: &contract, Data: data}
+	}
+
+	if networkConfig.MaxGasPrice > 0 {
+		return legacy(new(big.Int).SetUint64(networkConfig.MaxGasPrice)), nil
+	}
+
+	head, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+
+	if head.BaseFee == nil {
+		gasPrice, err := backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest gas price: %w", err)
+		}
+		return legacy(gasPrice), nil
+	}
+
+	tip, err := backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas tip: %w", err)
+	}
+	feeCap := new(big.Int).Add(tip, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+	return &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       gas,
+		To:        &contract,
+		Data:      data,
+	}, nil
+}
+
+// Sign verifies the envelope against the registry and signs its transaction with key,
+// which must belong to the envelope's sender. It needs no network access, so it can run on
+// an air-gapped host with the registry loaded from the same network file as the online host.
+func Sign(envelope *Envelope, key *ecdsa.PrivateKey, registry *blockchain.Registry) error {
+	tx, err := envelope.Verify(registry)
+	if err != nil {
+		return err
+	}
+
+	if signer := crypto.PubkeyToAddress(key.PublicKey); signer != envelope.From {
+		return fmt.Errorf("key belongs to %s, envelope is from %s", signer.Hex(), envelope.From.Hex())
+	}
+
+	signed, err := types.SignTx(tx, envelope.signer(), key)
+	if err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	encoded, err := signed.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode signed transaction: %w", err)
+	}
+	envelope.SignedTx = encoded
+	return nil
+}
+
+// signer signs for the envelope's chain. An unsigned legacy transaction decodes with a
+// meaningless chain ID, so the envelope's chain ID is used instead.
+func (e *Envelope) signer() types.Signer {
+	return types.LatestSignerForChainID(new(big.Int).SetUint64(e.ChainID))
+}
+
+// Broadcast verifies a signed envelope against the client's registry and submits the raw
+// transaction. The signature must be from the envelope's sender and cover exactly the
+// unsigned transaction that was built.
+func Broadcast(ctx context.Context, client *blockchain.Client, envelope *Envelope) (*types.Transaction, error) {
+	unsigned, err := envelope.Verify(client.Registry())
+	if err != nil {
+		return nil, err
+	}
+
+	signed, err := envelope.SignedTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	if !signed.Protected() {
+		return nil, fmt.Errorf("transaction is signed without replay protection")
+	}
+	signer := envelope.signer()
+	if signer.Hash(signed) != signer.Hash(unsigned) {
+		return nil, fmt.Errorf("signed transaction differs from the unsigned transaction")
+	}
+	sender, err := types.Sender(signer, signed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover signer: %w", err)
+	}
+	if sender != envelope.From {
+		return nil, fmt.Errorf("transaction is signed by %s, envelope is from %s", sender.Hex(), envelope.From.Hex())
+	}
+
+	backend, err := client.GetBackend(envelope.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	if err := backend.SendTransaction(ctx, signed); err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	return signed, nil
+}
@@ -0,0 +1,240 @@
+package offline
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/internal/backendtest"
+)
+
+var alice = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+
+// node serves what building and broadcasting need and records sent transactions
+type node struct {
+	backendtest.Chain
+
+	sent []*types.Transaction
+}
+
+func newNode() *node {
+	return &node{Chain: backendtest.Chain{Head: 50, BaseFee: big.NewInt(100)}}
+}
+
+func (n *node) SuggestGasTipCap(_ context.Context) (*big.Int, error) {
+	return big.NewInt(3), nil
+}
+
+func (n *node) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	return 7, nil
+}
+
+func (n *node) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	n.sent = append(n.sent, tx)
+	return nil
+}
+
+func TestBuildSignBroadcast(t *testing.T) {
+	key := backendtest.Key(t)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	backend := newNode()
+	client := backendtest.NewClient(t, backend)
+
+	if _, err := client.CreateTransactor(context.Background(), backendtest.ChainID); !errors.Is(err, blockchain.ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly from a client without a key, got: %v", err)
+	}
+
+	envelope, err := BuildTransfer(context.Background(), client, backendtest.ChainID, from, alice, backendtest.Amount("12.5"))
+	if err != nil {
+		t.Fatalf("failed to build: %v", err)
+	}
+	if envelope.Network != "Devnet" || envelope.Method != MethodTransfer {
+		t.Errorf("unexpected envelope: %+v", envelope)
+	}
+	if envelope.Args["to"] != alice.Hex() || envelope.Args["amount"] != "12.50" {
+		t.Errorf("unexpected arguments: %v", envelope.Args)
+	}
+
+	tx, err := envelope.Transaction()
+	if err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if tx.Type() != types.DynamicFeeTxType || tx.Nonce() != 7 || tx.Gas() != 3000000 {
+		t.Errorf("unexpected transaction: type %d, nonce %d, gas %d", tx.Type(), tx.Nonce(), tx.Gas())
+	}
+	if tx.GasTipCap().Int64() != 3 || tx.GasFeeCap().Int64() != 203 {
+		t.Errorf("unexpected fees: tip %s, cap %s", tx.GasTipCap(), tx.GasFeeCap())
+	}
+
+	// The envelope crosses the air gap as a file
+	path := filepath.Join(t.TempDir(), "transfer.json")
+	if err := envelope.WriteFile(path); err != nil {
+		t.Fatalf("failed to write envelope: %v", err)
+	}
+	envelope, err = ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read envelope: %v", err)
+	}
+
+	if err := Sign(envelope, key, backendtest.Registry(t)); err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	sent, err := Broadcast(context.Background(), client, envelope)
+	if err != nil {
+		t.Fatalf("failed to broadcast: %v", err)
+	}
+	if len(backend.sent) != 1 || backend.sent[0].Hash() != sent.Hash() {
+		t.Fatalf("expected the signed transaction to be sent, got %v", backend.sent)
+	}
+}
+
+func TestSignRejectsTamperedEnvelope(t *testing.T) {
+	key := backendtest.Key(t)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	client := backendtest.NewClient(t, newNode())
+	envelope, err := BuildBurnWithAccountNumber(context.Background(), client, backendtest.ChainID, from, backendtest.Amount("100"), "1234567890")
+	if err != nil {
+		t.Fatalf("failed to build: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tamper   func(e *Envelope)
+		registry *blockchain.Registry
+		expected string
+	}{
+		{
+			name:   "contract not in registry",
+			tamper: func(_ *Envelope) {},
+			registry: backendtest.Registry(t, func(config *blockchain.NetworkConfig) {
+				config.ContractAddress = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+			}),
+			expected: "is not the IDRX contract",
+		},
+		{
+			name:     "arguments differ from calldata",
+			tamper:   func(e *Envelope) { e.Args["accountNumber"] = "0987654321" },
+			expected: "envelope argument accountNumber",
+		},
+		{
+			name:     "method differs from calldata",
+			tamper:   func(e *Envelope) { e.Method = MethodTransfer },
+			expected: "envelope says transfer",
+		},
+		{
+			name:     "unknown chain",
+			tamper:   func(e *Envelope) { e.ChainID = 1 },
+			expected: "not supported",
+		},
+		{
+			name:     "other sender",
+			tamper:   func(e *Envelope) { e.From = alice },
+			expected: "key belongs to",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copied := *envelope
+			copied.Args = map[string]string{}
+			for name, value := range envelope.Args {
+				copied.Args[name] = value
+			}
+			tt.tamper(&copied)
+
+			registry := tt.registry
+			if registry == nil {
+				registry = backendtest.Registry(t)
+			}
+
+			err := Sign(&copied, key, registry)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error containing %q, got: %v", tt.expected, err)
+			}
+			if len(copied.SignedTx) != 0 {
+				t.Error("rejected envelope must not be signed")
+			}
+		})
+	}
+}
+
+func TestBroadcastRejectsSwappedSignature(t *testing.T) {
+	key := backendtest.Key(t)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	backend := newNode()
+	client := backendtest.NewClient(t, backend)
+
+	burn, err := BuildBurnBridge(context.Background(), client, backendtest.ChainID, from, backendtest.Amount("5"), blockchain.BaseChainID)
+	if err != nil {
+		t.Fatalf("failed to build: %v", err)
+	}
+	if burn.Args["toChainId"] != "8453" {
+		t.Errorf("unexpected arguments: %v", burn.Args)
+	}
+	transfer, err := BuildTransfer(context.Background(), client, backendtest.ChainID, from, alice, backendtest.Amount("5"))
+	if err != nil {
+		t.Fatalf("failed to build: %v", err)
+	}
+	if err := Sign(transfer, key, backendtest.Registry(t)); err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	// A signature for another transaction must not be broadcast under this envelope
+	burn.SignedTx = transfer.SignedTx
+	if _, err := Broadcast(context.Background(), client, burn); err == nil {
+		t.Fatal("expected the swapped signature to be rejected")
+	}
+	if len(backend.sent) != 0 {
+		t.Errorf("nothing should be sent, got %d transactions", len(backend.sent))
+	}
+}
+
+func TestBuildSignBroadcastLegacyGas(t *testing.T) {
+	key := backendtest.Key(t)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	// Every built-in network caps the gas price, which builds a legacy transaction
+	const maxGasPrice = 30_000_000_000
+	capGasPrice := func(config *blockchain.NetworkConfig) { config.MaxGasPrice = maxGasPrice }
+	registry := backendtest.Registry(t, capGasPrice)
+	backend := newNode()
+	client := backendtest.NewClient(t, backend, capGasPrice)
+
+	envelope, err := BuildTransfer(context.Background(), client, backendtest.ChainID, from, alice, backendtest.Amount("12.5"))
+	if err != nil {
+		t.Fatalf("failed to build: %v", err)
+	}
+	tx, err := envelope.Transaction()
+	if err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if tx.Type() != types.LegacyTxType || tx.GasPrice().Uint64() != maxGasPrice {
+		t.Fatalf("expected a legacy transaction at the maximum gas price, got type %d at %s", tx.Type(), tx.GasPrice())
+	}
+
+	if err := Sign(envelope, key, registry); err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	sent, err := Broadcast(context.Background(), client, envelope)
+	if err != nil {
+		t.Fatalf("failed to broadcast: %v", err)
+	}
+	if !sent.Protected() || sent.ChainId().Uint64() != backendtest.ChainID {
+		t.Errorf("expected an EIP-155 signature for chain %d, got chain %s", backendtest.ChainID, sent.ChainId())
+	}
+	if len(backend.sent) != 1 {
+		t.Errorf("expected 1 transaction sent, got %d", len(backend.sent))
+	}
+}
@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/contracts"
 )
 
 // TokenAmount represents a precise token amount using decimal
@@ -35,33 +38,49 @@ func FromWei(wei *big.Int, decimals int32) *TokenAmount {
 }
 
 // BalanceOf returns the IDRX balance for an address on the specified chain
-func (c *Client) BalanceOf(_ context.Context, chainID uint64, address common.Address) (*TokenAmount, error) {
+func (c *Client) BalanceOf(ctx context.Context, chainID uint64, address common.Address) (*TokenAmount, error) {
+	return c.BalanceAt(ctx, chainID, address, nil)
+}
+
+// BalanceAt returns the IDRX balance for an address as of a block (nil for the latest block).
+// Historical blocks need an archive node unless they are recent.
+func (c *Client) BalanceAt(ctx context.Context, chainID uint64, address common.Address, blockNumber *big.Int) (*TokenAmount, error) {
 	contract, err := c.GetContract(chainID)
 	if err != nil {
 		return nil, err
 	}
 
-	balance, err := contract.BalanceOf(nil, address)
+	balance, err := contract.BalanceOf(callOpts(ctx, blockNumber), address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get balance: %w", err)
 	}
 
-	return FromWei(balance, int32(GetDecimals(chainID))), nil
+	return FromWei(balance, int32(c.Decimals(chainID))), nil
 }
 
 // TotalSupply returns the total supply of IDRX on the specified chain
-func (c *Client) TotalSupply(_ context.Context, chainID uint64) (*TokenAmount, error) {
+func (c *Client) TotalSupply(ctx context.Context, chainID uint64) (*TokenAmount, error) {
+	return c.TotalSupplyAt(ctx, chainID, nil)
+}
+
+// TotalSupplyAt returns the total supply of IDRX as of a block (nil for the latest block)
+func (c *Client) TotalSupplyAt(ctx context.Context, chainID uint64, blockNumber *big.Int) (*TokenAmount, error) {
 	contract, err := c.GetContract(chainID)
 	if err != nil {
 		return nil, err
 	}
 
-	supply, err := contract.TotalSupply(nil)
+	supply, err := contract.TotalSupply(callOpts(ctx, blockNumber))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total supply: %w", err)
 	}
 
-	return FromWei(supply, int32(GetDecimals(chainID))), nil
+	return FromWei(supply, int32(c.Decimals(chainID))), nil
+}
+
+// callOpts creates call options bound to the caller's context and an optional block
+func callOpts(ctx context.Context, blockNumber *big.Int) *bind.CallOpts {
+	return &bind.CallOpts{Context: ctx, BlockNumber: blockNumber}
 }
 
 // Transfer sends IDRX tokens from the client's address to another address
@@ -71,6 +90,10 @@ func (c *Client) Transfer(ctx context.Context, chainID uint64, to common.Address
 		return nil, err
 	}
 
+	if err := c.preflight(ctx, chainID, "transfer", to, amount.ToWei()); err != nil {
+		return nil, err
+	}
+
 	transactor, err := c.CreateTransactor(ctx, chainID)
 	if err != nil {
 		return nil, err
@@ -78,7 +101,7 @@ func (c *Client) Transfer(ctx context.Context, chainID uint64, to common.Address
 
 	tx, err := contract.Transfer(transactor, to, amount.ToWei())
 	if err != nil {
-		return nil, fmt.Errorf("failed to transfer tokens: %w", err)
+		return nil, fmt.Errorf("failed to transfer tokens: %w", asRevertError(err))
 	}
 
 	return tx, nil
@@ -91,6 +114,10 @@ func (c *Client) Mint(ctx context.Context, chainID uint64, to common.Address, am
 		return nil, err
 	}
 
+	if err := c.preflight(ctx, chainID, "mint", to, amount.ToWei()); err != nil {
+		return nil, err
+	}
+
 	transactor, err := c.CreateTransactor(ctx, chainID)
 	if err != nil {
 		return nil, err
@@ -98,7 +125,7 @@ func (c *Client) Mint(ctx context.Context, chainID uint64, to common.Address, am
 
 	tx, err := contract.Mint(transactor, to, amount.ToWei())
 	if err != nil {
-		return nil, fmt.Errorf("failed to mint tokens: %w", err)
+		return nil, fmt.Errorf("failed to mint tokens: %w", asRevertError(err))
 	}
 
 	return tx, nil
@@ -111,6 +138,10 @@ func (c *Client) Burn(ctx context.Context, chainID uint64, amount *TokenAmount)
 		return nil, err
 	}
 
+	if err := c.preflight(ctx, chainID, "burn", amount.ToWei()); err != nil {
+		return nil, err
+	}
+
 	transactor, err := c.CreateTransactor(ctx, chainID)
 	if err != nil {
 		return nil, err
@@ -118,7 +149,7 @@ func (c *Client) Burn(ctx context.Context, chainID uint64, amount *TokenAmount)
 
 	tx, err := contract.Burn(transactor, amount.ToWei())
 	if err != nil {
-		return nil, fmt.Errorf("failed to burn tokens: %w", err)
+		return nil, fmt.Errorf("failed to burn tokens: %w", asRevertError(err))
 	}
 
 	return tx, nil
@@ -136,6 +167,10 @@ func (c *Client) BurnWithAccountNumber(
 		return nil, err
 	}
 
+	if err := c.preflight(ctx, chainID, "burnWithAccountNumber", amount.ToWei(), accountNumber); err != nil {
+		return nil, err
+	}
+
 	transactor, err := c.CreateTransactor(ctx, chainID)
 	if err != nil {
 		return nil, err
@@ -143,7 +178,7 @@ func (c *Client) BurnWithAccountNumber(
 
 	tx, err := contract.BurnWithAccountNumber(transactor, amount.ToWei(), accountNumber)
 	if err != nil {
-		return nil, fmt.Errorf("failed to burn tokens with account number: %w", err)
+		return nil, fmt.Errorf("failed to burn tokens with account number: %w", asRevertError(err))
 	}
 
 	return tx, nil
@@ -164,6 +199,10 @@ func (c *Client) BurnBridge(ctx context.Context, request *BridgeRequest) (*types
 		return nil, err
 	}
 
+	if err := c.preflight(ctx, request.FromChainID, "burnBridge", request.Amount.ToWei(), new(big.Int).SetUint64(request.ToChainID)); err != nil {
+		return nil, err
+	}
+
 	transactor, err := c.CreateTransactor(ctx, request.FromChainID)
 	if err != nil {
 		return nil, err
@@ -171,7 +210,7 @@ func (c *Client) BurnBridge(ctx context.Context, request *BridgeRequest) (*types
 
 	tx, err := contract.BurnBridge(transactor, request.Amount.ToWei(), new(big.Int).SetUint64(request.ToChainID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to burn for bridge: %w", err)
+		return nil, fmt.Errorf("failed to burn for bridge: %w", asRevertError(err))
 	}
 
 	return tx, nil
@@ -191,6 +230,10 @@ func (c *Client) MintBridge(
 		return nil, err
 	}
 
+	if err := c.preflight(ctx, chainID, "mintBridge", to, amount.ToWei(), new(big.Int).SetUint64(fromChainID), bridgeNonce); err != nil {
+		return nil, err
+	}
+
 	transactor, err := c.CreateTransactor(ctx, chainID)
 	if err != nil {
 		return nil, err
@@ -198,20 +241,20 @@ func (c *Client) MintBridge(
 
 	tx, err := contract.MintBridge(transactor, to, amount.ToWei(), new(big.Int).SetUint64(fromChainID), bridgeNonce)
 	if err != nil {
-		return nil, fmt.Errorf("failed to mint bridge tokens: %w", err)
+		return nil, fmt.Errorf("failed to mint bridge tokens: %w", asRevertError(err))
 	}
 
 	return tx, nil
 }
 
 // GetBridgeNonce returns the current bridge nonce for the specified chain
-func (c *Client) GetBridgeNonce(_ context.Context, chainID uint64) (*big.Int, error) {
+func (c *Client) GetBridgeNonce(ctx context.Context, chainID uint64) (*big.Int, error) {
 	contract, err := c.GetContract(chainID)
 	if err != nil {
 		return nil, err
 	}
 
-	nonce, err := contract.BridgeNonce(nil)
+	nonce, err := contract.BridgeNonce(callOpts(ctx, nil))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get bridge nonce: %w", err)
 	}
@@ -220,13 +263,13 @@ func (c *Client) GetBridgeNonce(_ context.Context, chainID uint64) (*big.Int, er
 }
 
 // IsNonceUsed checks if a bridge nonce has been used for a specific chain
-func (c *Client) IsNonceUsed(_ context.Context, chainID uint64, fromChainID uint64, nonce *big.Int) (bool, error) {
+func (c *Client) IsNonceUsed(ctx context.Context, chainID uint64, fromChainID uint64, nonce *big.Int) (bool, error) {
 	contract, err := c.GetContract(chainID)
 	if err != nil {
 		return false, err
 	}
 
-	used, err := contract.FromChainNonceUsed(nil, new(big.Int).SetUint64(fromChainID), nonce)
+	used, err := contract.FromChainNonceUsed(callOpts(ctx, nil), new(big.Int).SetUint64(fromChainID), nonce)
 	if err != nil {
 		return false, fmt.Errorf("failed to check nonce usage: %w", err)
 	}
@@ -242,13 +285,13 @@ type PlatformFeeInfo struct {
 }
 
 // GetPlatformFeeInfo returns the current platform fee configuration
-func (c *Client) GetPlatformFeeInfo(_ context.Context, chainID uint64) (*PlatformFeeInfo, error) {
+func (c *Client) GetPlatformFeeInfo(ctx context.Context, chainID uint64) (*PlatformFeeInfo, error) {
 	contract, err := c.GetContract(chainID)
 	if err != nil {
 		return nil, err
 	}
 
-	recipient, burnFee, mintFee, err := contract.GetPlatformFeeInfo(nil)
+	recipient, burnFee, mintFee, err := contract.GetPlatformFeeInfo(callOpts(ctx, nil))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get platform fee info: %w", err)
 	}
@@ -260,14 +303,40 @@ func (c *Client) GetPlatformFeeInfo(_ context.Context, chainID uint64) (*Platfor
 	}, nil
 }
 
+// BasisPoints is the denominator of the bridge fees in PlatformFeeInfo
+const BasisPoints = 10000
+
+// GetMaxPlatformFee returns the highest bridge fee, in basis points, the contract accepts
+func (c *Client) GetMaxPlatformFee(ctx context.Context, chainID uint64) (uint64, error) {
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return 0, err
+	}
+
+	maxFee, err := contract.MaxPlatformFee(callOpts(ctx, nil))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get max platform fee: %w", err)
+	}
+
+	return maxFee, nil
+}
+
+// BridgeCut splits an amount in the smallest unit the way the contract does: the platform fee is
+// amount * feeBasisPoints / BasisPoints rounded down, and the rest is kept
+func BridgeCut(amount *big.Int, feeBasisPoints uint64) (platformFee, amountAfterCut *big.Int) {
+	platformFee = new(big.Int).Mul(amount, new(big.Int).SetUint64(feeBasisPoints))
+	platformFee.Quo(platformFee, big.NewInt(BasisPoints))
+	return platformFee, new(big.Int).Sub(amount, platformFee)
+}
+
 // IsBlacklisted checks if an address is blacklisted
-func (c *Client) IsBlacklisted(_ context.Context, chainID uint64, address common.Address) (bool, error) {
+func (c *Client) IsBlacklisted(ctx context.Context, chainID uint64, address common.Address) (bool, error) {
 	contract, err := c.GetContract(chainID)
 	if err != nil {
 		return false, err
 	}
 
-	blacklisted, err := contract.GetBlackListStatus(nil, address)
+	blacklisted, err := contract.GetBlackListStatus(callOpts(ctx, nil), address)
 	if err != nil {
 		return false, fmt.Errorf("failed to check blacklist status: %w", err)
 	}
@@ -283,23 +352,23 @@ type TokenInfo struct {
 }
 
 // GetTokenInfo returns basic information about the IDRX token
-func (c *Client) GetTokenInfo(_ context.Context, chainID uint64) (*TokenInfo, error) {
+func (c *Client) GetTokenInfo(ctx context.Context, chainID uint64) (*TokenInfo, error) {
 	contract, err := c.GetContract(chainID)
 	if err != nil {
 		return nil, err
 	}
 
-	name, err := contract.Name(nil)
+	name, err := contract.Name(callOpts(ctx, nil))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token name: %w", err)
 	}
 
-	symbol, err := contract.Symbol(nil)
+	symbol, err := contract.Symbol(callOpts(ctx, nil))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token symbol: %w", err)
 	}
 
-	decimals, err := contract.Decimals(nil)
+	decimals, err := contract.Decimals(callOpts(ctx, nil))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token decimals: %w", err)
 	}
@@ -348,7 +417,7 @@ func (c *Client) CompleteBridge(ctx context.Context, request *BridgeRequest) err
 	}
 
 	// Step 3: Extract bridge nonce from burn event
-	bridgeNonce, err := c.extractBridgeNonceFromReceipt(receipt)
+	bridgeNonce, err := c.extractBridgeNonceFromReceipt(request.FromChainID, receipt)
 	if err != nil {
 		return fmt.Errorf("failed to extract bridge nonce: %w", err)
 	}
@@ -363,18 +432,43 @@ func (c *Client) CompleteBridge(ctx context.Context, request *BridgeRequest) err
 }
 
 // extractBridgeNonceFromReceipt extracts the bridge nonce from a burn bridge transaction receipt
-func (c *Client) extractBridgeNonceFromReceipt(receipt *types.Receipt) (*big.Int, error) {
-	// Parse BurnBridge event logs to extract the nonce
+func (c *Client) extractBridgeNonceFromReceipt(chainID uint64, receipt *types.Receipt) (*big.Int, error) {
+	networkConfig, _, exists := c.registry.GetByChainID(chainID)
+	if !exists {
+		return nil, fmt.Errorf("chain ID %d not supported", chainID)
+	}
+
+	event, err := findBurnBridgeEvent(networkConfig.ContractAddress, receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	return event.BridgeNonce, nil
+}
+
+// findBurnBridgeEvent returns the first BurnBridge event emitted by the contract in a receipt
+func findBurnBridgeEvent(contractAddress common.Address, receipt *types.Receipt) (*contracts.IDRXBurnBridge, error) {
+	filterer, err := contracts.NewIDRXFilterer(contractAddress, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event filterer: %w", err)
+	}
+
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse IDRX ABI: %w", err)
+	}
+
+	burnBridgeTopic := parsed.Events["BurnBridge"].ID
 	for _, log := range receipt.Logs {
-		if len(log.Topics) > 0 {
-			// BurnBridge event signature: BurnBridge(address,uint256,uint256,uint256,uint256,uint256)
-			burnBridgeTopic := common.HexToHash("0x") // This would need to be the actual event signature hash
-			if log.Topics[0] == burnBridgeTopic {
-				// Extract nonce from log data (this is simplified - actual implementation would need proper ABI decoding)
-				// For now, return a placeholder
-				return big.NewInt(0), nil
-			}
+		if log.Address != contractAddress || len(log.Topics) == 0 || log.Topics[0] != burnBridgeTopic {
+			continue
+		}
+
+		event, err := filterer.ParseBurnBridge(*log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse BurnBridge event: %w", err)
 		}
+		return event, nil
 	}
 
 	return nil, fmt.Errorf("bridge nonce not found in transaction receipt")
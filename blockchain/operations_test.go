@@ -0,0 +1,142 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+// burnBridgeLog builds a BurnBridge log as the contract would emit it
+func burnBridgeLog(t *testing.T, contract, user common.Address, amount, toChain, nonce int64) *types.Log {
+	t.Helper()
+
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+
+	event := parsed.Events["BurnBridge"]
+	data, err := event.Inputs.NonIndexed().Pack(
+		user,
+		big.NewInt(amount),
+		big.NewInt(amount),
+		big.NewInt(toChain),
+		big.NewInt(nonce),
+		big.NewInt(0),
+	)
+	if err != nil {
+		t.Fatalf("failed to pack event data: %v", err)
+	}
+
+	return &types.Log{Address: contract, Topics: []common.Hash{event.ID}, Data: data}
+}
+
+func TestFindBurnBridgeEvent(t *testing.T) {
+	contract := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	user := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+
+	receipt := &types.Receipt{Logs: []*types.Log{
+		// Same event shape from another contract must be ignored
+		burnBridgeLog(t, other, user, 500, PolygonChainID, 99),
+		{Address: contract, Topics: []common.Hash{{0x01}}},
+		burnBridgeLog(t, contract, user, 1000, PolygonChainID, 7),
+	}}
+
+	event, err := findBurnBridgeEvent(contract, receipt)
+	if err != nil {
+		t.Fatalf("expected BurnBridge event, got error: %v", err)
+	}
+	if event.BridgeNonce.Int64() != 7 {
+		t.Errorf("expected bridge nonce 7, got %s", event.BridgeNonce)
+	}
+	if event.User != user {
+		t.Errorf("expected user %s, got %s", user.Hex(), event.User.Hex())
+	}
+	if event.ToChain.Uint64() != PolygonChainID {
+		t.Errorf("expected destination chain %d, got %s", PolygonChainID, event.ToChain)
+	}
+}
+
+func TestFindBurnBridgeEventMissing(t *testing.T) {
+	contract := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	if _, err := findBurnBridgeEvent(contract, &types.Receipt{}); err == nil {
+		t.Error("expected error for receipt without BurnBridge event")
+	}
+}
+
+func TestBalanceAtQueriesRequestedBlock(t *testing.T) {
+	var blockParam string
+	server := newTestRPCServer(t, 31337, map[string]rpcHandler{
+		"eth_call": func(params []json.RawMessage) (any, error) {
+			if len(params) > 1 {
+				_ = json.Unmarshal(params[1], &blockParam)
+			}
+			return uint256Result(12345), nil
+		},
+	})
+	client := newTestDevnetClient(t, server.URL, 2)
+	address := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+
+	balance, err := client.BalanceAt(context.Background(), 31337, address, big.NewInt(42))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if blockParam != "0x2a" {
+		t.Errorf("expected eth_call at block 0x2a, got %q", blockParam)
+	}
+	if !balance.Amount.Equal(decimal.RequireFromString("123.45")) {
+		t.Errorf("expected balance 123.45, got %s", balance.Amount)
+	}
+
+	if _, err := client.TotalSupplyAt(context.Background(), 31337, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if blockParam != "latest" {
+		t.Errorf("expected eth_call at latest block, got %q", blockParam)
+	}
+}
+
+func TestBalanceOfHonoursContext(t *testing.T) {
+	server := newTestRPCServer(t, 31337, map[string]rpcHandler{
+		"eth_call": func(_ []json.RawMessage) (any, error) { return uint256Result(1), nil },
+	})
+	client := newTestDevnetClient(t, server.URL, 2)
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.BalanceOf(ctx, 31337, common.HexToAddress("0x00000000000000000000000000000000000000cc"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context cancellation to be honoured, got: %v", err)
+	}
+}
+
+func TestBridgeCutRoundsFeeDown(t *testing.T) {
+	tests := []struct {
+		amount, fee, wantFee, wantAfterCut int64
+	}{
+		{amount: 100000, fee: 50, wantFee: 500, wantAfterCut: 99500},
+		{amount: 199, fee: 50, wantFee: 0, wantAfterCut: 199},
+		{amount: 12345, fee: 30, wantFee: 37, wantAfterCut: 12308},
+	}
+
+	for _, tt := range tests {
+		fee, afterCut := BridgeCut(big.NewInt(tt.amount), uint64(tt.fee))
+		if fee.Int64() != tt.wantFee || afterCut.Int64() != tt.wantAfterCut {
+			t.Errorf("BridgeCut(%d, %d) = %s, %s; want %d, %d", tt.amount, tt.fee, fee, afterCut, tt.wantFee, tt.wantAfterCut)
+		}
+	}
+}
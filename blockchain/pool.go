@@ -0,0 +1,820 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Backend is the subset of the go-ethereum client API used by the SDK.
+// *ethclient.Client satisfies it, as does the simulated backend client.
+type Backend interface {
+	ethereum.BlockNumberReader
+	ethereum.ChainReader
+	ethereum.ChainStateReader
+	ethereum.ContractCaller
+	ethereum.GasEstimator
+	ethereum.GasPricer
+	ethereum.GasPricer1559
+	ethereum.FeeHistoryReader
+	ethereum.LogFilterer
+	ethereum.PendingStateReader
+	ethereum.PendingContractCaller
+	ethereum.TransactionReader
+	ethereum.TransactionSender
+	ethereum.ChainIDReader
+}
+
+// PoolConfig controls background health checking and routing for the RPC endpoints of a chain
+type PoolConfig struct {
+	HealthCheckInterval time.Duration // How often endpoints are probed in the background
+	HealthCheckTimeout  time.Duration // Timeout for a single probe
+	MaxBlockLag         uint64        // Blocks an endpoint may trail the highest head before it is ejected
+	MaxErrorRate        float64       // Smoothed error rate (0-1) above which an endpoint is ejected
+	EjectionDuration    time.Duration // How long an ejected endpoint is skipped by the router
+	MaxAttempts         int           // Endpoints tried for an idempotent read before giving up
+}
+
+// DefaultPoolConfig returns the pool configuration used when none is provided
+func DefaultPoolConfig() *PoolConfig {
+	return &PoolConfig{
+		HealthCheckInterval: 15 * time.Second,
+		HealthCheckTimeout:  5 * time.Second,
+		MaxBlockLag:         10,
+		MaxErrorRate:        0.5,
+		EjectionDuration:    time.Minute,
+		MaxAttempts:         3,
+	}
+}
+
+// withDefaults fills zero fields with values from DefaultPoolConfig
+func (pc *PoolConfig) withDefaults() *PoolConfig {
+	defaults := DefaultPoolConfig()
+	if pc == nil {
+		return defaults
+	}
+
+	config := *pc
+	if config.HealthCheckInterval <= 0 {
+		config.HealthCheckInterval = defaults.HealthCheckInterval
+	}
+	if config.HealthCheckTimeout <= 0 {
+		config.HealthCheckTimeout = defaults.HealthCheckTimeout
+	}
+	if config.MaxBlockLag == 0 {
+		config.MaxBlockLag = defaults.MaxBlockLag
+	}
+	if config.MaxErrorRate <= 0 {
+		config.MaxErrorRate = defaults.MaxErrorRate
+	}
+	if config.EjectionDuration <= 0 {
+		config.EjectionDuration = defaults.EjectionDuration
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	return &config
+}
+
+// EndpointStatus reports the health of a single RPC endpoint as seen by the pool
+type EndpointStatus struct {
+	URL         string        `json:"url"`
+	Healthy     bool          `json:"healthy"`
+	Latency     time.Duration `json:"latency"`
+	ErrorRate   float64       `json:"errorRate"`
+	BlockNumber uint64        `json:"blockNumber"`
+	BlockLag    uint64        `json:"blockLag"`
+	LastChecked time.Time     `json:"lastChecked"`
+	LastError   string        `json:"lastError,omitempty"`
+}
+
+// ewmaWeight is the weight given to the newest sample in latency and error rate averages
+const ewmaWeight = 0.2
+
+// endpoint tracks the health of a single RPC endpoint
+type endpoint struct {
+	url      string
+	backend  Backend
+	external bool // backend is owned by the caller and is not closed by the pool
+
+	mu            sync.Mutex
+	latency       time.Duration // smoothed request latency
+	errorRate     float64       // smoothed failure ratio
+	head          uint64        // last observed block number
+	chainVerified bool          // endpoint reported the expected chain ID
+	wrongChain    bool          // endpoint serves a different chain and is never used
+	ejectedUntil  time.Time
+	lastChecked   time.Time
+	lastErr       error
+}
+
+// record folds the outcome of a request into the endpoint's statistics
+func (e *endpoint) record(latency time.Duration, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	sample := 0.0
+	if err != nil {
+		sample = 1.0
+		e.lastErr = err
+	}
+	e.errorRate = e.errorRate*(1-ewmaWeight) + sample*ewmaWeight
+
+	if err == nil {
+		if e.latency == 0 {
+			e.latency = latency
+		} else {
+			e.latency = time.Duration(float64(e.latency)*(1-ewmaWeight) + float64(latency)*ewmaWeight)
+		}
+	}
+}
+
+// endpointPool routes requests for one chain to its healthiest RPC endpoint
+type endpointPool struct {
+	chainID   uint64
+	config    *PoolConfig
+	endpoints []*endpoint
+
+	mu       sync.RWMutex
+	bestHead uint64
+
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// newEndpointPool creates a pool for the given chain; call start to begin health checks
+func newEndpointPool(chainID uint64, endpoints []*endpoint, config *PoolConfig) *endpointPool {
+	return &endpointPool{
+		chainID:   chainID,
+		config:    config.withDefaults(),
+		endpoints: endpoints,
+		stop:      make(chan struct{}),
+	}
+}
+
+// start runs an initial health check and then keeps checking in the background
+func (p *endpointPool) start() {
+	p.checkAll()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.config.HealthCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.checkAll()
+			}
+		}
+	}()
+}
+
+// close stops background health checks and closes every endpoint connection
+func (p *endpointPool) close() {
+	p.closeOnce.Do(func() {
+		close(p.stop)
+		p.wg.Wait()
+
+		for _, ep := range p.endpoints {
+			if ep.external {
+				continue
+			}
+			if closer, ok := ep.backend.(interface{ Close() }); ok {
+				closer.Close()
+			}
+		}
+	})
+}
+
+// checkAll probes every endpoint concurrently, then ejects the ones lagging behind the best head
+func (p *endpointPool) checkAll() {
+	var wg sync.WaitGroup
+	for _, ep := range p.endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+			p.checkEndpoint(ep)
+		}(ep)
+	}
+	wg.Wait()
+
+	var bestHead uint64
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		if !ep.wrongChain && ep.head > bestHead {
+			bestHead = ep.head
+		}
+		ep.mu.Unlock()
+	}
+
+	p.mu.Lock()
+	p.bestHead = bestHead
+	p.mu.Unlock()
+
+	now := time.Now()
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		lagging := bestHead > ep.head && bestHead-ep.head > p.config.MaxBlockLag
+		if lagging || ep.errorRate > p.config.MaxErrorRate {
+			ep.ejectedUntil = now.Add(p.config.EjectionDuration)
+		}
+		ep.mu.Unlock()
+	}
+}
+
+// checkEndpoint probes a single endpoint for its chain ID (once) and latest block
+func (p *endpointPool) checkEndpoint(ep *endpoint) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.config.HealthCheckTimeout)
+	defer cancel()
+
+	ep.mu.Lock()
+	verified := ep.chainVerified
+	ep.mu.Unlock()
+
+	if !verified {
+		chainID, err := ep.backend.ChainID(ctx)
+		if err != nil {
+			ep.record(0, err)
+			p.markChecked(ep)
+			return
+		}
+
+		ep.mu.Lock()
+		ep.chainVerified = true
+		if chainID.Uint64() != p.chainID {
+			ep.wrongChain = true
+			ep.lastErr = fmt.Errorf("endpoint serves chain ID %d, expected %d", chainID.Uint64(), p.chainID)
+		}
+		ep.mu.Unlock()
+	}
+
+	start := time.Now()
+	head, err := ep.backend.BlockNumber(ctx)
+	ep.record(time.Since(start), err)
+
+	if err == nil {
+		ep.mu.Lock()
+		ep.head = head
+		ep.mu.Unlock()
+	}
+	p.markChecked(ep)
+}
+
+// reachable reports whether at least one endpoint has answered a health check for the right chain
+func (p *endpointPool) reachable() bool {
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		ok := ep.chainVerified && !ep.wrongChain
+		ep.mu.Unlock()
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// lastError returns the most recent error recorded by any endpoint
+func (p *endpointPool) lastError() error {
+	var errs []error
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		if ep.lastErr != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ep.url, ep.lastErr))
+		}
+		ep.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// markChecked stamps the endpoint's last health check time
+func (p *endpointPool) markChecked(ep *endpoint) {
+	ep.mu.Lock()
+	ep.lastChecked = time.Now()
+	ep.mu.Unlock()
+}
+
+// ranked returns usable endpoints ordered from best to worst.
+// Endpoints whose chain ID has not been verified yet are left out, so a write is
+// never signed for one chain and sent to another.
+// Ejected endpoints are kept at the tail so requests still have somewhere to go
+// when every endpoint is currently unhealthy.
+func (p *endpointPool) ranked() []*endpoint {
+	type candidate struct {
+		ep      *endpoint
+		ejected bool
+		until   time.Time
+		score   float64
+	}
+
+	p.mu.RLock()
+	bestHead := p.bestHead
+	p.mu.RUnlock()
+
+	now := time.Now()
+	candidates := make([]candidate, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		if !ep.chainVerified || ep.wrongChain {
+			ep.mu.Unlock()
+			continue
+		}
+
+		latency := float64(ep.latency.Milliseconds())
+		if latency <= 0 {
+			latency = 1
+		}
+		var lag float64
+		if bestHead > ep.head {
+			lag = float64(bestHead - ep.head)
+		}
+
+		candidates = append(candidates, candidate{
+			ep:      ep,
+			ejected: now.Before(ep.ejectedUntil),
+			until:   ep.ejectedUntil,
+			score:   latency * (1 + 10*ep.errorRate) * (1 + lag),
+		})
+		ep.mu.Unlock()
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].ejected != candidates[j].ejected {
+			return !candidates[i].ejected
+		}
+		if candidates[i].ejected {
+			return candidates[i].until.Before(candidates[j].until)
+		}
+		return candidates[i].score < candidates[j].score
+	})
+
+	result := make([]*endpoint, len(candidates))
+	for i, c := range candidates {
+		result[i] = c.ep
+	}
+	return result
+}
+
+// best returns the highest ranked endpoint
+func (p *endpointPool) best() (*endpoint, error) {
+	ranked := p.ranked()
+	if len(ranked) == 0 {
+		return nil, fmt.Errorf("no available clients for chain ID %d", p.chainID)
+	}
+	return ranked[0], nil
+}
+
+// do runs an idempotent request, retrying on the next best endpoint when an endpoint fails
+func (p *endpointPool) do(ctx context.Context, fn func(Backend) error) error {
+	ranked := p.ranked()
+	if len(ranked) == 0 {
+		return fmt.Errorf("no available clients for chain ID %d", p.chainID)
+	}
+
+	attempts := p.config.MaxAttempts
+	if attempts > len(ranked) {
+		attempts = len(ranked)
+	}
+
+	var lastErr error
+	for _, ep := range ranked[:attempts] {
+		start := time.Now()
+		err := fn(ep.backend)
+		if !isEndpointFault(ctx, err) {
+			ep.record(time.Since(start), nil)
+			return err
+		}
+
+		ep.record(time.Since(start), err)
+		p.ejectIfFailing(ep)
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+// once runs a non-idempotent request against the best endpoint without retrying
+func (p *endpointPool) once(ctx context.Context, fn func(Backend) error) error {
+	ep, err := p.best()
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = fn(ep.backend)
+	if isEndpointFault(ctx, err) {
+		ep.record(time.Since(start), err)
+		p.ejectIfFailing(ep)
+	} else {
+		ep.record(time.Since(start), nil)
+	}
+	return err
+}
+
+// ejectIfFailing ejects an endpoint whose error rate crossed the configured threshold
+func (p *endpointPool) ejectIfFailing(ep *endpoint) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	if ep.errorRate > p.config.MaxErrorRate {
+		ep.ejectedUntil = time.Now().Add(p.config.EjectionDuration)
+	}
+}
+
+// status returns a snapshot of every endpoint's health
+func (p *endpointPool) status() []EndpointStatus {
+	p.mu.RLock()
+	bestHead := p.bestHead
+	p.mu.RUnlock()
+
+	now := time.Now()
+	statuses := make([]EndpointStatus, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		status := EndpointStatus{
+			URL:         ep.url,
+			Healthy:     ep.chainVerified && !ep.wrongChain && !now.Before(ep.ejectedUntil) && ep.errorRate <= p.config.MaxErrorRate,
+			Latency:     ep.latency,
+			ErrorRate:   ep.errorRate,
+			BlockNumber: ep.head,
+			LastChecked: ep.lastChecked,
+		}
+		if bestHead > ep.head {
+			status.BlockLag = bestHead - ep.head
+		}
+		if ep.lastErr != nil {
+			status.LastError = ep.lastErr.Error()
+		}
+		ep.mu.Unlock()
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// isEndpointFault reports whether err should count against the endpoint that returned it.
+// Caller cancellation, contract reverts and missing data are properties of the request,
+// not of the endpoint, so they are neither retried nor penalised.
+func isEndpointFault(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		// HTTP endpoints cannot serve subscriptions; callers fall back to polling
+		return false
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		// Execution reverted errors carry revert data and are deterministic across endpoints
+		return false
+	}
+
+	return true
+}
+
+// poolBackend implements Backend on top of an endpoint pool.
+// Read requests fail over to other endpoints; transactions and subscriptions do not.
+type poolBackend struct {
+	pool *endpointPool
+}
+
+var _ Backend = (*poolBackend)(nil)
+
+// BlockNumber implements ethereum.BlockNumberReader
+func (b *poolBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	var result uint64
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.BlockNumber(ctx)
+		return err
+	})
+	return result, err
+}
+
+// BlockByHash implements ethereum.ChainReader
+func (b *poolBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	var result *types.Block
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.BlockByHash(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+// BlockByNumber implements ethereum.ChainReader
+func (b *poolBackend) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	var result *types.Block
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.BlockByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+// HeaderByHash implements ethereum.ChainReader
+func (b *poolBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	var result *types.Header
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.HeaderByHash(ctx, hash)
+		return err
+	})
+	return result, err
+}
+
+// HeaderByNumber implements ethereum.ChainReader
+func (b *poolBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var result *types.Header
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.HeaderByNumber(ctx, number)
+		return err
+	})
+	return result, err
+}
+
+// TransactionCount implements ethereum.ChainReader
+func (b *poolBackend) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	var result uint
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.TransactionCount(ctx, blockHash)
+		return err
+	})
+	return result, err
+}
+
+// TransactionInBlock implements ethereum.ChainReader
+func (b *poolBackend) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
+	var result *types.Transaction
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.TransactionInBlock(ctx, blockHash, index)
+		return err
+	})
+	return result, err
+}
+
+// SubscribeNewHead implements ethereum.ChainReader
+func (b *poolBackend) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	var result ethereum.Subscription
+	err := b.pool.once(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.SubscribeNewHead(ctx, ch)
+		return err
+	})
+	return result, err
+}
+
+// BalanceAt implements ethereum.ChainStateReader
+func (b *poolBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	var result *big.Int
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.BalanceAt(ctx, account, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+// StorageAt implements ethereum.ChainStateReader
+func (b *poolBackend) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	var result []byte
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.StorageAt(ctx, account, key, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+// CodeAt implements ethereum.ChainStateReader
+func (b *poolBackend) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	var result []byte
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.CodeAt(ctx, account, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+// NonceAt implements ethereum.ChainStateReader
+func (b *poolBackend) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	var result uint64
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.NonceAt(ctx, account, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+// CallContract implements ethereum.ContractCaller
+func (b *poolBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result []byte
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.CallContract(ctx, call, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+// EstimateGas implements ethereum.GasEstimator
+func (b *poolBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	var result uint64
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.EstimateGas(ctx, call)
+		return err
+	})
+	return result, err
+}
+
+// SuggestGasPrice implements ethereum.GasPricer
+func (b *poolBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	var result *big.Int
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.SuggestGasPrice(ctx)
+		return err
+	})
+	return result, err
+}
+
+// SuggestGasTipCap implements ethereum.GasPricer1559
+func (b *poolBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	var result *big.Int
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.SuggestGasTipCap(ctx)
+		return err
+	})
+	return result, err
+}
+
+// FeeHistory implements ethereum.FeeHistoryReader
+func (b *poolBackend) FeeHistory(
+	ctx context.Context,
+	blockCount uint64,
+	lastBlock *big.Int,
+	rewardPercentiles []float64,
+) (*ethereum.FeeHistory, error) {
+	var result *ethereum.FeeHistory
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+		return err
+	})
+	return result, err
+}
+
+// FilterLogs implements ethereum.LogFilterer
+func (b *poolBackend) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var result []types.Log
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.FilterLogs(ctx, q)
+		return err
+	})
+	return result, err
+}
+
+// SubscribeFilterLogs implements ethereum.LogFilterer
+func (b *poolBackend) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	var result ethereum.Subscription
+	err := b.pool.once(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.SubscribeFilterLogs(ctx, q, ch)
+		return err
+	})
+	return result, err
+}
+
+// PendingBalanceAt implements ethereum.PendingStateReader
+func (b *poolBackend) PendingBalanceAt(ctx context.Context, account common.Address) (*big.Int, error) {
+	var result *big.Int
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.PendingBalanceAt(ctx, account)
+		return err
+	})
+	return result, err
+}
+
+// PendingStorageAt implements ethereum.PendingStateReader
+func (b *poolBackend) PendingStorageAt(ctx context.Context, account common.Address, key common.Hash) ([]byte, error) {
+	var result []byte
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.PendingStorageAt(ctx, account, key)
+		return err
+	})
+	return result, err
+}
+
+// PendingCodeAt implements ethereum.PendingStateReader
+func (b *poolBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	var result []byte
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.PendingCodeAt(ctx, account)
+		return err
+	})
+	return result, err
+}
+
+// PendingNonceAt implements ethereum.PendingStateReader
+func (b *poolBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	var result uint64
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.PendingNonceAt(ctx, account)
+		return err
+	})
+	return result, err
+}
+
+// PendingTransactionCount implements ethereum.PendingStateReader
+func (b *poolBackend) PendingTransactionCount(ctx context.Context) (uint, error) {
+	var result uint
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.PendingTransactionCount(ctx)
+		return err
+	})
+	return result, err
+}
+
+// PendingCallContract implements ethereum.PendingContractCaller
+func (b *poolBackend) PendingCallContract(ctx context.Context, call ethereum.CallMsg) ([]byte, error) {
+	var result []byte
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.PendingCallContract(ctx, call)
+		return err
+	})
+	return result, err
+}
+
+// TransactionByHash implements ethereum.TransactionReader
+func (b *poolBackend) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	var (
+		result  *types.Transaction
+		pending bool
+	)
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, pending, err = backend.TransactionByHash(ctx, txHash)
+		return err
+	})
+	return result, pending, err
+}
+
+// TransactionReceipt implements ethereum.TransactionReader
+func (b *poolBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	var result *types.Receipt
+	err := b.pool.do(ctx, func(backend Backend) error {
+		var err error
+		result, err = backend.TransactionReceipt(ctx, txHash)
+		return err
+	})
+	return result, err
+}
+
+// SendTransaction implements ethereum.TransactionSender.
+// Transactions are sent to a single endpoint; resubmitting elsewhere is left to the caller.
+func (b *poolBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return b.pool.once(ctx, func(backend Backend) error {
+		return backend.SendTransaction(ctx, tx)
+	})
+}
+
+// ChainID implements ethereum.ChainIDReader.
+// Endpoints are verified against the pool's chain ID during health checks, so no request is made.
+func (b *poolBackend) ChainID(_ context.Context) (*big.Int, error) {
+	return new(big.Int).SetUint64(b.pool.chainID), nil
+}
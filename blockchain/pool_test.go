@@ -0,0 +1,181 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+)
+
+// fakeBackend is a Backend stub whose health and call behaviour can be scripted
+type fakeBackend struct {
+	Backend // unimplemented methods panic if called
+
+	chainID  uint64
+	chainErr error
+	head     uint64
+	delay    time.Duration
+	callErr  error
+	calls    atomic.Int32
+}
+
+func (f *fakeBackend) ChainID(_ context.Context) (*big.Int, error) {
+	if f.chainErr != nil {
+		return nil, f.chainErr
+	}
+	return new(big.Int).SetUint64(f.chainID), nil
+}
+
+func (f *fakeBackend) BlockNumber(_ context.Context) (uint64, error) {
+	time.Sleep(f.delay)
+	return f.head, nil
+}
+
+func (f *fakeBackend) CallContract(_ context.Context, _ ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	f.calls.Add(1)
+	if f.callErr != nil {
+		return nil, f.callErr
+	}
+	return []byte{0x01}, nil
+}
+
+// revertError mimics the rpc.DataError returned for execution reverts
+type revertError struct{}
+
+func (revertError) Error() string          { return "execution reverted" }
+func (revertError) ErrorData() interface{} { return "0x" }
+
+func newTestPool(backends ...*fakeBackend) *endpointPool {
+	endpoints := make([]*endpoint, len(backends))
+	for i, backend := range backends {
+		endpoints[i] = &endpoint{url: "fake", backend: backend}
+	}
+	return newEndpointPool(BaseChainID, endpoints, nil)
+}
+
+func TestPoolPrefersFasterEndpoint(t *testing.T) {
+	slow := &fakeBackend{chainID: BaseChainID, head: 100, delay: 20 * time.Millisecond}
+	fast := &fakeBackend{chainID: BaseChainID, head: 100}
+
+	pool := newTestPool(slow, fast)
+	pool.checkAll()
+
+	best, err := pool.best()
+	if err != nil {
+		t.Fatalf("expected a best endpoint, got error: %v", err)
+	}
+	if best.backend != fast {
+		t.Error("expected the lower latency endpoint to be preferred")
+	}
+}
+
+func TestPoolEjectsLaggingEndpoint(t *testing.T) {
+	lagging := &fakeBackend{chainID: BaseChainID, head: 50}
+	current := &fakeBackend{chainID: BaseChainID, head: 100, delay: 5 * time.Millisecond}
+
+	pool := newTestPool(lagging, current)
+	pool.checkAll()
+
+	best, err := pool.best()
+	if err != nil {
+		t.Fatalf("expected a best endpoint, got error: %v", err)
+	}
+	if best.backend != current {
+		t.Error("expected the lagging endpoint to be ejected")
+	}
+
+	statuses := pool.status()
+	if statuses[0].Healthy {
+		t.Error("lagging endpoint should be reported unhealthy")
+	}
+	if statuses[0].BlockLag != 50 {
+		t.Errorf("expected block lag 50, got %d", statuses[0].BlockLag)
+	}
+}
+
+func TestPoolExcludesWrongChain(t *testing.T) {
+	wrong := &fakeBackend{chainID: PolygonChainID, head: 100}
+	right := &fakeBackend{chainID: BaseChainID, head: 100, delay: 5 * time.Millisecond}
+
+	pool := newTestPool(wrong, right)
+	pool.checkAll()
+
+	ranked := pool.ranked()
+	if len(ranked) != 1 || ranked[0].backend != right {
+		t.Error("endpoint serving another chain should never be routed to")
+	}
+}
+
+func TestPoolExcludesUnverifiedEndpoint(t *testing.T) {
+	unverified := &fakeBackend{chainID: BaseChainID, chainErr: errors.New("connection refused"), head: 100}
+	verified := &fakeBackend{chainID: BaseChainID, head: 100, delay: 5 * time.Millisecond}
+
+	pool := newTestPool(unverified, verified)
+	pool.checkAll()
+
+	ranked := pool.ranked()
+	if len(ranked) != 1 || ranked[0].backend != verified {
+		t.Fatal("endpoint whose chain ID was never verified should not be routed to")
+	}
+
+	unverified.chainErr = nil
+	pool.checkAll()
+	if len(pool.ranked()) != 2 {
+		t.Error("expected the endpoint to be routed to once its chain ID is verified")
+	}
+}
+
+func TestPoolRetriesReadsOnAnotherEndpoint(t *testing.T) {
+	failing := &fakeBackend{chainID: BaseChainID, head: 100, callErr: errors.New("connection reset")}
+	healthy := &fakeBackend{chainID: BaseChainID, head: 100, delay: 5 * time.Millisecond}
+
+	pool := newTestPool(failing, healthy)
+	pool.checkAll()
+
+	backend := &poolBackend{pool: pool}
+	result, err := backend.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+	if err != nil {
+		t.Fatalf("expected read to succeed on the second endpoint, got: %v", err)
+	}
+	if len(result) != 1 {
+		t.Errorf("unexpected result: %x", result)
+	}
+	if failing.calls.Load() != 1 || healthy.calls.Load() != 1 {
+		t.Errorf("expected one call per endpoint, got %d and %d", failing.calls.Load(), healthy.calls.Load())
+	}
+}
+
+func TestPoolDoesNotRetryReverts(t *testing.T) {
+	first := &fakeBackend{chainID: BaseChainID, head: 100, callErr: revertError{}}
+	second := &fakeBackend{chainID: BaseChainID, head: 100, delay: 5 * time.Millisecond}
+
+	pool := newTestPool(first, second)
+	pool.checkAll()
+
+	backend := &poolBackend{pool: pool}
+	if _, err := backend.CallContract(context.Background(), ethereum.CallMsg{}, nil); err == nil {
+		t.Fatal("expected revert error to be returned")
+	}
+	if second.calls.Load() != 0 {
+		t.Error("reverts are deterministic and should not be retried on another endpoint")
+	}
+	if pool.endpoints[0].errorRate != 0 {
+		t.Error("reverts should not count against the endpoint")
+	}
+}
+
+func TestPoolBackendChainIDIsLocal(t *testing.T) {
+	pool := newTestPool(&fakeBackend{chainID: BaseChainID, head: 1})
+
+	chainID, err := (&poolBackend{pool: pool}).ChainID(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if chainID.Uint64() != BaseChainID {
+		t.Errorf("expected chain ID %d, got %d", BaseChainID, chainID.Uint64())
+	}
+}
@@ -0,0 +1,257 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+// EIP-1967 storage slots of an upgradeable proxy
+var (
+	ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	AdminSlot          = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+	BeaconSlot         = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+)
+
+// ProxyState is what the EIP-1967 slots of the IDRX proxy point to at a block
+type ProxyState struct {
+	ChainID                uint64
+	Proxy                  common.Address
+	Block                  uint64
+	Implementation         common.Address
+	Admin                  common.Address // Zero for UUPS proxies, which keep no admin
+	Beacon                 common.Address // Zero unless the proxy is a beacon proxy
+	ImplementationCodeHash common.Hash    // Keccak-256 of the implementation's runtime bytecode
+}
+
+// ProxyEventType identifies an ERC1967Proxy event
+type ProxyEventType string
+
+// ERC1967Proxy event types
+const (
+	ProxyUpgraded       ProxyEventType = "Upgraded"
+	ProxyAdminChanged   ProxyEventType = "AdminChanged"
+	ProxyBeaconUpgraded ProxyEventType = "BeaconUpgraded"
+)
+
+// ProxyEvent is a historical upgrade, admin change or beacon change of the proxy
+type ProxyEvent struct {
+	Type          ProxyEventType
+	Address       common.Address // New implementation, admin or beacon
+	PreviousAdmin common.Address // Set for AdminChanged
+	BlockNumber   uint64
+	TxHash        common.Hash
+	Index         uint
+}
+
+// ProxyInspectOptions controls InspectProxy
+type ProxyInspectOptions struct {
+	FromBlock uint64  // History starts here; use the proxy deployment block
+	ToBlock   *uint64 // Snapshot block (defaults to the latest block)
+	ChunkSize uint64  // Blocks per eth_getLogs request (defaults to 2000)
+
+	// ExpectedCodeHash is the implementation bytecode hash the deployment should run.
+	// When set, ProxyReport.CodeHashMismatch reports whether it differs.
+	ExpectedCodeHash common.Hash
+}
+
+// ProxyReport is the current state and upgrade history of the IDRX proxy on one chain
+type ProxyReport struct {
+	ProxyState
+	History          []ProxyEvent // Oldest first
+	CodeHashMismatch bool
+}
+
+// ProxyState reads the EIP-1967 slots of the IDRX proxy as of a block (nil for the latest
+// block) and hashes the bytecode of the implementation they point to
+func (c *Client) ProxyState(ctx context.Context, chainID uint64, blockNumber *big.Int) (*ProxyState, error) {
+	networkConfig, _, exists := c.registry.GetByChainID(chainID)
+	if !exists {
+		return nil, fmt.Errorf("chain ID %d not supported", chainID)
+	}
+
+	backend, err := c.GetBackend(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if blockNumber == nil {
+		head, err := backend.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block number: %w", err)
+		}
+		blockNumber = new(big.Int).SetUint64(head)
+	}
+
+	proxy := networkConfig.ContractAddress
+	state := &ProxyState{ChainID: chainID, Proxy: proxy, Block: blockNumber.Uint64()}
+
+	slots := []struct {
+		slot   common.Hash
+		target *common.Address
+	}{
+		{ImplementationSlot, &state.Implementation},
+		{AdminSlot, &state.Admin},
+		{BeaconSlot, &state.Beacon},
+	}
+	for _, s := range slots {
+		value, err := backend.StorageAt(ctx, proxy, s.slot, blockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read storage slot %s: %w", s.slot.Hex(), err)
+		}
+		*s.target = common.BytesToAddress(value)
+	}
+
+	if state.Implementation != (common.Address{}) {
+		code, err := backend.CodeAt(ctx, state.Implementation, blockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get implementation code: %w", err)
+		}
+		if len(code) == 0 {
+			return nil, fmt.Errorf("no code at implementation %s on chain %d", state.Implementation.Hex(), chainID)
+		}
+		state.ImplementationCodeHash = crypto.Keccak256Hash(code)
+	}
+
+	return state, nil
+}
+
+// ProxyHistory lists the Upgraded, AdminChanged and BeaconUpgraded events of the IDRX proxy
+// between two blocks (inclusive), oldest first
+func (c *Client) ProxyHistory(ctx context.Context, chainID uint64, fromBlock, toBlock, chunkSize uint64) ([]ProxyEvent, error) {
+	networkConfig, _, exists := c.registry.GetByChainID(chainID)
+	if !exists {
+		return nil, fmt.Errorf("chain ID %d not supported", chainID)
+	}
+	if chunkSize == 0 {
+		chunkSize = maxLogRange
+	}
+
+	backend, err := c.GetBackend(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	filterer, err := contracts.NewERC1967ProxyFilterer(networkConfig.ContractAddress, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create proxy filterer: %w", err)
+	}
+
+	var history []ProxyEvent
+	for from := fromBlock; from <= toBlock; from += chunkSize {
+		to := min(from+chunkSize-1, toBlock)
+		events, err := fetchProxyEvents(ctx, filterer, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch proxy events for blocks %d-%d: %w", from, to, err)
+		}
+		history = append(history, events...)
+	}
+
+	return history, nil
+}
+
+// InspectProxy reads the current proxy state and its upgrade history, and flags an
+// implementation whose bytecode hash differs from the expected one
+func (c *Client) InspectProxy(ctx context.Context, chainID uint64, opts *ProxyInspectOptions) (*ProxyReport, error) {
+	if opts == nil {
+		opts = &ProxyInspectOptions{}
+	}
+
+	var block *big.Int
+	if opts.ToBlock != nil {
+		block = new(big.Int).SetUint64(*opts.ToBlock)
+	}
+
+	state, err := c.ProxyState(ctx, chainID, block)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := c.ProxyHistory(ctx, chainID, opts.FromBlock, state.Block, opts.ChunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ProxyReport{ProxyState: *state, History: history}
+	if opts.ExpectedCodeHash != (common.Hash{}) {
+		report.CodeHashMismatch = state.ImplementationCodeHash != opts.ExpectedCodeHash
+	}
+
+	return report, nil
+}
+
+// fetchProxyEvents returns the proxy events in a block range in the order they were emitted
+func fetchProxyEvents(ctx context.Context, filterer *contracts.ERC1967ProxyFilterer, from, to uint64) ([]ProxyEvent, error) {
+	opts := &bind.FilterOpts{Start: from, End: &to, Context: ctx}
+	var events []ProxyEvent
+
+	upgraded, err := filterer.FilterUpgraded(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	for upgraded.Next() {
+		e := upgraded.Event
+		events = append(events, ProxyEvent{
+			Type:        ProxyUpgraded,
+			Address:     e.Implementation,
+			BlockNumber: e.Raw.BlockNumber,
+			TxHash:      e.Raw.TxHash,
+			Index:       e.Raw.Index,
+		})
+	}
+	if err := upgraded.Error(); err != nil {
+		return nil, err
+	}
+
+	adminChanged, err := filterer.FilterAdminChanged(opts)
+	if err != nil {
+		return nil, err
+	}
+	for adminChanged.Next() {
+		e := adminChanged.Event
+		events = append(events, ProxyEvent{
+			Type:          ProxyAdminChanged,
+			Address:       e.NewAdmin,
+			PreviousAdmin: e.PreviousAdmin,
+			BlockNumber:   e.Raw.BlockNumber,
+			TxHash:        e.Raw.TxHash,
+			Index:         e.Raw.Index,
+		})
+	}
+	if err := adminChanged.Error(); err != nil {
+		return nil, err
+	}
+
+	beaconUpgraded, err := filterer.FilterBeaconUpgraded(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	for beaconUpgraded.Next() {
+		e := beaconUpgraded.Event
+		events = append(events, ProxyEvent{
+			Type:        ProxyBeaconUpgraded,
+			Address:     e.Beacon,
+			BlockNumber: e.Raw.BlockNumber,
+			TxHash:      e.Raw.TxHash,
+			Index:       e.Raw.Index,
+		})
+	}
+	if err := beaconUpgraded.Error(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].BlockNumber != events[j].BlockNumber {
+			return events[i].BlockNumber < events[j].BlockNumber
+		}
+		return events[i].Index < events[j].Index
+	})
+	return events, nil
+}
@@ -0,0 +1,117 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+// proxyBackend serves EIP-1967 slots, code and proxy events
+type proxyBackend struct {
+	roleBackend
+
+	storage map[common.Hash]common.Hash
+	code    map[common.Address][]byte
+}
+
+func (p *proxyBackend) StorageAt(_ context.Context, _ common.Address, key common.Hash, _ *big.Int) ([]byte, error) {
+	value := p.storage[key]
+	return value[:], nil
+}
+
+func (p *proxyBackend) CodeAt(_ context.Context, account common.Address, _ *big.Int) ([]byte, error) {
+	return p.code[account], nil
+}
+
+// proxyLog builds an ERC1967Proxy event log
+func proxyLog(t *testing.T, name string, block uint64, args ...common.Address) types.Log {
+	t.Helper()
+
+	parsed, err := contracts.ERC1967ProxyMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+	event := parsed.Events[name]
+
+	log := types.Log{
+		Address:     common.HexToAddress("0x00000000000000000000000000000000000000aa"),
+		Topics:      []common.Hash{event.ID},
+		BlockNumber: block,
+	}
+	if name == "AdminChanged" {
+		data, err := event.Inputs.Pack(args[0], args[1])
+		if err != nil {
+			t.Fatalf("failed to pack event data: %v", err)
+		}
+		log.Data = data
+	} else {
+		log.Topics = append(log.Topics, common.BytesToHash(args[0][:]))
+	}
+	return log
+}
+
+func TestInspectProxy(t *testing.T) {
+	v1 := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	v2 := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	admin := common.HexToAddress("0x00000000000000000000000000000000000000ad")
+	code := []byte{0x60, 0x80, 0x60, 0x40}
+
+	backend := &proxyBackend{
+		roleBackend: roleBackend{
+			headerBackend: headerBackend{head: 100},
+			logs: []types.Log{
+				proxyLog(t, "Upgraded", 10, v1),
+				proxyLog(t, "AdminChanged", 10, common.Address{}, admin),
+				proxyLog(t, "Upgraded", 90, v2),
+			},
+		},
+		storage: map[common.Hash]common.Hash{
+			ImplementationSlot: common.BytesToHash(v2[:]),
+			AdminSlot:          common.BytesToHash(admin[:]),
+		},
+		code: map[common.Address][]byte{v2: code},
+	}
+	backend.logs[1].Index = 1
+	client := newHeaderClient(t, backend)
+
+	report, err := client.InspectProxy(context.Background(), 31337, &ProxyInspectOptions{
+		ExpectedCodeHash: crypto.Keccak256Hash([]byte("previous implementation")),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Implementation != v2 || report.Admin != admin || report.Beacon != (common.Address{}) {
+		t.Errorf("unexpected slots: implementation %s, admin %s, beacon %s", report.Implementation.Hex(), report.Admin.Hex(), report.Beacon.Hex())
+	}
+	if report.ImplementationCodeHash != crypto.Keccak256Hash(code) {
+		t.Errorf("unexpected code hash %s", report.ImplementationCodeHash.Hex())
+	}
+	if !report.CodeHashMismatch {
+		t.Error("expected the upgraded implementation to be flagged")
+	}
+
+	if len(report.History) != 3 {
+		t.Fatalf("expected 3 history events, got %d", len(report.History))
+	}
+	if report.History[1].Type != ProxyAdminChanged || report.History[1].Address != admin {
+		t.Errorf("unexpected admin change: %+v", report.History[1])
+	}
+	if last := report.History[2]; last.Type != ProxyUpgraded || last.Address != v2 || last.BlockNumber != 90 {
+		t.Errorf("unexpected last upgrade: %+v", last)
+	}
+
+	report, err = client.InspectProxy(context.Background(), 31337, &ProxyInspectOptions{ExpectedCodeHash: crypto.Keccak256Hash(code)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.CodeHashMismatch {
+		t.Error("matching implementation should not be flagged")
+	}
+}
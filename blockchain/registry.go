@@ -0,0 +1,399 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// Testnet network identifiers. IDRX testnet deployments are not published with
+// fixed addresses, so these are registered with BaseSepoliaConfig and PolygonAmoyConfig.
+const (
+	BaseSepolia = "BaseSepolia"
+	PolygonAmoy = "PolygonAmoy"
+)
+
+// Chain ID constants for testnets
+const (
+	BaseSepoliaChainID = 84532
+	PolygonAmoyChainID = 80002
+)
+
+// defaultBlockTime is assumed for registered networks that do not specify a block time
+const defaultBlockTime = 2 * time.Second
+
+// Registry is an instance-scoped set of network configurations.
+// Unlike SupportedNetworks it can be customised per client without affecting other clients.
+type Registry struct {
+	mu       sync.RWMutex
+	networks map[string]*NetworkConfig
+}
+
+// NewRegistry creates an empty network registry
+func NewRegistry() *Registry {
+	return &Registry{
+		networks: make(map[string]*NetworkConfig),
+	}
+}
+
+// DefaultRegistry creates a registry pre-populated with copies of the SupportedNetworks mainnets
+func DefaultRegistry() *Registry {
+	registry := NewRegistry()
+	for name, config := range SupportedNetworks {
+		registry.networks[name] = config.clone()
+	}
+	return registry
+}
+
+// BaseSepoliaConfig returns a Base Sepolia testnet configuration for an IDRX deployment at contractAddress.
+// The public RPC endpoint is used when no endpoints are given.
+func BaseSepoliaConfig(contractAddress common.Address, rpcEndpoints ...string) *NetworkConfig {
+	if len(rpcEndpoints) == 0 {
+		rpcEndpoints = []string{"https://sepolia.base.org"}
+	}
+	return &NetworkConfig{
+		ChainID:         BaseSepoliaChainID,
+		Name:            "Base Sepolia",
+		RPCEndpoints:    rpcEndpoints,
+		ContractAddress: contractAddress,
+		BlockTime:       2 * time.Second,
+		GasLimit:        3000000,
+		MaxGasPrice:     10000000000, // 10 Gwei
+		IsTestnet:       true,
+		Decimals:        2,
+	}
+}
+
+// PolygonAmoyConfig returns a Polygon Amoy testnet configuration for an IDRX deployment at contractAddress.
+// The public RPC endpoint is used when no endpoints are given.
+func PolygonAmoyConfig(contractAddress common.Address, rpcEndpoints ...string) *NetworkConfig {
+	if len(rpcEndpoints) == 0 {
+		rpcEndpoints = []string{"https://rpc-amoy.polygon.technology"}
+	}
+	return &NetworkConfig{
+		ChainID:         PolygonAmoyChainID,
+		Name:            "Polygon Amoy",
+		RPCEndpoints:    rpcEndpoints,
+		ContractAddress: contractAddress,
+		BlockTime:       2 * time.Second,
+		GasLimit:        3000000,
+		MaxGasPrice:     50000000000, // 50 Gwei
+		IsTestnet:       true,
+		Decimals:        0,
+	}
+}
+
+// DevnetConfig returns a configuration for a local development chain such as anvil or hardhat
+func DevnetConfig(chainID uint64, rpcEndpoint string, contractAddress common.Address, decimals uint8) *NetworkConfig {
+	return &NetworkConfig{
+		ChainID:         chainID,
+		Name:            fmt.Sprintf("Devnet %d", chainID),
+		RPCEndpoints:    []string{rpcEndpoint},
+		ContractAddress: contractAddress,
+		BlockTime:       time.Second,
+		GasLimit:        3000000,
+		IsTestnet:       true,
+		Decimals:        decimals,
+	}
+}
+
+// Register adds or replaces a network configuration under the given name
+func (r *Registry) Register(networkName string, config *NetworkConfig) error {
+	if networkName == "" {
+		return fmt.Errorf("network name is required")
+	}
+	if config == nil {
+		return fmt.Errorf("network config cannot be nil")
+	}
+
+	network := config.clone()
+	if network.Name == "" {
+		network.Name = networkName
+	}
+	if network.BlockTime == 0 {
+		network.BlockTime = defaultBlockTime
+	}
+	if err := network.validate(); err != nil {
+		return fmt.Errorf("invalid network %s: %w", networkName, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for existingName, existing := range r.networks {
+		if existingName != networkName && existing.ChainID == network.ChainID {
+			return fmt.Errorf("chain ID %d already registered as %s", network.ChainID, existingName)
+		}
+	}
+
+	r.networks[networkName] = network
+	return nil
+}
+
+// Remove deletes a network from the registry
+func (r *Registry) Remove(networkName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.networks, networkName)
+}
+
+// SetRPCEndpoints replaces the RPC endpoints of a registered network, for example with
+// private provider URLs. Clients created before the change keep their existing endpoints.
+func (r *Registry) SetRPCEndpoints(networkName string, rpcEndpoints ...string) error {
+	if len(rpcEndpoints) == 0 {
+		return fmt.Errorf("at least one RPC endpoint is required")
+	}
+	for _, endpoint := range rpcEndpoints {
+		if err := validateRPCEndpoint(endpoint); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	config, exists := r.networks[networkName]
+	if !exists {
+		return fmt.Errorf("network %s not registered", networkName)
+	}
+
+	config.RPCEndpoints = append([]string(nil), rpcEndpoints...)
+	return nil
+}
+
+// Get returns a copy of the configuration for a network name
+func (r *Registry) Get(networkName string) (*NetworkConfig, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	config, exists := r.networks[networkName]
+	if !exists {
+		return nil, false
+	}
+	return config.clone(), true
+}
+
+// GetByChainID returns a copy of the configuration and the network name for a chain ID
+func (r *Registry) GetByChainID(chainID uint64) (*NetworkConfig, string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for networkName, config := range r.networks {
+		if config.ChainID == chainID {
+			return config.clone(), networkName, true
+		}
+	}
+	return nil, "", false
+}
+
+// Names returns the registered network names in sorted order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.networks))
+	for networkName := range r.networks {
+		names = append(names, networkName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsChainSupported checks if a chain ID is registered
+func (r *Registry) IsChainSupported(chainID uint64) bool {
+	_, _, exists := r.GetByChainID(chainID)
+	return exists
+}
+
+// Decimals returns the token decimals for a chain ID, or 2 if the chain is not registered
+func (r *Registry) Decimals(chainID uint64) uint8 {
+	config, _, exists := r.GetByChainID(chainID)
+	if !exists {
+		return 2 // fallback to majority decimal places
+	}
+	return config.Decimals
+}
+
+// networkFile is the on-disk representation of a set of network definitions
+type networkFile struct {
+	Networks map[string]networkDefinition `json:"networks" yaml:"networks"`
+}
+
+// networkDefinition is a single network entry in a YAML or JSON file.
+// Fields left empty keep the value of an already registered network of the same name.
+type networkDefinition struct {
+	ChainID         uint64   `json:"chainId" yaml:"chainId"`
+	Name            string   `json:"name" yaml:"name"`
+	RPCEndpoints    []string `json:"rpcEndpoints" yaml:"rpcEndpoints"`
+	ContractAddress string   `json:"contractAddress" yaml:"contractAddress"`
+	BlockTime       string   `json:"blockTime" yaml:"blockTime"` // Go duration, e.g. "2s"
+	GasLimit        uint64   `json:"gasLimit" yaml:"gasLimit"`
+	MaxGasPrice     uint64   `json:"maxGasPrice" yaml:"maxGasPrice"`
+	IsTestnet       *bool    `json:"isTestnet" yaml:"isTestnet"`
+	Decimals        *uint8   `json:"decimals" yaml:"decimals"`
+
+	MulticallAddress string `json:"multicallAddress" yaml:"multicallAddress"`
+}
+
+// LoadFile loads network definitions from a .yaml, .yml or .json file
+func (r *Registry) LoadFile(path string) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is provided by the caller
+	if err != nil {
+		return fmt.Errorf("failed to read network file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return r.LoadYAML(data)
+	case ".json":
+		return r.LoadJSON(data)
+	default:
+		return fmt.Errorf("unsupported network file extension %q", filepath.Ext(path))
+	}
+}
+
+// LoadYAML loads network definitions from YAML data
+func (r *Registry) LoadYAML(data []byte) error {
+	var file networkFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse network YAML: %w", err)
+	}
+	return r.apply(file)
+}
+
+// LoadJSON loads network definitions from JSON data
+func (r *Registry) LoadJSON(data []byte) error {
+	var file networkFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse network JSON: %w", err)
+	}
+	return r.apply(file)
+}
+
+// apply merges file definitions into the registry
+func (r *Registry) apply(file networkFile) error {
+	names := make([]string, 0, len(file.Networks))
+	for networkName := range file.Networks {
+		names = append(names, networkName)
+	}
+	sort.Strings(names)
+
+	for _, networkName := range names {
+		definition := file.Networks[networkName]
+
+		config, exists := r.Get(networkName)
+		if !exists {
+			config = &NetworkConfig{}
+		}
+
+		if err := definition.mergeInto(config); err != nil {
+			return fmt.Errorf("invalid network %s: %w", networkName, err)
+		}
+
+		if err := r.Register(networkName, config); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergeInto copies the fields set in the definition onto config
+func (d *networkDefinition) mergeInto(config *NetworkConfig) error {
+	if d.ChainID != 0 {
+		config.ChainID = d.ChainID
+	}
+	if d.Name != "" {
+		config.Name = d.Name
+	}
+	if len(d.RPCEndpoints) > 0 {
+		config.RPCEndpoints = append([]string(nil), d.RPCEndpoints...)
+	}
+	if d.ContractAddress != "" {
+		if !common.IsHexAddress(d.ContractAddress) {
+			return fmt.Errorf("invalid contract address %q", d.ContractAddress)
+		}
+		config.ContractAddress = common.HexToAddress(d.ContractAddress)
+	}
+	if d.BlockTime != "" {
+		blockTime, err := time.ParseDuration(d.BlockTime)
+		if err != nil {
+			return fmt.Errorf("invalid block time %q: %w", d.BlockTime, err)
+		}
+		config.BlockTime = blockTime
+	}
+	if d.GasLimit != 0 {
+		config.GasLimit = d.GasLimit
+	}
+	if d.MaxGasPrice != 0 {
+		config.MaxGasPrice = d.MaxGasPrice
+	}
+	if d.IsTestnet != nil {
+		config.IsTestnet = *d.IsTestnet
+	}
+	if d.Decimals != nil {
+		config.Decimals = *d.Decimals
+	}
+	if d.MulticallAddress != "" {
+		if !common.IsHexAddress(d.MulticallAddress) {
+			return fmt.Errorf("invalid multicall address %q", d.MulticallAddress)
+		}
+		config.MulticallAddress = common.HexToAddress(d.MulticallAddress)
+	}
+	return nil
+}
+
+// clone returns a deep copy of the network configuration
+func (nc *NetworkConfig) clone() *NetworkConfig {
+	cloned := *nc
+	cloned.RPCEndpoints = append([]string(nil), nc.RPCEndpoints...)
+	return &cloned
+}
+
+// validate performs static checks on a network configuration
+func (nc *NetworkConfig) validate() error {
+	if nc.ChainID == 0 {
+		return fmt.Errorf("chain ID is required")
+	}
+	if len(nc.RPCEndpoints) == 0 {
+		return fmt.Errorf("at least one RPC endpoint is required")
+	}
+	for _, endpoint := range nc.RPCEndpoints {
+		if err := validateRPCEndpoint(endpoint); err != nil {
+			return err
+		}
+	}
+	if nc.BlockTime < 0 {
+		return fmt.Errorf("block time cannot be negative")
+	}
+	return nil
+}
+
+// validateRPCEndpoint checks that an RPC endpoint is a URL with a supported scheme
+func validateRPCEndpoint(endpoint string) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid RPC endpoint %q: %w", endpoint, err)
+	}
+	switch parsed.Scheme {
+	case "http", "https", "ws", "wss":
+		if parsed.Host == "" {
+			return fmt.Errorf("invalid RPC endpoint %q: missing host", endpoint)
+		}
+	case "":
+		// IPC socket path
+	default:
+		return fmt.Errorf("invalid RPC endpoint %q: unsupported scheme %s", endpoint, parsed.Scheme)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package blockchain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDefaultRegistryIsIndependent(t *testing.T) {
+	registry := DefaultRegistry()
+
+	if len(registry.Names()) != len(SupportedNetworks) {
+		t.Fatalf("expected %d networks, got %d", len(SupportedNetworks), len(registry.Names()))
+	}
+
+	if err := registry.SetRPCEndpoints(BaseMainnet, "https://base-mainnet.example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	config, _ := registry.Get(BaseMainnet)
+	if config.RPCEndpoints[0] != "https://base-mainnet.example.com" {
+		t.Errorf("expected overridden endpoint, got %v", config.RPCEndpoints)
+	}
+	if SupportedNetworks[BaseMainnet].RPCEndpoints[0] == "https://base-mainnet.example.com" {
+		t.Error("overriding a registry endpoint must not modify SupportedNetworks")
+	}
+
+	// Copies returned by Get must not alias registry state
+	config.Decimals = 9
+	again, _ := registry.Get(BaseMainnet)
+	if again.Decimals != 2 {
+		t.Error("mutating a returned config must not affect the registry")
+	}
+}
+
+func TestRegistryRegisterTestnets(t *testing.T) {
+	registry := NewRegistry()
+	contract := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	if err := registry.Register(BaseSepolia, BaseSepoliaConfig(contract)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.Register(PolygonAmoy, PolygonAmoyConfig(contract, "https://amoy.example.com")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !registry.IsChainSupported(BaseSepoliaChainID) {
+		t.Error("Base Sepolia should be registered")
+	}
+	if registry.Decimals(PolygonAmoyChainID) != 0 {
+		t.Errorf("expected Polygon Amoy decimals 0, got %d", registry.Decimals(PolygonAmoyChainID))
+	}
+
+	config, name, exists := registry.GetByChainID(PolygonAmoyChainID)
+	if !exists || name != PolygonAmoy {
+		t.Fatalf("expected %s, got %s", PolygonAmoy, name)
+	}
+	if config.RPCEndpoints[0] != "https://amoy.example.com" {
+		t.Errorf("expected custom endpoint, got %v", config.RPCEndpoints)
+	}
+}
+
+func TestRegistryRegisterValidation(t *testing.T) {
+	registry := DefaultRegistry()
+
+	testCases := map[string]*NetworkConfig{
+		"missing chain ID":   {RPCEndpoints: []string{"http://localhost:8545"}},
+		"missing endpoints":  {ChainID: 31337},
+		"bad scheme":         {ChainID: 31337, RPCEndpoints: []string{"ftp://localhost"}},
+		"missing host":       {ChainID: 31337, RPCEndpoints: []string{"http://"}},
+		"duplicate chain ID": {ChainID: BaseChainID, RPCEndpoints: []string{"http://localhost:8545"}},
+	}
+
+	for name, config := range testCases {
+		if err := registry.Register("Devnet", config); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	devnet := DevnetConfig(31337, "http://127.0.0.1:8545", common.Address{}, 2)
+	if err := registry.Register("Devnet", devnet); err != nil {
+		t.Errorf("expected devnet to register, got: %v", err)
+	}
+}
+
+func TestRegistryLoadYAML(t *testing.T) {
+	registry := DefaultRegistry()
+
+	data := []byte(`
+networks:
+  BaseMainnet:
+    rpcEndpoints:
+      - https://base-mainnet.g.alchemy.com/v2/key
+  BaseSepolia:
+    chainId: 84532
+    name: Base Sepolia
+    rpcEndpoints: [https://sepolia.base.org]
+    contractAddress: "0x00000000000000000000000000000000000000aa"
+    blockTime: 2s
+    isTestnet: true
+    decimals: 2
+`)
+
+	if err := registry.LoadYAML(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	base, _ := registry.Get(BaseMainnet)
+	if len(base.RPCEndpoints) != 1 || base.RPCEndpoints[0] != "https://base-mainnet.g.alchemy.com/v2/key" {
+		t.Errorf("expected overridden endpoints, got %v", base.RPCEndpoints)
+	}
+	if base.ContractAddress != SupportedNetworks[BaseMainnet].ContractAddress {
+		t.Error("fields absent from the file should keep their existing values")
+	}
+
+	sepolia, exists := registry.Get(BaseSepolia)
+	if !exists {
+		t.Fatal("expected Base Sepolia to be added")
+	}
+	if !sepolia.IsTestnet || sepolia.BlockTime != 2*time.Second || sepolia.Decimals != 2 {
+		t.Errorf("unexpected Base Sepolia config: %+v", sepolia)
+	}
+}
+
+func TestRegistryLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "networks.json")
+	data := `{"networks": {"Anvil": {"chainId": 31337, "rpcEndpoints": ["http://127.0.0.1:8545"], "decimals": 0}}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewRegistry()
+	if err := registry.LoadFile(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	anvil, exists := registry.Get("Anvil")
+	if !exists {
+		t.Fatal("expected Anvil network to be loaded")
+	}
+	if anvil.Name != "Anvil" {
+		t.Errorf("expected name to default to the network key, got %s", anvil.Name)
+	}
+	if anvil.BlockTime != defaultBlockTime {
+		t.Errorf("expected default block time, got %s", anvil.BlockTime)
+	}
+
+	if err := registry.LoadFile(filepath.Join(dir, "networks.toml")); err == nil {
+		t.Error("expected error for missing or unsupported file")
+	}
+}
+
+func TestRegistryLoadInvalidAddress(t *testing.T) {
+	registry := NewRegistry()
+	err := registry.LoadJSON([]byte(`{"networks": {"Bad": {"chainId": 1, "rpcEndpoints": ["http://localhost"], "contractAddress": "0x123"}}}`))
+	if err == nil {
+		t.Error("expected error for invalid contract address")
+	}
+}
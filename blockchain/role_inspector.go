@@ -0,0 +1,349 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+// RoleInspectOptions controls the event replay of InspectRoles
+type RoleInspectOptions struct {
+	FromBlock uint64  // Replay starts here; use the contract deployment block
+	ToBlock   *uint64 // Snapshot block (defaults to the latest block)
+	ChunkSize uint64  // Blocks per eth_getLogs request (defaults to 2000)
+}
+
+// RoleHolder is an account holding a role according to the event replay
+type RoleHolder struct {
+	Account      common.Address
+	GrantedBy    common.Address
+	GrantedBlock uint64
+	GrantedTx    common.Hash
+}
+
+// RoleDiscrepancy is an account whose role membership from the event replay
+// disagrees with hasRole at the snapshot block
+type RoleDiscrepancy struct {
+	Role     Role
+	Account  common.Address
+	Replayed bool // Holds the role according to the events
+	OnChain  bool // Holds the role according to hasRole
+}
+
+// RoleReport is the reconstructed access control state of the contract on one chain
+type RoleReport struct {
+	ChainID       uint64
+	Block         uint64                // Snapshot block
+	Admins        map[Role]Role         // Admin role of each role
+	Holders       map[Role][]RoleHolder // Current holders of each role, ordered by account
+	Discrepancies []RoleDiscrepancy     // Empty when the replay matches hasRole
+}
+
+// HasRole reports whether an account holds a role in the report
+func (r *RoleReport) HasRole(role Role, account common.Address) bool {
+	for _, holder := range r.Holders[role] {
+		if holder.Account == account {
+			return true
+		}
+	}
+	return false
+}
+
+// roleEvent is a decoded RoleGranted, RoleRevoked or RoleAdminChanged log
+type roleEvent struct {
+	log   types.Log
+	apply func(state *roleState)
+}
+
+// roleState accumulates role membership during the replay
+type roleState struct {
+	holders map[Role]map[common.Address]RoleHolder
+	admins  map[Role]Role
+	touched map[Role]map[common.Address]bool // Every account granted or revoked each role
+}
+
+func (s *roleState) touch(role Role, account common.Address) {
+	if s.holders[role] == nil {
+		s.holders[role] = make(map[common.Address]RoleHolder)
+		s.touched[role] = make(map[common.Address]bool)
+	}
+	s.touched[role][account] = true
+}
+
+// InspectRoles reconstructs who holds each role by replaying RoleGranted, RoleRevoked and
+// RoleAdminChanged events, then cross-checks every account seen in the events with hasRole
+// at the snapshot block. Roles missing from Roles() are reported under their hex identifier.
+func (c *Client) InspectRoles(ctx context.Context, chainID uint64, opts *RoleInspectOptions) (*RoleReport, error) {
+	if opts == nil {
+		opts = &RoleInspectOptions{}
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = maxLogRange
+	}
+
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var toBlock uint64
+	if opts.ToBlock != nil {
+		toBlock = *opts.ToBlock
+	} else {
+		backend, err := c.GetBackend(chainID)
+		if err != nil {
+			return nil, err
+		}
+		if toBlock, err = backend.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get block number: %w", err)
+		}
+	}
+
+	ids := make(map[Role][32]byte)
+	names := make(map[[32]byte]Role)
+	for _, role := range Roles() {
+		id, err := c.RoleID(ctx, chainID, role)
+		if err != nil {
+			return nil, err
+		}
+		ids[role] = id
+		names[id] = role
+	}
+	roleName := func(id [32]byte) Role {
+		if role, known := names[id]; known {
+			return role
+		}
+		return Role(common.Hash(id).Hex())
+	}
+
+	state := &roleState{
+		holders: make(map[Role]map[common.Address]RoleHolder),
+		admins:  make(map[Role]Role),
+		touched: make(map[Role]map[common.Address]bool),
+	}
+	for _, role := range Roles() {
+		state.holders[role] = make(map[common.Address]RoleHolder)
+		state.touched[role] = make(map[common.Address]bool)
+		state.admins[role] = DefaultAdminRole
+	}
+
+	for from := opts.FromBlock; from <= toBlock; from += chunkSize {
+		to := min(from+chunkSize-1, toBlock)
+		events, err := fetchRoleEvents(ctx, &contract.IDRXFilterer, from, to, roleName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch role events for blocks %d-%d: %w", from, to, err)
+		}
+		for _, event := range events {
+			event.apply(state)
+		}
+	}
+
+	report := &RoleReport{
+		ChainID: chainID,
+		Block:   toBlock,
+		Admins:  state.admins,
+		Holders: make(map[Role][]RoleHolder, len(state.holders)),
+	}
+	for role, holders := range state.holders {
+		list := make([]RoleHolder, 0, len(holders))
+		for _, holder := range holders {
+			list = append(list, holder)
+		}
+		sort.Slice(list, func(i, j int) bool { return bytes.Compare(list[i].Account[:], list[j].Account[:]) < 0 })
+		report.Holders[role] = list
+	}
+
+	// Revoked accounts are checked too, so a missed RoleGranted event also shows up
+	at := callOpts(ctx, new(big.Int).SetUint64(toBlock))
+	for role, accounts := range state.touched {
+		id, known := ids[role]
+		if !known {
+			id = common.HexToHash(string(role))
+		}
+		for account := range accounts {
+			onChain, err := contract.HasRole(at, id, account)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check %s of %s: %w", role, account.Hex(), err)
+			}
+			_, replayed := state.holders[role][account]
+			if onChain != replayed {
+				report.Discrepancies = append(report.Discrepancies, RoleDiscrepancy{
+					Role:     role,
+					Account:  account,
+					Replayed: replayed,
+					OnChain:  onChain,
+				})
+			}
+		}
+	}
+	sort.Slice(report.Discrepancies, func(i, j int) bool {
+		a, b := report.Discrepancies[i], report.Discrepancies[j]
+		if a.Role != b.Role {
+			return a.Role < b.Role
+		}
+		return bytes.Compare(a.Account[:], b.Account[:]) < 0
+	})
+
+	return report, nil
+}
+
+// fetchRoleEvents returns the role events in a block range in the order they were emitted
+func fetchRoleEvents(
+	ctx context.Context,
+	filterer *contracts.IDRXFilterer,
+	from, to uint64,
+	roleName func([32]byte) Role,
+) ([]roleEvent, error) {
+	opts := &bind.FilterOpts{Start: from, End: &to, Context: ctx}
+	var events []roleEvent
+
+	granted, err := filterer.FilterRoleGranted(opts, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	for granted.Next() {
+		e := granted.Event
+		role := roleName(e.Role)
+		events = append(events, roleEvent{log: e.Raw, apply: func(s *roleState) {
+			s.touch(role, e.Account)
+			s.holders[role][e.Account] = RoleHolder{
+				Account:      e.Account,
+				GrantedBy:    e.Sender,
+				GrantedBlock: e.Raw.BlockNumber,
+				GrantedTx:    e.Raw.TxHash,
+			}
+		}})
+	}
+	if err := granted.Error(); err != nil {
+		return nil, err
+	}
+
+	revoked, err := filterer.FilterRoleRevoked(opts, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	for revoked.Next() {
+		e := revoked.Event
+		role := roleName(e.Role)
+		events = append(events, roleEvent{log: e.Raw, apply: func(s *roleState) {
+			s.touch(role, e.Account)
+			delete(s.holders[role], e.Account)
+		}})
+	}
+	if err := revoked.Error(); err != nil {
+		return nil, err
+	}
+
+	adminChanged, err := filterer.FilterRoleAdminChanged(opts, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	for adminChanged.Next() {
+		e := adminChanged.Event
+		role, admin := roleName(e.Role), roleName(e.NewAdminRole)
+		events = append(events, roleEvent{log: e.Raw, apply: func(s *roleState) {
+			s.admins[role] = admin
+		}})
+	}
+	if err := adminChanged.Error(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		a, b := events[i].log, events[j].log
+		if a.BlockNumber != b.BlockNumber {
+			return a.BlockNumber < b.BlockNumber
+		}
+		return a.Index < b.Index
+	})
+	return events, nil
+}
+
+// RoleDiff is a role assignment that is not the same on every compared chain
+type RoleDiff struct {
+	Role    Role
+	Account common.Address  // Zero when the difference is the admin role
+	Holders map[uint64]bool // Whether Account holds Role, by chain ID
+	Admins  map[uint64]Role // Admin role of Role, by chain ID; set only for admin differences
+}
+
+// DiffRoles compares role reports from different chains and returns every account that
+// holds a role on some chains but not others, and every role whose admin role differs.
+// Deployments configured identically produce no differences.
+func DiffRoles(reports ...*RoleReport) []RoleDiff {
+	var diffs []RoleDiff
+
+	roles := make(map[Role]bool)
+	for _, report := range reports {
+		for role := range report.Holders {
+			roles[role] = true
+		}
+		for role := range report.Admins {
+			roles[role] = true
+		}
+	}
+	sortedRoles := make([]Role, 0, len(roles))
+	for role := range roles {
+		sortedRoles = append(sortedRoles, role)
+	}
+	sort.Slice(sortedRoles, func(i, j int) bool { return sortedRoles[i] < sortedRoles[j] })
+
+	for _, role := range sortedRoles {
+		admins := make(map[uint64]Role, len(reports))
+		for _, report := range reports {
+			admins[report.ChainID] = report.Admins[role]
+		}
+		if !allEqual(admins) {
+			diffs = append(diffs, RoleDiff{Role: role, Admins: admins})
+		}
+
+		accounts := make(map[common.Address]bool)
+		for _, report := range reports {
+			for _, holder := range report.Holders[role] {
+				accounts[holder.Account] = true
+			}
+		}
+		sortedAccounts := make([]common.Address, 0, len(accounts))
+		for account := range accounts {
+			sortedAccounts = append(sortedAccounts, account)
+		}
+		sort.Slice(sortedAccounts, func(i, j int) bool { return bytes.Compare(sortedAccounts[i][:], sortedAccounts[j][:]) < 0 })
+
+		for _, account := range sortedAccounts {
+			holders := make(map[uint64]bool, len(reports))
+			for _, report := range reports {
+				holders[report.ChainID] = report.HasRole(role, account)
+			}
+			if !allEqual(holders) {
+				diffs = append(diffs, RoleDiff{Role: role, Account: account, Holders: holders})
+			}
+		}
+	}
+
+	return diffs
+}
+
+// allEqual reports whether every value in a map is the same
+func allEqual[T comparable](values map[uint64]T) bool {
+	var first T
+	initialised := false
+	for _, value := range values {
+		if !initialised {
+			first, initialised = value, true
+			continue
+		}
+		if value != first {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+// roleBackend serves role events from a fixed log and answers hasRole from a fixed set
+type roleBackend struct {
+	headerBackend
+
+	logs    []types.Log
+	onChain map[Role]map[common.Address]bool
+	queries int
+}
+
+func testRoleID(role Role) [32]byte {
+	if role == DefaultAdminRole {
+		return [32]byte{}
+	}
+	return crypto.Keccak256Hash([]byte(role))
+}
+
+func (r *roleBackend) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	r.queries++
+	var logs []types.Log
+	for _, log := range r.logs {
+		if log.BlockNumber < query.FromBlock.Uint64() || log.BlockNumber > query.ToBlock.Uint64() {
+			continue
+		}
+		if len(query.Topics) > 0 && len(query.Topics[0]) > 0 && query.Topics[0][0] != log.Topics[0] {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+func (r *roleBackend) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	method, err := parsed.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+
+	if method.Name != "hasRole" {
+		return method.Outputs.Pack(testRoleID(Role(method.Name)))
+	}
+	args, err := method.Inputs.Unpack(msg.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	for role, accounts := range r.onChain {
+		if testRoleID(role) == args[0].([32]byte) {
+			return method.Outputs.Pack(accounts[args[1].(common.Address)])
+		}
+	}
+	return method.Outputs.Pack(false)
+}
+
+// roleLog builds a role event log whose arguments are all indexed topics
+func roleLog(t *testing.T, name string, block uint64, index uint, topics ...common.Hash) types.Log {
+	t.Helper()
+
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+	return types.Log{
+		Address:     common.HexToAddress("0x00000000000000000000000000000000000000aa"),
+		Topics:      append([]common.Hash{parsed.Events[name].ID}, topics...),
+		BlockNumber: block,
+		Index:       index,
+	}
+}
+
+func TestInspectRoles(t *testing.T) {
+	alice := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	bob := common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	admin := common.HexToAddress("0x00000000000000000000000000000000000000ad")
+	minter := common.Hash(testRoleID(MinterRole))
+
+	backend := &roleBackend{
+		headerBackend: headerBackend{head: 20},
+		logs: []types.Log{
+			roleLog(t, "RoleGranted", 5, 0, minter, common.BytesToHash(alice[:]), common.BytesToHash(admin[:])),
+			roleLog(t, "RoleGranted", 7, 0, minter, common.BytesToHash(bob[:]), common.BytesToHash(admin[:])),
+			roleLog(t, "RoleAdminChanged", 8, 0, minter, common.Hash{}, common.Hash(testRoleID(PauserRole))),
+			// Granted and revoked in the same block; the revoke comes last
+			roleLog(t, "RoleRevoked", 9, 3, minter, common.BytesToHash(alice[:]), common.BytesToHash(admin[:])),
+			roleLog(t, "RoleGranted", 9, 1, minter, common.BytesToHash(alice[:]), common.BytesToHash(admin[:])),
+		},
+		// Alice still holds the role on chain, so the replay missed something
+		onChain: map[Role]map[common.Address]bool{MinterRole: {alice: true, bob: true}},
+	}
+	client := newHeaderClient(t, backend)
+
+	report, err := client.InspectRoles(context.Background(), 31337, &RoleInspectOptions{FromBlock: 3, ChunkSize: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Block != 20 {
+		t.Errorf("expected snapshot at block 20, got %d", report.Block)
+	}
+	// Blocks 3-20 in chunks of 4 is 5 chunks, with one query per event type
+	if backend.queries != 15 {
+		t.Errorf("expected 15 log queries, got %d", backend.queries)
+	}
+
+	holders := report.Holders[MinterRole]
+	if len(holders) != 1 || holders[0].Account != bob || holders[0].GrantedBlock != 7 || holders[0].GrantedBy != admin {
+		t.Errorf("expected bob as the only minter, got %+v", holders)
+	}
+	if report.Admins[MinterRole] != PauserRole || report.Admins[BlacklistRole] != DefaultAdminRole {
+		t.Errorf("unexpected admin roles: %v", report.Admins)
+	}
+
+	if len(report.Discrepancies) != 1 {
+		t.Fatalf("expected 1 discrepancy, got %+v", report.Discrepancies)
+	}
+	if d := report.Discrepancies[0]; d.Account != alice || d.Replayed || !d.OnChain {
+		t.Errorf("unexpected discrepancy: %+v", d)
+	}
+}
+
+func TestDiffRoles(t *testing.T) {
+	pauser := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	extra := common.HexToAddress("0x00000000000000000000000000000000000000b0")
+
+	base := &RoleReport{
+		ChainID: 8453,
+		Admins:  map[Role]Role{PauserRole: DefaultAdminRole},
+		Holders: map[Role][]RoleHolder{PauserRole: {{Account: pauser}}},
+	}
+	polygon := &RoleReport{
+		ChainID: 137,
+		Admins:  map[Role]Role{PauserRole: DefaultAdminRole},
+		Holders: map[Role][]RoleHolder{PauserRole: {{Account: pauser}}},
+	}
+	lisk := &RoleReport{
+		ChainID: 1135,
+		Admins:  map[Role]Role{PauserRole: MinterRole},
+		Holders: map[Role][]RoleHolder{PauserRole: {{Account: pauser}, {Account: extra}}},
+	}
+
+	if diffs := DiffRoles(base, polygon); len(diffs) != 0 {
+		t.Errorf("expected identical deployments to match, got %+v", diffs)
+	}
+
+	diffs := DiffRoles(base, polygon, lisk)
+	if len(diffs) != 2 {
+		t.Fatalf("expected 2 differences, got %+v", diffs)
+	}
+	if diffs[0].Admins[1135] != MinterRole || diffs[0].Account != (common.Address{}) {
+		t.Errorf("expected admin difference first, got %+v", diffs[0])
+	}
+	if diffs[1].Account != extra || !diffs[1].Holders[1135] || diffs[1].Holders[8453] {
+		t.Errorf("expected extra pauser on chain 1135 only, got %+v", diffs[1])
+	}
+}
@@ -0,0 +1,121 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Role is an IDRX access control role, named after its contract constant
+type Role string
+
+// Roles defined by the IDRX contract
+const (
+	DefaultAdminRole      Role = "DEFAULT_ADMIN_ROLE"
+	MinterRole            Role = "MINTER_ROLE"
+	PauserRole            Role = "PAUSER_ROLE"
+	BlacklistRole         Role = "BLACKLIST_ROLE"
+	PlatformFeeSetterRole Role = "PLATFORM_FEE_SETTER_ROLE"
+	UpgraderRole          Role = "UPGRADER_ROLE"
+)
+
+// Roles returns every role defined by the IDRX contract
+func Roles() []Role {
+	return []Role{DefaultAdminRole, MinterRole, PauserRole, BlacklistRole, PlatformFeeSetterRole, UpgraderRole}
+}
+
+// roleKey identifies a cached role identifier
+type roleKey struct {
+	chainID uint64
+	role    Role
+}
+
+// RoleID returns the identifier of a role on a chain, read from the contract constant
+// once and cached
+func (c *Client) RoleID(ctx context.Context, chainID uint64, role Role) ([32]byte, error) {
+	key := roleKey{chainID: chainID, role: role}
+	if id, cached := c.roleIDs.Load(key); cached {
+		return id.([32]byte), nil
+	}
+
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	opts := callOpts(ctx, nil)
+	var id [32]byte
+	switch role {
+	case DefaultAdminRole:
+		id, err = contract.DEFAULTADMINROLE(opts)
+	case MinterRole:
+		id, err = contract.MINTERROLE(opts)
+	case PauserRole:
+		id, err = contract.PAUSERROLE(opts)
+	case BlacklistRole:
+		id, err = contract.BLACKLISTROLE(opts)
+	case PlatformFeeSetterRole:
+		id, err = contract.PLATFORMFEESETTERROLE(opts)
+	case UpgraderRole:
+		id, err = contract.UPGRADERROLE(opts)
+	default:
+		return [32]byte{}, fmt.Errorf("unknown role %s", role)
+	}
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("failed to get %s identifier: %w", role, err)
+	}
+
+	c.roleIDs.Store(key, id)
+	return id, nil
+}
+
+// HasRole checks if an account holds a role on the specified chain
+func (c *Client) HasRole(ctx context.Context, chainID uint64, role Role, account common.Address) (bool, error) {
+	id, err := c.RoleID(ctx, chainID, role)
+	if err != nil {
+		return false, err
+	}
+
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return false, err
+	}
+
+	hasRole, err := contract.HasRole(callOpts(ctx, nil), id, account)
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s: %w", role, err)
+	}
+
+	return hasRole, nil
+}
+
+// RoleAdmin returns the role whose holders can grant and revoke role
+func (c *Client) RoleAdmin(ctx context.Context, chainID uint64, role Role) (Role, error) {
+	id, err := c.RoleID(ctx, chainID, role)
+	if err != nil {
+		return "", err
+	}
+
+	contract, err := c.GetContract(chainID)
+	if err != nil {
+		return "", err
+	}
+
+	adminID, err := contract.GetRoleAdmin(callOpts(ctx, nil), id)
+	if err != nil {
+		return "", fmt.Errorf("failed to get admin of %s: %w", role, err)
+	}
+
+	for _, candidate := range Roles() {
+		candidateID, err := c.RoleID(ctx, chainID, candidate)
+		if err != nil {
+			return "", err
+		}
+		if candidateID == adminID {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("admin of %s is an unknown role %s", role, common.Hash(adminID).Hex())
+}
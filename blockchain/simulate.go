@@ -0,0 +1,204 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+// Revert categories recognised from IDRX revert reasons and custom errors.
+// Use errors.Is on an error returned by a write or Simulate to test for them.
+var (
+	ErrPaused                = errors.New("contract is paused")
+	ErrBlacklisted           = errors.New("address is blacklisted")
+	ErrInsufficientBalance   = errors.New("insufficient balance")
+	ErrInsufficientAllowance = errors.New("insufficient allowance")
+	ErrMissingRole           = errors.New("account is missing role")
+)
+
+// RevertError is a contract execution revert decoded from the node's response
+type RevertError struct {
+	Reason string                 // Revert reason string, or the custom error name
+	Args   map[string]interface{} // Custom error arguments; "account" and "role" for missing role reverts
+	Data   []byte                 // Raw revert data, if the node returned it
+	Kind   error                  // One of the Err* revert categories, or nil if unrecognised
+}
+
+// Error implements the error interface
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return "execution reverted"
+	}
+	return "execution reverted: " + e.Reason
+}
+
+// Unwrap returns the revert category so errors.Is(err, ErrPaused) and friends work
+func (e *RevertError) Unwrap() error {
+	return e.Kind
+}
+
+// revertKinds maps lower-cased fragments of revert reasons and custom error names to categories.
+// Missing role comes first because AccessControl messages embed arbitrary role names.
+var revertKinds = []struct {
+	fragments []string
+	kind      error
+}{
+	{[]string{"is missing role", "accesscontrolunauthorizedaccount"}, ErrMissingRole},
+	{[]string{"blacklist", "black list"}, ErrBlacklisted},
+	{[]string{"pausable: paused", "while paused", "enforcedpause"}, ErrPaused},
+	{[]string{"exceeds balance", "insufficientbalance"}, ErrInsufficientBalance},
+	{[]string{"insufficient allowance", "insufficientallowance"}, ErrInsufficientAllowance},
+}
+
+// missingRolePattern matches OpenZeppelin AccessControl revert strings
+var missingRolePattern = regexp.MustCompile(`account (0x[0-9a-fA-F]{40}) is missing role (0x[0-9a-fA-F]{64})`)
+
+// Simulate executes a contract write as an eth_call from the client's address, without
+// signing or sending it. Method and args follow the IDRX ABI, e.g. "transfer", to, amount.
+// A revert is returned as a *RevertError; the call's return data is returned on success.
+func (c *Client) Simulate(ctx context.Context, chainID uint64, method string, args ...interface{}) ([]byte, error) {
+	backend, err := c.GetBackend(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	networkConfig, _, exists := c.registry.GetByChainID(chainID)
+	if !exists {
+		return nil, fmt.Errorf("chain ID %d not supported", chainID)
+	}
+
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse IDRX ABI: %w", err)
+	}
+
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s call: %w", method, err)
+	}
+
+	result, err := backend.CallContract(ctx, ethereum.CallMsg{
+		From: c.address,
+		To:   &networkConfig.ContractAddress,
+		Data: data,
+	}, nil)
+	if err != nil {
+		if revert := decodeRevert(err); revert != nil {
+			return nil, revert
+		}
+		return nil, fmt.Errorf("failed to simulate %s: %w", method, err)
+	}
+
+	return result, nil
+}
+
+// preflight simulates a write before it is sent when SimulateWrites is enabled
+func (c *Client) preflight(ctx context.Context, chainID uint64, method string, args ...interface{}) error {
+	if !c.simulateWrites {
+		return nil
+	}
+
+	_, err := c.Simulate(ctx, chainID, method, args...)
+	return err
+}
+
+// asRevertError returns the decoded revert for err, or err unchanged if it is not a revert
+func asRevertError(err error) error {
+	if revert := decodeRevert(err); revert != nil {
+		return revert
+	}
+	return err
+}
+
+// decodeRevert extracts a revert from an RPC error, returning nil if err is not a revert
+func decodeRevert(err error) *RevertError {
+	if err == nil {
+		return nil
+	}
+
+	var revert *RevertError
+	if errors.As(err, &revert) {
+		return revert
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if encoded, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(encoded); decodeErr == nil && len(data) > 0 {
+				return decodeRevertData(data)
+			}
+		}
+	}
+
+	// Gas estimation wraps the RPC error as text, so fall back to the message
+	message := err.Error()
+	index := strings.Index(message, "execution reverted")
+	if index < 0 {
+		return nil
+	}
+	reason := strings.TrimPrefix(message[index+len("execution reverted"):], ":")
+	return newRevertError(strings.TrimSpace(reason), nil, nil)
+}
+
+// decodeRevertData decodes Error(string), Panic(uint256) and IDRX custom errors
+func decodeRevertData(data []byte) *RevertError {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return newRevertError(reason, nil, data)
+	}
+
+	if len(data) >= 4 {
+		parsed, err := contracts.IDRXMetaData.GetAbi()
+		if err == nil {
+			var selector [4]byte
+			copy(selector[:], data[:4])
+			if customErr, err := parsed.ErrorByID(selector); err == nil {
+				args := make(map[string]interface{})
+				if err := customErr.Inputs.UnpackIntoMap(args, data[4:]); err != nil {
+					args = nil
+				}
+				return newRevertError(customErr.Name, args, data)
+			}
+		}
+	}
+
+	return newRevertError("", nil, data)
+}
+
+// newRevertError builds a RevertError and classifies it by reason
+func newRevertError(reason string, args map[string]interface{}, data []byte) *RevertError {
+	revert := &RevertError{Reason: reason, Args: args, Data: data}
+
+	lower := strings.ToLower(reason)
+	for _, candidate := range revertKinds {
+		for _, fragment := range candidate.fragments {
+			if strings.Contains(lower, fragment) {
+				revert.Kind = candidate.kind
+				break
+			}
+		}
+		if revert.Kind != nil {
+			break
+		}
+	}
+
+	if revert.Kind == ErrMissingRole && revert.Args == nil {
+		if match := missingRolePattern.FindStringSubmatch(reason); match != nil {
+			revert.Args = map[string]interface{}{
+				"account": common.HexToAddress(match[1]),
+				"role":    common.HexToHash(match[2]),
+			}
+		}
+	}
+
+	return revert
+}
@@ -0,0 +1,156 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
+)
+
+// revertDataError is an RPC error carrying ABI-encoded revert data
+type revertDataError struct {
+	data []byte
+}
+
+func (e revertDataError) Error() string          { return "execution reverted" }
+func (e revertDataError) ErrorData() interface{} { return hexutil.Encode(e.data) }
+
+// encodeRevertReason ABI-encodes an Error(string) revert
+func encodeRevertReason(t *testing.T, reason string) []byte {
+	t.Helper()
+
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		t.Fatalf("failed to create ABI type: %v", err)
+	}
+	packed, err := abi.Arguments{{Type: stringType}}.Pack(reason)
+	if err != nil {
+		t.Fatalf("failed to pack revert reason: %v", err)
+	}
+
+	return append(crypto.Keccak256([]byte("Error(string)"))[:4], packed...)
+}
+
+func TestDecodeRevertClassifiesReasons(t *testing.T) {
+	tests := []struct {
+		reason string
+		kind   error
+	}{
+		{"Pausable: paused", ErrPaused},
+		{"ERC20Pausable: token transfer while paused", ErrPaused},
+		{"Blacklistable: account is blacklisted", ErrBlacklisted},
+		{"ERC20: transfer amount exceeds balance", ErrInsufficientBalance},
+		{"ERC20: burn amount exceeds balance", ErrInsufficientBalance},
+		{"ERC20: insufficient allowance", ErrInsufficientAllowance},
+		{"something else", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			revert := decodeRevert(revertDataError{data: encodeRevertReason(t, tt.reason)})
+			if revert == nil {
+				t.Fatal("expected revert to be decoded")
+			}
+			if revert.Reason != tt.reason {
+				t.Errorf("expected reason %q, got %q", tt.reason, revert.Reason)
+			}
+			if revert.Kind != tt.kind {
+				t.Errorf("expected kind %v, got %v", tt.kind, revert.Kind)
+			}
+		})
+	}
+}
+
+func TestDecodeRevertMissingRole(t *testing.T) {
+	reason := "AccessControl: account 0x00000000000000000000000000000000000000cc is missing role " +
+		"0x9f2df0fed2c77648de5860a4cc508cd0818c85b8b8a1ab4ceeef8d981c8956a6"
+
+	err := asRevertError(revertDataError{data: encodeRevertReason(t, reason)})
+	if !errors.Is(err, ErrMissingRole) {
+		t.Fatalf("expected ErrMissingRole, got: %v", err)
+	}
+
+	var revert *RevertError
+	if !errors.As(err, &revert) {
+		t.Fatalf("expected *RevertError, got %T", err)
+	}
+	if revert.Args["account"] != common.HexToAddress("0x00000000000000000000000000000000000000cc") {
+		t.Errorf("unexpected account: %v", revert.Args["account"])
+	}
+	if revert.Args["role"] != crypto.Keccak256Hash([]byte("MINTER_ROLE")) {
+		t.Errorf("unexpected role: %v", revert.Args["role"])
+	}
+}
+
+func TestDecodeRevertFromMessage(t *testing.T) {
+	// Gas estimation failures only carry the reason in the error text
+	err := errors.New("failed to estimate gas needed: execution reverted: Pausable: paused")
+
+	revert := decodeRevert(err)
+	if revert == nil || revert.Kind != ErrPaused {
+		t.Fatalf("expected paused revert, got: %v", revert)
+	}
+
+	if decodeRevert(errors.New("connection refused")) != nil {
+		t.Error("non-revert errors should not be decoded")
+	}
+}
+
+func TestSimulateReturnsTypedRevert(t *testing.T) {
+	server := newTestRPCServer(t, 31337, map[string]rpcHandler{
+		"eth_call": func(_ []json.RawMessage) (any, error) {
+			return nil, revertDataError{data: encodeRevertReason(t, "ERC20: transfer amount exceeds balance")}
+		},
+	})
+	client := newTestDevnetClient(t, server.URL, 2)
+
+	to := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	_, err := client.Simulate(context.Background(), 31337, "transfer", to, big.NewInt(100))
+	if !errors.Is(err, ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance, got: %v", err)
+	}
+
+	if _, err := client.Simulate(context.Background(), 31337, "noSuchMethod"); err == nil {
+		t.Error("expected error for unknown method")
+	}
+}
+
+func TestSimulateWritesRejectsBeforeSigning(t *testing.T) {
+	var sent bool
+	server := newTestRPCServer(t, 31337, map[string]rpcHandler{
+		"eth_call": func(_ []json.RawMessage) (any, error) {
+			return nil, revertDataError{data: encodeRevertReason(t, "Pausable: paused")}
+		},
+		"eth_sendRawTransaction": func(_ []json.RawMessage) (any, error) {
+			sent = true
+			return nil, errors.New("unexpected send")
+		},
+	})
+
+	registry := NewRegistry()
+	devnet := DevnetConfig(31337, server.URL, common.HexToAddress("0x00000000000000000000000000000000000000aa"), 2)
+	if err := registry.Register("Devnet", devnet); err != nil {
+		t.Fatalf("failed to register devnet: %v", err)
+	}
+	client, err := NewClient(&ClientConfig{PrivateKeyHex: testPrivateKey, Registry: registry, SimulateWrites: true})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	amount := &TokenAmount{Amount: decimal.NewFromInt(5), Decimals: 2}
+	_, err = client.Transfer(context.Background(), 31337, common.HexToAddress("0x00000000000000000000000000000000000000cc"), amount)
+	if !errors.Is(err, ErrPaused) {
+		t.Fatalf("expected ErrPaused from pre-check, got: %v", err)
+	}
+	if sent {
+		t.Error("transaction should not be sent when the pre-check fails")
+	}
+}
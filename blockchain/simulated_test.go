@@ -0,0 +1,276 @@
+package blockchain_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/blockchain/internal/simbackend"
+)
+
+// newHarness deploys IDRX on a simulated chain. The tests are skipped until the compiled
+// contracts are committed: they are meant to check the SDK against the real IDRX code.
+func newHarness(t *testing.T) *simbackend.Harness {
+	t.Helper()
+
+	h, err := simbackend.New(nil)
+	if errors.Is(err, simbackend.ErrBytecodeUnavailable) {
+		t.Skipf("%v; add abis/idrx.bin and abis/proxy.bin and run make generate-contracts", err)
+	}
+	if err != nil {
+		t.Fatalf("failed to start simulated backend: %v", err)
+	}
+	t.Cleanup(h.Close)
+
+	return h
+}
+
+func tokens(t *testing.T, amount string) *blockchain.TokenAmount {
+	t.Helper()
+	parsed, err := blockchain.ParseTokenAmount(amount, 2)
+	if err != nil {
+		t.Fatalf("invalid amount %q: %v", amount, err)
+	}
+	return parsed
+}
+
+func mustReceipt(t *testing.T, h *simbackend.Harness, tx *types.Transaction) *types.Receipt {
+	t.Helper()
+	receipt, err := h.Receipt(tx)
+	if err != nil {
+		t.Fatalf("failed to get receipt: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("transaction %s reverted", tx.Hash().Hex())
+	}
+	return receipt
+}
+
+func assertBalance(t *testing.T, h *simbackend.Harness, address common.Address, expected string) {
+	t.Helper()
+	balance, err := h.Client.BalanceOf(context.Background(), h.ChainID(), address)
+	if err != nil {
+		t.Fatalf("failed to get balance: %v", err)
+	}
+	if !balance.Amount.Equal(decimal.RequireFromString(expected)) {
+		t.Errorf("expected balance %s for %s, got %s", expected, address.Hex(), balance.Amount)
+	}
+}
+
+func TestSimulatedTransfer(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+
+	if err := h.Mint(h.User, tokens(t, "100").ToWei()); err != nil {
+		t.Fatalf("failed to mint: %v", err)
+	}
+
+	tx, err := h.Client.Transfer(ctx, h.ChainID(), recipient, tokens(t, "25.50"))
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+	receipt := mustReceipt(t, h, tx)
+
+	assertBalance(t, h, h.User, "74.5")
+	assertBalance(t, h, recipient, "25.5")
+
+	var found bool
+	for _, log := range receipt.Logs {
+		event, err := h.Contract.ParseTransfer(*log)
+		if err != nil {
+			continue
+		}
+		if event.From == h.User && event.To == recipient && event.Value.Cmp(big.NewInt(2550)) == 0 {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected Transfer event for the transfer")
+	}
+}
+
+func TestSimulatedTransferBlacklisted(t *testing.T) {
+	h := newHarness(t)
+
+	if err := h.Mint(h.User, tokens(t, "100").ToWei()); err != nil {
+		t.Fatalf("failed to mint: %v", err)
+	}
+	if err := h.Blacklist(h.User); err != nil {
+		t.Fatalf("failed to blacklist: %v", err)
+	}
+
+	blacklisted, err := h.Client.IsBlacklisted(context.Background(), h.ChainID(), h.User)
+	if err != nil {
+		t.Fatalf("failed to read blacklist status: %v", err)
+	}
+	if !blacklisted {
+		t.Fatal("expected user to be blacklisted")
+	}
+
+	if _, err := h.Client.Transfer(context.Background(), h.ChainID(), h.Admin, tokens(t, "1")); !errors.Is(err, blockchain.ErrBlacklisted) {
+		t.Errorf("expected ErrBlacklisted for a transfer from a blacklisted account, got: %v", err)
+	}
+}
+
+func TestSimulatedTransferPaused(t *testing.T) {
+	h := newHarness(t)
+
+	if err := h.Mint(h.User, tokens(t, "100").ToWei()); err != nil {
+		t.Fatalf("failed to mint: %v", err)
+	}
+	if err := h.Pause(); err != nil {
+		t.Fatalf("failed to pause: %v", err)
+	}
+
+	if _, err := h.Client.Transfer(context.Background(), h.ChainID(), h.Admin, tokens(t, "1")); !errors.Is(err, blockchain.ErrPaused) {
+		t.Errorf("expected ErrPaused for a transfer while paused, got: %v", err)
+	}
+
+	if err := h.Unpause(); err != nil {
+		t.Fatalf("failed to unpause: %v", err)
+	}
+	tx, err := h.Client.Transfer(context.Background(), h.ChainID(), h.Admin, tokens(t, "1"))
+	if err != nil {
+		t.Fatalf("expected transfer to succeed after unpause: %v", err)
+	}
+	mustReceipt(t, h, tx)
+}
+
+func TestSimulatedBurn(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	if err := h.Mint(h.User, tokens(t, "100").ToWei()); err != nil {
+		t.Fatalf("failed to mint: %v", err)
+	}
+
+	tx, err := h.Client.Burn(ctx, h.ChainID(), tokens(t, "40"))
+	if err != nil {
+		t.Fatalf("burn failed: %v", err)
+	}
+	mustReceipt(t, h, tx)
+
+	assertBalance(t, h, h.User, "60")
+
+	supply, err := h.Client.TotalSupply(ctx, h.ChainID())
+	if err != nil {
+		t.Fatalf("failed to get total supply: %v", err)
+	}
+	if !supply.Amount.Equal(decimal.NewFromInt(60)) {
+		t.Errorf("expected total supply 60, got %s", supply.Amount)
+	}
+}
+
+func TestSimulatedBurnBridgeAndMintBridge(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	if err := h.Mint(h.User, tokens(t, "100").ToWei()); err != nil {
+		t.Fatalf("failed to mint: %v", err)
+	}
+
+	tx, err := h.Client.BurnBridge(ctx, &blockchain.BridgeRequest{
+		Amount:      tokens(t, "30"),
+		ToChainID:   blockchain.PolygonChainID,
+		ToAddress:   h.User,
+		FromChainID: h.ChainID(),
+	})
+	if err != nil {
+		t.Fatalf("burn bridge failed: %v", err)
+	}
+	receipt := mustReceipt(t, h, tx)
+
+	var burn bool
+	for _, log := range receipt.Logs {
+		event, err := h.Contract.ParseBurnBridge(*log)
+		if err != nil {
+			continue
+		}
+		if event.User == h.User && event.ToChain.Uint64() == blockchain.PolygonChainID {
+			burn = true
+		}
+	}
+	if !burn {
+		t.Fatal("expected BurnBridge event in receipt")
+	}
+	assertBalance(t, h, h.User, "70")
+
+	// Minting the bridged amount requires MINTER_ROLE on the client account
+	nonce := big.NewInt(1)
+	_, err = h.Client.MintBridge(ctx, h.ChainID(), h.User, tokens(t, "30"), blockchain.PolygonChainID, nonce)
+	var revert *blockchain.RevertError
+	if !errors.As(err, &revert) || !errors.Is(err, blockchain.ErrMissingRole) || revert.Args["account"] != h.User {
+		t.Fatalf("expected ErrMissingRole for the user, got: %v", err)
+	}
+	if err := h.GrantMinterRole(h.User); err != nil {
+		t.Fatalf("failed to grant minter role: %v", err)
+	}
+
+	tx, err = h.Client.MintBridge(ctx, h.ChainID(), h.User, tokens(t, "30"), blockchain.PolygonChainID, nonce)
+	if err != nil {
+		t.Fatalf("mint bridge failed: %v", err)
+	}
+	mustReceipt(t, h, tx)
+
+	used, err := h.Client.IsNonceUsed(ctx, h.ChainID(), blockchain.PolygonChainID, nonce)
+	if err != nil {
+		t.Fatalf("failed to check nonce: %v", err)
+	}
+	if !used {
+		t.Error("expected bridge nonce to be marked used")
+	}
+	assertBalance(t, h, h.User, "100")
+
+	if _, err := h.Client.MintBridge(ctx, h.ChainID(), h.User, tokens(t, "30"), blockchain.PolygonChainID, nonce); err == nil {
+		t.Error("expected replayed bridge nonce to be rejected")
+	}
+}
+
+func TestSimulatedBurnBridgeChargesPlatformFee(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+	feeRecipient := common.HexToAddress("0x00000000000000000000000000000000000000fe")
+
+	if err := h.Mint(h.User, tokens(t, "100").ToWei()); err != nil {
+		t.Fatalf("failed to mint: %v", err)
+	}
+	// 1% on bridge burns
+	if err := h.SetPlatformFeeInfo(feeRecipient, 100, 0); err != nil {
+		t.Fatalf("failed to set platform fee: %v", err)
+	}
+
+	tx, err := h.Client.BurnBridge(ctx, &blockchain.BridgeRequest{
+		Amount:      tokens(t, "30"),
+		ToChainID:   blockchain.PolygonChainID,
+		ToAddress:   h.User,
+		FromChainID: h.ChainID(),
+	})
+	if err != nil {
+		t.Fatalf("burn bridge failed: %v", err)
+	}
+	receipt := mustReceipt(t, h, tx)
+
+	var found bool
+	for _, log := range receipt.Logs {
+		event, err := h.Contract.ParseBurnBridge(*log)
+		if err != nil {
+			continue
+		}
+		found = true
+		if event.PlatformFee.Int64() != 30 || event.AmountAfterCut.Int64() != 2970 {
+			t.Errorf("expected a fee of 30 and 2970 burned, got %s and %s", event.PlatformFee, event.AmountAfterCut)
+		}
+	}
+	if !found {
+		t.Fatal("expected BurnBridge event in receipt")
+	}
+	assertBalance(t, h, h.User, "70")
+	assertBalance(t, h, feeRecipient, "0.3")
+}
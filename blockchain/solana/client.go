@@ -0,0 +1,508 @@
+// Package solana provides IDRX SPL token operations on Solana over JSON-RPC: balances across
+// token accounts, transfers that create the recipient's associated token account, and burns
+// for fiat redemption.
+package solana
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/blockchain/internal/base58"
+)
+
+// SolanaMainnet is the network name of Solana mainnet-beta
+const SolanaMainnet = "SolanaMainnet"
+
+// NetworkConfig describes an IDRX SPL token deployment
+type NetworkConfig struct {
+	Name         string
+	RPCEndpoints []string  // Tried in order when an endpoint cannot be reached
+	Mint         PublicKey // IDRX mint account
+	TokenProgram PublicKey // Program that owns the mint (defaults to TokenProgramID)
+	Commitment   string    // Commitment for reads and preflight (defaults to "confirmed")
+	BlockTime    time.Duration
+	IsTestnet    bool
+}
+
+// MainnetConfig returns a mainnet-beta configuration for the IDRX mint. Without endpoints
+// the public RPC is used, which is rate limited; pass a dedicated endpoint in production.
+func MainnetConfig(mint PublicKey, rpcEndpoints ...string) *NetworkConfig {
+	if len(rpcEndpoints) == 0 {
+		rpcEndpoints = []string{"https://api.mainnet-beta.solana.com"}
+	}
+	return &NetworkConfig{
+		Name:         "Solana",
+		RPCEndpoints: rpcEndpoints,
+		Mint:         mint,
+		TokenProgram: TokenProgramID,
+		Commitment:   "confirmed",
+		BlockTime:    400 * time.Millisecond,
+	}
+}
+
+// DevnetConfig returns a configuration for Solana devnet or a local test validator
+func DevnetConfig(mint PublicKey, rpcEndpoints ...string) *NetworkConfig {
+	if len(rpcEndpoints) == 0 {
+		rpcEndpoints = []string{"https://api.devnet.solana.com"}
+	}
+	config := MainnetConfig(mint, rpcEndpoints...)
+	config.Name = "Solana Devnet"
+	config.IsTestnet = true
+	return config
+}
+
+// ClientConfig configures a Client
+type ClientConfig struct {
+	PrivateKey ed25519.PrivateKey        // Signing key (nil for a read-only client)
+	Networks   map[string]*NetworkConfig // Keyed by network name, e.g. SolanaMainnet
+	Timeout    time.Duration             // RPC request timeout (defaults to 30 seconds)
+	HTTPClient *http.Client              // Optional; overrides Timeout
+}
+
+// Client performs IDRX SPL token operations on one or more Solana networks
+type Client struct {
+	privateKey ed25519.PrivateKey
+	address    PublicKey
+	networks   map[string]*network
+}
+
+// network is a configured network with its RPC client and cached mint decimals
+type network struct {
+	name   string
+	config NetworkConfig
+	rpc    *rpcClient
+
+	mu       sync.Mutex
+	decimals *uint8
+}
+
+// NewClient creates a Solana client
+func NewClient(config *ClientConfig) (*Client, error) {
+	if len(config.Networks) == 0 {
+		return nil, fmt.Errorf("no Solana networks configured")
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		timeout := config.Timeout
+		if timeout == 0 {
+			timeout = 30 * time.Second
+		}
+		httpClient = &http.Client{Timeout: timeout}
+	}
+
+	client := &Client{networks: make(map[string]*network, len(config.Networks))}
+	if config.PrivateKey != nil {
+		if len(config.PrivateKey) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("private key must be %d bytes, got %d", ed25519.PrivateKeySize, len(config.PrivateKey))
+		}
+		client.privateKey = config.PrivateKey
+		copy(client.address[:], config.PrivateKey.Public().(ed25519.PublicKey))
+	}
+
+	for name, networkConfig := range config.Networks {
+		if len(networkConfig.RPCEndpoints) == 0 {
+			return nil, fmt.Errorf("network %s has no RPC endpoints", name)
+		}
+		if networkConfig.Mint.IsZero() {
+			return nil, fmt.Errorf("network %s has no mint address", name)
+		}
+
+		resolved := *networkConfig
+		if resolved.TokenProgram.IsZero() {
+			resolved.TokenProgram = TokenProgramID
+		}
+		if resolved.Commitment == "" {
+			resolved.Commitment = "confirmed"
+		}
+		if resolved.BlockTime == 0 {
+			resolved.BlockTime = 400 * time.Millisecond
+		}
+
+		client.networks[name] = &network{
+			name:   name,
+			config: resolved,
+			rpc:    &rpcClient{endpoints: resolved.RPCEndpoints, httpClient: httpClient},
+		}
+	}
+
+	return client, nil
+}
+
+// Address returns the wallet address of the signing key (zero for a read-only client)
+func (c *Client) Address() PublicKey {
+	return c.address
+}
+
+// Networks returns the configured network names in sorted order
+func (c *Client) Networks() []string {
+	names := make([]string, 0, len(c.networks))
+	for name := range c.networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasNetwork reports whether a network name is configured
+func (c *Client) HasNetwork(networkName string) bool {
+	_, exists := c.networks[networkName]
+	return exists
+}
+
+// Network returns a copy of a network's configuration
+func (c *Client) Network(networkName string) (*NetworkConfig, bool) {
+	n, exists := c.networks[networkName]
+	if !exists {
+		return nil, false
+	}
+	config := n.config
+	return &config, true
+}
+
+// getNetwork resolves a network name
+func (c *Client) getNetwork(networkName string) (*network, error) {
+	n, exists := c.networks[networkName]
+	if !exists {
+		return nil, fmt.Errorf("network %s not supported", networkName)
+	}
+	return n, nil
+}
+
+// Decimals reads the mint's decimals once per network
+func (c *Client) Decimals(ctx context.Context, networkName string) (uint8, error) {
+	n, err := c.getNetwork(networkName)
+	if err != nil {
+		return 0, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.decimals != nil {
+		return *n.decimals, nil
+	}
+
+	var supply struct {
+		Value struct {
+			Decimals uint8 `json:"decimals"`
+		} `json:"value"`
+	}
+	if err := n.rpc.call(ctx, "getTokenSupply", &supply, n.config.Mint.String(), n.commitment()); err != nil {
+		return 0, fmt.Errorf("failed to get token decimals for %s: %w", networkName, err)
+	}
+	n.decimals = &supply.Value.Decimals
+	return supply.Value.Decimals, nil
+}
+
+// BalanceOf returns the IDRX balance of a wallet, summed over all its token accounts for the
+// mint. Transfer and BurnWithAccountNumber spend only from the associated token account, so
+// they can fail while tokens held in other accounts make up the balance.
+func (c *Client) BalanceOf(ctx context.Context, networkName string, owner PublicKey) (*blockchain.TokenAmount, error) {
+	n, err := c.getNetwork(networkName)
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := c.Decimals(ctx, networkName)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts struct {
+		Value []struct {
+			Pubkey  string `json:"pubkey"`
+			Account struct {
+				Data struct {
+					Parsed struct {
+						Info struct {
+							TokenAmount struct {
+								Amount string `json:"amount"`
+							} `json:"tokenAmount"`
+						} `json:"info"`
+					} `json:"parsed"`
+				} `json:"data"`
+			} `json:"account"`
+		} `json:"value"`
+	}
+	err = n.rpc.call(ctx, "getTokenAccountsByOwner", &accounts,
+		owner.String(),
+		map[string]string{"mint": n.config.Mint.String()},
+		map[string]string{"encoding": "jsonParsed", "commitment": n.config.Commitment},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token accounts: %w", err)
+	}
+
+	total := new(big.Int)
+	for _, account := range accounts.Value {
+		amount, ok := new(big.Int).SetString(account.Account.Data.Parsed.Info.TokenAmount.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid amount in token account %s", account.Pubkey)
+		}
+		total.Add(total, amount)
+	}
+
+	return blockchain.FromWei(total, int32(decimals)), nil
+}
+
+// Transfer sends IDRX from the wallet's associated token account to another wallet, creating
+// the recipient's associated token account first if it has none. The sender pays the fee and
+// any account rent.
+func (c *Client) Transfer(ctx context.Context, networkName string, to PublicKey, amount *blockchain.TokenAmount) (Signature, error) {
+	n, err := c.getNetwork(networkName)
+	if err != nil {
+		return Signature{}, err
+	}
+	if c.privateKey == nil {
+		return Signature{}, blockchain.ErrReadOnly
+	}
+	decimals, units, err := c.units(ctx, networkName, amount)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	mint, program := n.config.Mint, n.config.TokenProgram
+	source, err := FindAssociatedTokenAddress(c.address, mint, program)
+	if err != nil {
+		return Signature{}, err
+	}
+	destination, err := FindAssociatedTokenAddress(to, mint, program)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	exists, err := n.accountExists(ctx, destination)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	var instructions []instruction
+	if !exists {
+		instructions = append(instructions, createAssociatedTokenAccount(c.address, destination, to, mint, program))
+	}
+	instructions = append(instructions, transferChecked(source, mint, destination, c.address, program, units, decimals))
+
+	signature, err := c.send(ctx, n, instructions)
+	if err != nil {
+		return Signature{}, fmt.Errorf("failed to transfer tokens: %w", err)
+	}
+	return signature, nil
+}
+
+// BurnWithAccountNumber burns IDRX from the wallet's associated token account for fiat
+// redemption. Memos are public, so only HashAccountNumber of the bank account number is
+// attached as an SPL memo, never the number itself.
+func (c *Client) BurnWithAccountNumber(
+	ctx context.Context,
+	networkName string,
+	amount *blockchain.TokenAmount,
+	accountNumber string,
+) (Signature, error) {
+	n, err := c.getNetwork(networkName)
+	if err != nil {
+		return Signature{}, err
+	}
+	if c.privateKey == nil {
+		return Signature{}, blockchain.ErrReadOnly
+	}
+	if accountNumber == "" {
+		return Signature{}, fmt.Errorf("account number is required")
+	}
+	decimals, units, err := c.units(ctx, networkName, amount)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	source, err := FindAssociatedTokenAddress(c.address, n.config.Mint, n.config.TokenProgram)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	signature, err := c.send(ctx, n, []instruction{
+		burnChecked(source, n.config.Mint, c.address, n.config.TokenProgram, units, decimals),
+		memo(HashAccountNumber(accountNumber), c.address),
+	})
+	if err != nil {
+		return Signature{}, fmt.Errorf("failed to burn tokens with account number: %w", err)
+	}
+	return signature, nil
+}
+
+// HashAccountNumber returns the hex-encoded keccak256 hash of a bank account number, the form
+// in which BurnWithAccountNumber publishes it
+func HashAccountNumber(accountNumber string) string {
+	return crypto.Keccak256Hash([]byte(accountNumber)).Hex()
+}
+
+// units converts an amount to the mint's base units
+func (c *Client) units(ctx context.Context, networkName string, amount *blockchain.TokenAmount) (uint8, uint64, error) {
+	decimals, err := c.Decimals(ctx, networkName)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	value := amount.Amount
+	if !value.IsPositive() {
+		return 0, 0, fmt.Errorf("amount must be positive")
+	}
+	if !value.Equal(value.Truncate(int32(decimals))) {
+		return 0, 0, fmt.Errorf("amount has more than %d decimal places", decimals)
+	}
+
+	units := value.Shift(int32(decimals))
+	if units.GreaterThan(decimal.NewFromUint64(math.MaxUint64)) {
+		return 0, 0, fmt.Errorf("amount %s is too large", value)
+	}
+	return decimals, units.BigInt().Uint64(), nil
+}
+
+// send signs instructions with a fresh blockhash and submits the transaction
+func (c *Client) send(ctx context.Context, n *network, instructions []instruction) (Signature, error) {
+	var latest struct {
+		Value struct {
+			Blockhash string `json:"blockhash"`
+		} `json:"value"`
+	}
+	if err := n.rpc.call(ctx, "getLatestBlockhash", &latest, n.commitment()); err != nil {
+		return Signature{}, fmt.Errorf("failed to get latest blockhash: %w", err)
+	}
+	blockhash, err := ParsePublicKey(latest.Value.Blockhash)
+	if err != nil {
+		return Signature{}, fmt.Errorf("invalid blockhash: %w", err)
+	}
+
+	message, err := compileMessage(c.address, blockhash, instructions)
+	if err != nil {
+		return Signature{}, err
+	}
+	transaction, signature := signTransaction(message, c.privateKey)
+
+	var returned string
+	err = n.rpc.call(ctx, "sendTransaction", &returned,
+		base64.StdEncoding.EncodeToString(transaction),
+		map[string]string{"encoding": "base64", "preflightCommitment": n.config.Commitment},
+	)
+	if err != nil {
+		return Signature{}, err
+	}
+	if returned != signature.String() {
+		return Signature{}, fmt.Errorf("node returned signature %s for transaction %s", returned, signature)
+	}
+	return signature, nil
+}
+
+// accountExists reports whether an account has been created
+func (n *network) accountExists(ctx context.Context, account PublicKey) (bool, error) {
+	var info struct {
+		Value json.RawMessage `json:"value"`
+	}
+	err := n.rpc.call(ctx, "getAccountInfo", &info, account.String(),
+		map[string]string{"encoding": "base64", "commitment": n.config.Commitment})
+	if err != nil {
+		return false, fmt.Errorf("failed to get account %s: %w", account, err)
+	}
+	return len(info.Value) > 0 && string(info.Value) != "null", nil
+}
+
+// commitment is the configuration object for methods that only take a commitment
+func (n *network) commitment() map[string]string {
+	return map[string]string{"commitment": n.config.Commitment}
+}
+
+// ErrTransactionFailed is returned when a transaction landed but its execution failed
+var ErrTransactionFailed = errors.New("transaction failed")
+
+// SignatureStatus is the processing state of a transaction
+type SignatureStatus struct {
+	Slot               uint64          `json:"slot"`
+	Confirmations      *uint64         `json:"confirmations"` // Nil once finalized
+	Err                json.RawMessage `json:"err"`
+	ConfirmationStatus string          `json:"confirmationStatus"` // processed, confirmed or finalized
+}
+
+// SignatureStatus returns a transaction's status, or nil if the node has not seen it
+func (c *Client) SignatureStatus(ctx context.Context, networkName string, signature Signature) (*SignatureStatus, error) {
+	n, err := c.getNetwork(networkName)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses struct {
+		Value []*SignatureStatus `json:"value"`
+	}
+	err = n.rpc.call(ctx, "getSignatureStatuses", &statuses,
+		[]string{signature.String()},
+		map[string]bool{"searchTransactionHistory": true},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signature status: %w", err)
+	}
+	if len(statuses.Value) != 1 {
+		return nil, fmt.Errorf("node returned %d statuses for one signature", len(statuses.Value))
+	}
+	return statuses.Value[0], nil
+}
+
+// WaitForConfirmation polls until a transaction reaches the network's commitment level.
+// A transaction whose execution failed is returned with ErrTransactionFailed.
+func (c *Client) WaitForConfirmation(ctx context.Context, networkName string, signature Signature) (*SignatureStatus, error) {
+	n, err := c.getNetwork(networkName)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(n.config.BlockTime)
+	defer ticker.Stop()
+
+	for {
+		status, err := c.SignatureStatus(ctx, networkName, signature)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil {
+			if len(status.Err) > 0 && string(status.Err) != "null" {
+				return status, fmt.Errorf("%w: %s", ErrTransactionFailed, status.Err)
+			}
+			if reached(status.ConfirmationStatus, n.config.Commitment) {
+				return status, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// reached reports whether a confirmation status satisfies a commitment level
+func reached(status, commitment string) bool {
+	levels := map[string]int{"processed": 1, "confirmed": 2, "finalized": 3}
+	return levels[status] >= levels[commitment] && levels[status] > 0
+}
+
+// ParseSignature decodes a base58 transaction signature
+func ParseSignature(encoded string) (Signature, error) {
+	data, err := base58.Decode(encoded)
+	if err != nil {
+		return Signature{}, fmt.Errorf("invalid signature %s: %w", encoded, err)
+	}
+	if len(data) != 64 {
+		return Signature{}, fmt.Errorf("invalid signature %s: decodes to %d bytes", encoded, len(data))
+	}
+	return Signature(data), nil
+}
@@ -0,0 +1,359 @@
+package solana
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/blockchain"
+)
+
+var testMint = MustParsePublicKey("4zMMC9srt5Ri5X14GAgXhaHii3GnPAEERYPJgZJDncDU")
+
+// decodedInstruction is an instruction read back from a wire transaction
+type decodedInstruction struct {
+	program  PublicKey
+	accounts []PublicKey
+	data     []byte
+}
+
+// node is a mock JSON-RPC server holding token balances and recording sent transactions
+type node struct {
+	t        *testing.T
+	mu       sync.Mutex
+	balances map[PublicKey][]string // owner -> token account amounts
+	accounts map[PublicKey]bool     // existing accounts
+	sent     [][]decodedInstruction
+	signer   PublicKey
+	statuses []string // confirmation statuses returned by successive polls
+}
+
+func (n *node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var request struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		n.t.Errorf("bad request: %v", err)
+		return
+	}
+
+	var result interface{}
+	var rpcErr *RPCError
+	param := func(i int) string {
+		var s string
+		_ = json.Unmarshal(request.Params[i], &s)
+		return s
+	}
+
+	switch request.Method {
+	case "getTokenSupply":
+		result = map[string]interface{}{"value": map[string]interface{}{"amount": "1000", "decimals": 2}}
+	case "getTokenAccountsByOwner":
+		var accounts []interface{}
+		for i, amount := range n.balances[MustParsePublicKey(param(0))] {
+			accounts = append(accounts, map[string]interface{}{
+				"pubkey": PublicKey{byte(i + 1)}.String(),
+				"account": map[string]interface{}{"data": map[string]interface{}{"parsed": map[string]interface{}{
+					"info": map[string]interface{}{"tokenAmount": map[string]interface{}{"amount": amount}},
+				}}},
+			})
+		}
+		result = map[string]interface{}{"value": accounts}
+	case "getAccountInfo":
+		if n.accounts[MustParsePublicKey(param(0))] {
+			result = map[string]interface{}{"value": map[string]interface{}{"lamports": 2039280}}
+		} else {
+			result = map[string]interface{}{"value": nil}
+		}
+	case "getLatestBlockhash":
+		result = map[string]interface{}{"value": map[string]interface{}{"blockhash": PublicKey{9}.String()}}
+	case "sendTransaction":
+		raw, err := base64.StdEncoding.DecodeString(param(0))
+		if err != nil {
+			n.t.Fatalf("transaction is not base64: %v", err)
+		}
+		signature, instructions := n.decode(raw)
+		n.sent = append(n.sent, instructions)
+		result = signature.String()
+	case "getSignatureStatuses":
+		status := n.statuses[0]
+		if len(n.statuses) > 1 {
+			n.statuses = n.statuses[1:]
+		}
+		if status == "" {
+			result = map[string]interface{}{"value": []interface{}{nil}}
+		} else {
+			result = map[string]interface{}{"value": []interface{}{map[string]interface{}{
+				"slot": 100, "err": nil, "confirmationStatus": status,
+			}}}
+		}
+	default:
+		rpcErr = &RPCError{Code: -32601, Message: "Method not found"}
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result, "error": rpcErr})
+}
+
+// decode verifies the transaction's signature and returns its instructions
+func (n *node) decode(raw []byte) (Signature, []decodedInstruction) {
+	if raw[0] != 1 {
+		n.t.Fatalf("expected one signature, got %d", raw[0])
+	}
+	var signature Signature
+	copy(signature[:], raw[1:65])
+	message := raw[65:]
+
+	header, rest := message[:3], message[3:]
+	if header[0] != 1 {
+		n.t.Fatalf("expected one required signer, got %d", header[0])
+	}
+	keys := make([]PublicKey, rest[0])
+	rest = rest[1:]
+	for i := range keys {
+		copy(keys[i][:], rest[:32])
+		rest = rest[32:]
+	}
+	if keys[0] != n.signer {
+		n.t.Fatalf("fee payer is %s, want %s", keys[0], n.signer)
+	}
+	if !ed25519.Verify(keys[0][:], message, signature[:]) {
+		n.t.Fatal("invalid transaction signature")
+	}
+	rest = rest[32:] // recent blockhash
+
+	instructions := make([]decodedInstruction, rest[0])
+	rest = rest[1:]
+	for i := range instructions {
+		instructions[i].program = keys[rest[0]]
+		count := int(rest[1])
+		rest = rest[2:]
+		for _, index := range rest[:count] {
+			instructions[i].accounts = append(instructions[i].accounts, keys[index])
+		}
+		rest = rest[count:]
+		length := int(rest[0])
+		instructions[i].data = rest[1 : 1+length]
+		rest = rest[1+length:]
+	}
+	return signature, instructions
+}
+
+func newTestClient(t *testing.T, n *node, withKey bool) (*Client, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewServer(n)
+	t.Cleanup(server.Close)
+
+	config := &ClientConfig{Networks: map[string]*NetworkConfig{
+		SolanaMainnet: {
+			Name:         "Solana",
+			RPCEndpoints: []string{server.URL},
+			Mint:         testMint,
+			BlockTime:    time.Millisecond,
+		},
+	}}
+	if withKey {
+		config.PrivateKey = ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	}
+
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	n.signer = client.Address()
+	return client, server
+}
+
+func TestBalanceOfSumsTokenAccounts(t *testing.T) {
+	owner := PublicKey{7}
+	n := &node{t: t, balances: map[PublicKey][]string{owner: {"12345", "55"}}}
+	client, _ := newTestClient(t, n, false)
+
+	balance, err := client.BalanceOf(context.Background(), SolanaMainnet, owner)
+	if err != nil {
+		t.Fatalf("BalanceOf: %v", err)
+	}
+	if !balance.Amount.Equal(decimal.RequireFromString("124")) || balance.Decimals != 2 {
+		t.Errorf("balance = %s (%d decimals), want 124 (2 decimals)", balance.Amount, balance.Decimals)
+	}
+}
+
+func TestTransferCreatesMissingTokenAccount(t *testing.T) {
+	n := &node{t: t}
+	client, _ := newTestClient(t, n, true)
+	recipient := PublicKey{7}
+
+	amount := &blockchain.TokenAmount{Amount: decimal.RequireFromString("10.5")}
+	if _, err := client.Transfer(context.Background(), SolanaMainnet, recipient, amount); err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+
+	destination, _ := FindAssociatedTokenAddress(recipient, testMint, TokenProgramID)
+	instructions := n.sent[0]
+	if len(instructions) != 2 || instructions[0].program != AssociatedTokenAccountProgramID {
+		t.Fatalf("expected account creation then transfer, got %d instructions", len(instructions))
+	}
+	if instructions[0].accounts[1] != destination || instructions[0].accounts[2] != recipient {
+		t.Error("account creation targets the wrong token account")
+	}
+
+	transfer := instructions[1]
+	if transfer.program != TokenProgramID || transfer.data[0] != 12 {
+		t.Fatalf("expected TransferChecked, got program %s instruction %d", transfer.program, transfer.data[0])
+	}
+	if units := binary.LittleEndian.Uint64(transfer.data[1:9]); units != 1050 || transfer.data[9] != 2 {
+		t.Errorf("transfer of %d units at %d decimals, want 1050 at 2", units, transfer.data[9])
+	}
+	if transfer.accounts[2] != destination {
+		t.Error("transfer destination is not the recipient's associated token account")
+	}
+
+	// An existing account is not created again
+	n.accounts = map[PublicKey]bool{destination: true}
+	if _, err := client.Transfer(context.Background(), SolanaMainnet, recipient, amount); err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+	if len(n.sent[1]) != 1 {
+		t.Errorf("expected only the transfer instruction, got %d", len(n.sent[1]))
+	}
+}
+
+func TestBurnWithAccountNumberAttachesHashedMemo(t *testing.T) {
+	n := &node{t: t}
+	client, _ := newTestClient(t, n, true)
+
+	amount := &blockchain.TokenAmount{Amount: decimal.RequireFromString("250")}
+	if _, err := client.BurnWithAccountNumber(context.Background(), SolanaMainnet, amount, "1234567890"); err != nil {
+		t.Fatalf("BurnWithAccountNumber: %v", err)
+	}
+
+	instructions := n.sent[0]
+	if len(instructions) != 2 {
+		t.Fatalf("expected burn and memo, got %d instructions", len(instructions))
+	}
+	burn, note := instructions[0], instructions[1]
+	if burn.data[0] != 15 || binary.LittleEndian.Uint64(burn.data[1:9]) != 25000 {
+		t.Errorf("unexpected burn data %x", burn.data)
+	}
+	if note.program != MemoProgramID || string(note.data) != HashAccountNumber("1234567890") {
+		t.Errorf("memo = %q on %s", note.data, note.program)
+	}
+	if strings.Contains(string(note.data), "1234567890") {
+		t.Error("expected the account number to stay off chain")
+	}
+}
+
+func TestWritesRequireKeyAndValidAmount(t *testing.T) {
+	n := &node{t: t}
+	readOnly, _ := newTestClient(t, n, false)
+	amount := &blockchain.TokenAmount{Amount: decimal.RequireFromString("1")}
+
+	if _, err := readOnly.Transfer(context.Background(), SolanaMainnet, PublicKey{7}, amount); !errors.Is(err, blockchain.ErrReadOnly) {
+		t.Errorf("read-only Transfer error = %v, want ErrReadOnly", err)
+	}
+
+	client, _ := newTestClient(t, n, true)
+	for _, value := range []string{"0", "-1", "0.001", "184467440737095516.16"} {
+		amount := &blockchain.TokenAmount{Amount: decimal.RequireFromString(value)}
+		if _, err := client.Transfer(context.Background(), SolanaMainnet, PublicKey{7}, amount); err == nil {
+			t.Errorf("Transfer of %s succeeded", value)
+		}
+	}
+	if _, err := client.Transfer(context.Background(), "Unknown", PublicKey{7}, amount); err == nil {
+		t.Error("Transfer on an unknown network succeeded")
+	}
+	if len(n.sent) != 0 {
+		t.Errorf("%d transactions sent for invalid requests", len(n.sent))
+	}
+}
+
+func TestWaitForConfirmation(t *testing.T) {
+	n := &node{t: t, statuses: []string{"", "processed", "confirmed"}}
+	client, _ := newTestClient(t, n, false)
+
+	status, err := client.WaitForConfirmation(context.Background(), SolanaMainnet, Signature{1})
+	if err != nil {
+		t.Fatalf("WaitForConfirmation: %v", err)
+	}
+	if status.ConfirmationStatus != "confirmed" {
+		t.Errorf("status = %s, want confirmed", status.ConfirmationStatus)
+	}
+}
+
+func TestRPCFailover(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(&node{t: t})
+	defer up.Close()
+
+	client, err := NewClient(&ClientConfig{Networks: map[string]*NetworkConfig{
+		SolanaMainnet: MainnetConfig(testMint, down.URL, up.URL),
+	}})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	decimals, err := client.Decimals(context.Background(), SolanaMainnet)
+	if err != nil || decimals != 2 {
+		t.Errorf("Decimals = %d, %v; want 2 from the second endpoint", decimals, err)
+	}
+}
+
+func TestProgramAddressesAreOffCurve(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		wallet := ed25519.NewKeyFromSeed(append(make([]byte, 31), byte(i))).Public().(ed25519.PublicKey)
+		if !isOnCurve(PublicKey(wallet)) {
+			t.Fatalf("ed25519 public key %d reported off curve", i)
+		}
+
+		address, err := FindAssociatedTokenAddress(PublicKey(wallet), testMint, TokenProgramID)
+		if err != nil {
+			t.Fatalf("FindAssociatedTokenAddress: %v", err)
+		}
+		if isOnCurve(address) {
+			t.Fatalf("associated token address %s is on the curve", address)
+		}
+	}
+}
+
+func TestParsePrivateKeyFormats(t *testing.T) {
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	array := make([]int, len(key))
+	for i, b := range key {
+		array[i] = int(b)
+	}
+	encoded, _ := json.Marshal(array)
+
+	for _, input := range []string{string(encoded), Signature(key).String()} {
+		parsed, err := ParsePrivateKey(input)
+		if err != nil {
+			t.Fatalf("ParsePrivateKey: %v", err)
+		}
+		if !parsed.Equal(key) {
+			t.Error("parsed key differs")
+		}
+	}
+
+	tampered := append(ed25519.PrivateKey(nil), key...)
+	tampered[40] ^= 1
+	if _, err := ParsePrivateKey(Signature(tampered).String()); err == nil {
+		t.Error("key with mismatched public half accepted")
+	}
+}
@@ -0,0 +1,156 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/widnyana/idrx-go/blockchain/internal/base58"
+)
+
+// PublicKey is a Solana account address
+type PublicKey [32]byte
+
+// Well-known program IDs
+var (
+	SystemProgramID                 = MustParsePublicKey("11111111111111111111111111111111")
+	TokenProgramID                  = MustParsePublicKey("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	Token2022ProgramID              = MustParsePublicKey("TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb")
+	AssociatedTokenAccountProgramID = MustParsePublicKey("ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL")
+	MemoProgramID                   = MustParsePublicKey("MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr")
+)
+
+// ParsePublicKey decodes a base58 address
+func ParsePublicKey(address string) (PublicKey, error) {
+	data, err := base58.Decode(address)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("invalid Solana address %s: %w", address, err)
+	}
+	if len(data) != 32 {
+		return PublicKey{}, fmt.Errorf("invalid Solana address %s: decodes to %d bytes", address, len(data))
+	}
+	return PublicKey(data), nil
+}
+
+// MustParsePublicKey decodes a base58 address and panics if it is invalid
+func MustParsePublicKey(address string) PublicKey {
+	key, err := ParsePublicKey(address)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// String returns the base58 address
+func (k PublicKey) String() string {
+	return base58.Encode(k[:])
+}
+
+// IsZero reports whether the key is unset
+func (k PublicKey) IsZero() bool {
+	return k == PublicKey{}
+}
+
+// Signature identifies a Solana transaction
+type Signature [64]byte
+
+// String returns the base58 signature, as shown by explorers
+func (s Signature) String() string {
+	return base58.Encode(s[:])
+}
+
+// ParsePrivateKey reads a 64-byte ed25519 secret key, either base58-encoded as exported by
+// wallets or as the JSON byte array written by solana-keygen
+func ParsePrivateKey(encoded string) (ed25519.PrivateKey, error) {
+	encoded = strings.TrimSpace(encoded)
+
+	var data []byte
+	if strings.HasPrefix(encoded, "[") {
+		if err := json.Unmarshal([]byte(encoded), &data); err != nil {
+			return nil, fmt.Errorf("invalid keypair file: %w", err)
+		}
+	} else {
+		decoded, err := base58.Decode(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+		data = decoded
+	}
+
+	if len(data) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("private key must be %d bytes, got %d", ed25519.PrivateKeySize, len(data))
+	}
+	key := ed25519.NewKeyFromSeed(data[:ed25519.SeedSize])
+	if !key.Equal(ed25519.PrivateKey(data)) {
+		return nil, fmt.Errorf("private key does not match its public half")
+	}
+	return key, nil
+}
+
+// ErrNoProgramAddress is returned when no bump seed yields an address off the curve
+var ErrNoProgramAddress = errors.New("unable to find a viable program address")
+
+// FindProgramAddress derives the program-derived address for seeds, trying bump seeds from
+// 255 down until the address has no private key
+func FindProgramAddress(seeds [][]byte, programID PublicKey) (PublicKey, uint8, error) {
+	for bump := 255; bump >= 0; bump-- {
+		h := sha256.New()
+		for _, seed := range seeds {
+			h.Write(seed)
+		}
+		h.Write([]byte{byte(bump)})
+		h.Write(programID[:])
+		h.Write([]byte("ProgramDerivedAddress"))
+
+		var address PublicKey
+		copy(address[:], h.Sum(nil))
+		if !isOnCurve(address) {
+			return address, uint8(bump), nil
+		}
+	}
+	return PublicKey{}, 0, ErrNoProgramAddress
+}
+
+// FindAssociatedTokenAddress returns the associated token account of a wallet for a mint
+func FindAssociatedTokenAddress(wallet, mint, tokenProgram PublicKey) (PublicKey, error) {
+	address, _, err := FindProgramAddress([][]byte{wallet[:], tokenProgram[:], mint[:]}, AssociatedTokenAccountProgramID)
+	return address, err
+}
+
+// Edwards25519 field prime 2^255 - 19 and curve constant d = -121665/121666
+var (
+	fieldPrime = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	curveD     = func() *big.Int {
+		d := new(big.Int).ModInverse(big.NewInt(121666), fieldPrime)
+		d.Mul(d, big.NewInt(-121665))
+		return d.Mod(d, fieldPrime)
+	}()
+)
+
+// isOnCurve reports whether the bytes decompress to an ed25519 point: x^2 = (y^2 - 1) / (d*y^2 + 1)
+// must be a square modulo p. Non-canonical y values are reduced, as Solana's runtime does.
+func isOnCurve(key PublicKey) bool {
+	le := key
+	le[31] &= 0x7f
+	for i, j := 0, 31; i < j; i, j = i+1, j-1 {
+		le[i], le[j] = le[j], le[i]
+	}
+
+	y := new(big.Int).SetBytes(le[:])
+	y.Mod(y, fieldPrime)
+	y2 := new(big.Int).Mul(y, y)
+	y2.Mod(y2, fieldPrime)
+
+	u := new(big.Int).Sub(y2, big.NewInt(1))
+	v := new(big.Int).Mul(curveD, y2)
+	v.Add(v, big.NewInt(1))
+	v.ModInverse(v.Mod(v, fieldPrime), fieldPrime)
+
+	x2 := u.Mul(u, v)
+	x2.Mod(x2, fieldPrime)
+	return x2.Sign() == 0 || big.Jacobi(x2, fieldPrime) == 1
+}
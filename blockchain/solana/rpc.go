@@ -0,0 +1,104 @@
+package solana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// RPCError is an error returned by a Solana JSON-RPC node
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error implements the error interface
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("solana RPC error %d: %s", e.Code, e.Message)
+}
+
+// rpcClient sends JSON-RPC 2.0 requests to one network's endpoints, moving to the next
+// endpoint when one cannot be reached
+type rpcClient struct {
+	endpoints  []string
+	httpClient *http.Client
+	requestID  atomic.Uint64
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// call invokes method and decodes its result into result
+func (r *rpcClient) call(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: r.requestID.Add(1), Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
+	var lastErr error
+	for _, endpoint := range r.endpoints {
+		response, err := r.post(ctx, endpoint, body)
+		if err != nil {
+			lastErr = err
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		if response.Error != nil {
+			return response.Error
+		}
+		if result == nil {
+			return nil
+		}
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("failed to decode %s result: %w", method, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s failed: %w", method, lastErr)
+}
+
+// post sends one request to an endpoint
+func (r *rpcClient) post(ctx context.Context, endpoint string, body []byte) (*rpcResponse, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("endpoint %s returned HTTP %d", endpoint, response.StatusCode)
+	}
+
+	var decoded rpcResponse
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("invalid JSON-RPC response from %s: %w", endpoint, err)
+	}
+	return &decoded, nil
+}
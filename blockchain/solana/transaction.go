@@ -0,0 +1,171 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"fmt"
+)
+
+// accountMeta is an account an instruction reads or writes
+type accountMeta struct {
+	key      PublicKey
+	signer   bool
+	writable bool
+}
+
+// instruction is one program call in a transaction
+type instruction struct {
+	program  PublicKey
+	accounts []accountMeta
+	data     []byte
+}
+
+// createAssociatedTokenAccount creates a wallet's associated token account if it does not
+// exist yet (the idempotent variant), funded by payer
+func createAssociatedTokenAccount(payer, account, wallet, mint, tokenProgram PublicKey) instruction {
+	return instruction{
+		program: AssociatedTokenAccountProgramID,
+		accounts: []accountMeta{
+			{key: payer, signer: true, writable: true},
+			{key: account, writable: true},
+			{key: wallet},
+			{key: mint},
+			{key: SystemProgramID},
+			{key: tokenProgram},
+		},
+		data: []byte{1},
+	}
+}
+
+// transferChecked moves tokens between token accounts, checking the mint's decimals
+func transferChecked(source, mint, destination, owner, tokenProgram PublicKey, amount uint64, decimals uint8) instruction {
+	data := binary.LittleEndian.AppendUint64([]byte{12}, amount)
+	return instruction{
+		program: tokenProgram,
+		accounts: []accountMeta{
+			{key: source, writable: true},
+			{key: mint},
+			{key: destination, writable: true},
+			{key: owner, signer: true},
+		},
+		data: append(data, decimals),
+	}
+}
+
+// burnChecked destroys tokens from a token account, checking the mint's decimals
+func burnChecked(account, mint, owner, tokenProgram PublicKey, amount uint64, decimals uint8) instruction {
+	data := binary.LittleEndian.AppendUint64([]byte{15}, amount)
+	return instruction{
+		program: tokenProgram,
+		accounts: []accountMeta{
+			{key: account, writable: true},
+			{key: mint, writable: true},
+			{key: owner, signer: true},
+		},
+		data: append(data, decimals),
+	}
+}
+
+// memo attaches a UTF-8 note to a transaction, signed by signer
+func memo(text string, signer PublicKey) instruction {
+	return instruction{
+		program:  MemoProgramID,
+		accounts: []accountMeta{{key: signer, signer: true}},
+		data:     []byte(text),
+	}
+}
+
+// compileMessage serialises a legacy transaction message. Accounts are ordered as the
+// runtime requires: writable signers (fee payer first), read-only signers, writable
+// non-signers, then read-only non-signers including the programs.
+func compileMessage(payer PublicKey, recentBlockhash PublicKey, instructions []instruction) ([]byte, error) {
+	metas := map[PublicKey]*accountMeta{payer: {key: payer, signer: true, writable: true}}
+	order := []PublicKey{payer}
+	add := func(meta accountMeta) {
+		existing, ok := metas[meta.key]
+		if !ok {
+			copied := meta
+			metas[meta.key] = &copied
+			order = append(order, meta.key)
+			return
+		}
+		existing.signer = existing.signer || meta.signer
+		existing.writable = existing.writable || meta.writable
+	}
+	for _, ix := range instructions {
+		for _, account := range ix.accounts {
+			add(account)
+		}
+		add(accountMeta{key: ix.program})
+	}
+
+	var keys []PublicKey
+	var signers, readonlySigners, readonlyUnsigned int
+	for _, group := range []struct{ signer, writable bool }{{true, true}, {true, false}, {false, true}, {false, false}} {
+		for _, key := range order {
+			meta := metas[key]
+			if meta.signer != group.signer || meta.writable != group.writable {
+				continue
+			}
+			keys = append(keys, key)
+			switch {
+			case meta.signer && meta.writable:
+				signers++
+			case meta.signer:
+				signers++
+				readonlySigners++
+			case !meta.writable:
+				readonlyUnsigned++
+			}
+		}
+	}
+	if len(keys) > 256 {
+		return nil, fmt.Errorf("transaction references %d accounts", len(keys))
+	}
+
+	index := make(map[PublicKey]byte, len(keys))
+	for i, key := range keys {
+		index[key] = byte(i)
+	}
+
+	message := []byte{byte(signers), byte(readonlySigners), byte(readonlyUnsigned)}
+	message = appendCompactLength(message, len(keys))
+	for _, key := range keys {
+		message = append(message, key[:]...)
+	}
+	message = append(message, recentBlockhash[:]...)
+
+	message = appendCompactLength(message, len(instructions))
+	for _, ix := range instructions {
+		message = append(message, index[ix.program])
+		message = appendCompactLength(message, len(ix.accounts))
+		for _, account := range ix.accounts {
+			message = append(message, index[account.key])
+		}
+		message = appendCompactLength(message, len(ix.data))
+		message = append(message, ix.data...)
+	}
+	return message, nil
+}
+
+// signTransaction signs a message whose only signer is key and returns the wire transaction
+func signTransaction(message []byte, key ed25519.PrivateKey) ([]byte, Signature) {
+	var signature Signature
+	copy(signature[:], ed25519.Sign(key, message))
+
+	transaction := appendCompactLength(nil, 1)
+	transaction = append(transaction, signature[:]...)
+	return append(transaction, message...), signature
+}
+
+// appendCompactLength appends a compact-u16 length prefix
+func appendCompactLength(data []byte, length int) []byte {
+	for {
+		b := byte(length & 0x7f)
+		length >>= 7
+		if length == 0 {
+			return append(data, b)
+		}
+		data = append(data, b|0x80)
+	}
+}
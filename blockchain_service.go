@@ -2,18 +2,28 @@ package idrx
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
 
 	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/blockchain/batch"
+	"github.com/widnyana/idrx-go/blockchain/solana"
+	"github.com/widnyana/idrx-go/models"
 )
 
 // BlockchainService provides blockchain-specific operations for IDRX
 type BlockchainService struct {
 	client *blockchain.Client
+	solana *solana.Client // Set by WithSolana
 }
 
 // NewBlockchainService creates a new blockchain service
@@ -23,21 +33,91 @@ func NewBlockchainService(client *blockchain.Client) *BlockchainService {
 	}
 }
 
+// Client returns the underlying blockchain client, for packages such as reconcile that work on it directly
+func (bs *BlockchainService) Client() *blockchain.Client {
+	return bs.client
+}
+
+// Solana returns the Solana client enabled by WithSolana, or nil
+func (bs *BlockchainService) Solana() *solana.Client {
+	return bs.solana
+}
+
+// isSolana reports whether a network name refers to a configured Solana network
+func (bs *BlockchainService) isSolana(networkName string) bool {
+	return bs.solana != nil && bs.solana.HasNetwork(networkName)
+}
+
+// Connect eagerly dials the given networks (or every enabled network) and
+// returns a *blockchain.NetworkError for each one that could not be reached
+func (bs *BlockchainService) Connect(ctx context.Context, networkNames ...string) error {
+	return bs.client.Connect(ctx, networkNames...)
+}
+
+// NetworkErrors returns the latest connection error for each enabled network that failed to connect
+func (bs *BlockchainService) NetworkErrors() map[string]error {
+	return bs.client.NetworkErrors()
+}
+
+// ValidateNetwork checks that the IDRX contract is deployed on a network and that
+// its decimals match the configured value
+func (bs *BlockchainService) ValidateNetwork(ctx context.Context, networkName string) error {
+	return bs.client.ValidateNetwork(ctx, networkName)
+}
+
+// Simulate dry-runs a contract write with eth_call and returns a *blockchain.RevertError if it would revert
+func (bs *BlockchainService) Simulate(ctx context.Context, chainID uint64, method string, args ...interface{}) ([]byte, error) {
+	return bs.client.Simulate(ctx, chainID, method, args...)
+}
+
+// SubscribeEvents follows Transfer, BurnBridge, MintBridge and BurnWithAccountNumber events on a chain,
+// decoded into models types with decimals applied
+func (bs *BlockchainService) SubscribeEvents(
+	ctx context.Context,
+	chainID uint64,
+	opts *blockchain.SubscribeOptions,
+) (*blockchain.EventSubscription, error) {
+	return bs.client.SubscribeEvents(ctx, chainID, opts)
+}
+
 // GetBalance returns the IDRX balance for an address on the specified chain
 func (bs *BlockchainService) GetBalance(ctx context.Context, chainID uint64, address string) (*blockchain.TokenAmount, error) {
 	addr := common.HexToAddress(address)
 	return bs.client.BalanceOf(ctx, chainID, addr)
 }
 
-// GetBalanceByNetwork returns the IDRX balance for an address on the specified network
+// GetBalanceByNetwork returns the IDRX balance for an address on the specified network.
+// On Solana the address is the wallet, and the balance covers all its IDRX token accounts.
 func (bs *BlockchainService) GetBalanceByNetwork(ctx context.Context, networkName string, address string) (*blockchain.TokenAmount, error) {
-	networkConfig, exists := blockchain.GetNetworkConfig(networkName)
+	if bs.isSolana(networkName) {
+		owner, err := solana.ParsePublicKey(address)
+		if err != nil {
+			return nil, err
+		}
+		return bs.solana.BalanceOf(ctx, networkName, owner)
+	}
+
+	networkConfig, exists := bs.client.Registry().Get(networkName)
 	if !exists {
 		return nil, fmt.Errorf("network %s not supported", networkName)
 	}
 	return bs.GetBalance(ctx, networkConfig.ChainID, address)
 }
 
+// GetBalanceAt returns the IDRX balance for an address as of a point in time, using the
+// last block mined at or before it
+func (bs *BlockchainService) GetBalanceAt(ctx context.Context, chainID uint64, address string, at time.Time) (*blockchain.TokenAmount, error) {
+	account, err := parseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	block, err := bs.client.BlockAtTime(ctx, chainID, at)
+	if err != nil {
+		return nil, err
+	}
+	return bs.client.BalanceAt(ctx, chainID, account, block)
+}
+
 // GetTokenInfo returns basic token information for the specified chain
 func (bs *BlockchainService) GetTokenInfo(ctx context.Context, chainID uint64) (*blockchain.TokenInfo, error) {
 	return bs.client.GetTokenInfo(ctx, chainID)
@@ -45,7 +125,7 @@ func (bs *BlockchainService) GetTokenInfo(ctx context.Context, chainID uint64) (
 
 // GetTokenInfoByNetwork returns basic token information for the specified network
 func (bs *BlockchainService) GetTokenInfoByNetwork(ctx context.Context, networkName string) (*blockchain.TokenInfo, error) {
-	networkConfig, exists := blockchain.GetNetworkConfig(networkName)
+	networkConfig, exists := bs.client.Registry().Get(networkName)
 	if !exists {
 		return nil, fmt.Errorf("network %s not supported", networkName)
 	}
@@ -55,7 +135,7 @@ func (bs *BlockchainService) GetTokenInfoByNetwork(ctx context.Context, networkN
 // Transfer transfers IDRX tokens between addresses
 func (bs *BlockchainService) Transfer(ctx context.Context, chainID uint64, toAddress string, amount string) (*TransferResult, error) {
 	to := common.HexToAddress(toAddress)
-	tokenAmount, err := blockchain.ParseTokenAmount(amount, int32(blockchain.GetDecimals(chainID)))
+	tokenAmount, err := blockchain.ParseTokenAmount(amount, int32(bs.client.Decimals(chainID)))
 	if err != nil {
 		return nil, fmt.Errorf("invalid amount: %w", err)
 	}
@@ -74,15 +154,112 @@ func (bs *BlockchainService) Transfer(ctx context.Context, chainID uint64, toAdd
 	}, nil
 }
 
+// TransferByNetwork transfers IDRX tokens on the specified EVM or Solana network. On Solana
+// the recipient is a wallet address; its associated token account is created if needed.
+func (bs *BlockchainService) TransferByNetwork(ctx context.Context, networkName string, toAddress string, amount string) (*TransferResult, error) {
+	if !bs.isSolana(networkName) {
+		networkConfig, exists := bs.client.Registry().Get(networkName)
+		if !exists {
+			return nil, fmt.Errorf("network %s not supported", networkName)
+		}
+		result, err := bs.Transfer(ctx, networkConfig.ChainID, toAddress, amount)
+		if err != nil {
+			return nil, err
+		}
+		result.Network = networkName
+		return result, nil
+	}
+
+	to, err := solana.ParsePublicKey(toAddress)
+	if err != nil {
+		return nil, err
+	}
+	tokenAmount, err := bs.solanaAmount(ctx, networkName, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := bs.solana.Transfer(ctx, networkName, to, tokenAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransferResult{
+		TxHash:  signature.String(),
+		Network: networkName,
+		To:      toAddress,
+		Amount:  amount,
+		Status:  "pending",
+	}, nil
+}
+
 // TransferResult represents the result of a transfer operation
 type TransferResult struct {
-	TxHash  string `json:"txHash"`
+	TxHash  string `json:"txHash"` // Transaction signature on Solana
 	ChainID uint64 `json:"chainId"`
+	Network string `json:"network,omitempty"` // Network name, for the *ByNetwork methods
+	From    string `json:"from,omitempty"`    // Token owner, for TransferFrom
 	To      string `json:"to"`
 	Amount  string `json:"amount"`
 	Status  string `json:"status"`
 }
 
+// BatchTransferItem is one payment in a batch transfer
+type BatchTransferItem struct {
+	ID     string `json:"id"` // Caller reference such as a payroll line (optional)
+	To     string `json:"to"`
+	Amount string `json:"amount"`
+}
+
+// BatchTransferOptions controls a batch transfer
+type BatchTransferOptions struct {
+	StateFile     string           // Progress is saved here and resumed from on the next run (optional)
+	Concurrency   int              // Transactions awaiting confirmation at once (defaults to 8)
+	Confirmations uint64           // Confirmations required per transfer (defaults to 1)
+	WaitTimeout   time.Duration    // How long to wait for each transfer before leaving it submitted (optional)
+	OnProgress    func(batch.Item) // Called after every status change (optional)
+}
+
+// BatchTransfer sends many transfers from the wallet with sequential nonces and returns the
+// outcome of each one. Malformed addresses or amounts fail the whole batch before anything is
+// checked on chain; see batch.Run for the on-chain validation and resume behaviour.
+func (bs *BlockchainService) BatchTransfer(
+	ctx context.Context,
+	chainID uint64,
+	items []BatchTransferItem,
+	options *BatchTransferOptions,
+) (*batch.Report, error) {
+	if options == nil {
+		options = &BatchTransferOptions{}
+	}
+
+	decimals := int32(bs.client.Decimals(chainID))
+	transfers := make([]batch.Transfer, len(items))
+	for i, item := range items {
+		to, err := parseAddress(item.To)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		amount, err := blockchain.ParseTokenAmount(item.Amount, decimals)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: invalid amount: %w", i, err)
+		}
+		transfers[i] = batch.Transfer{ID: item.ID, To: to, Amount: amount}
+	}
+
+	config := &batch.Config{
+		Concurrency:   options.Concurrency,
+		Confirmations: options.Confirmations,
+		WaitTimeout:   options.WaitTimeout,
+		OnProgress:    options.OnProgress,
+	}
+	if options.StateFile != "" {
+		config.Store = batch.NewFileStore(options.StateFile)
+	}
+
+	return batch.Run(ctx, bs.client, chainID, transfers, config)
+}
+
 // BurnForRedemption burns IDRX tokens for fiat redemption
 func (bs *BlockchainService) BurnForRedemption(
 	ctx context.Context,
@@ -90,7 +267,7 @@ func (bs *BlockchainService) BurnForRedemption(
 	amount string,
 	accountNumber string,
 ) (*BurnResult, error) {
-	tokenAmount, err := blockchain.ParseTokenAmount(amount, int32(blockchain.GetDecimals(chainID)))
+	tokenAmount, err := blockchain.ParseTokenAmount(amount, int32(bs.client.Decimals(chainID)))
 	if err != nil {
 		return nil, fmt.Errorf("invalid amount: %w", err)
 	}
@@ -109,26 +286,255 @@ func (bs *BlockchainService) BurnForRedemption(
 	}, nil
 }
 
+// BurnForRedemptionByNetwork burns IDRX tokens for fiat redemption on the specified EVM or
+// Solana network. On Solana only a hash of the account number travels in an SPL memo.
+func (bs *BlockchainService) BurnForRedemptionByNetwork(
+	ctx context.Context,
+	networkName string,
+	amount string,
+	accountNumber string,
+) (*BurnResult, error) {
+	if !bs.isSolana(networkName) {
+		networkConfig, exists := bs.client.Registry().Get(networkName)
+		if !exists {
+			return nil, fmt.Errorf("network %s not supported", networkName)
+		}
+		result, err := bs.BurnForRedemption(ctx, networkConfig.ChainID, amount, accountNumber)
+		if err != nil {
+			return nil, err
+		}
+		result.Network = networkName
+		return result, nil
+	}
+
+	tokenAmount, err := bs.solanaAmount(ctx, networkName, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := bs.solana.BurnWithAccountNumber(ctx, networkName, tokenAmount, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BurnResult{
+		TxHash:        signature.String(),
+		Network:       networkName,
+		Amount:        amount,
+		AccountNumber: accountNumber,
+		Status:        "pending",
+	}, nil
+}
+
+// solanaAmount parses an amount using the decimals of a Solana network's mint
+func (bs *BlockchainService) solanaAmount(ctx context.Context, networkName string, amount string) (*blockchain.TokenAmount, error) {
+	decimals, err := bs.solana.Decimals(ctx, networkName)
+	if err != nil {
+		return nil, err
+	}
+	tokenAmount, err := blockchain.ParseTokenAmount(amount, int32(decimals))
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %w", err)
+	}
+	return tokenAmount, nil
+}
+
 // BurnResult represents the result of a burn operation
 type BurnResult struct {
-	TxHash        string `json:"txHash"`
+	TxHash        string `json:"txHash"` // Transaction signature on Solana
 	ChainID       uint64 `json:"chainId"`
+	Network       string `json:"network,omitempty"` // Network name, for the *ByNetwork methods
 	Amount        string `json:"amount"`
-	AccountNumber string `json:"accountNumber"`
+	AccountNumber string `json:"accountNumber,omitempty"`
+	Account       string `json:"account,omitempty"` // Token holder, for BurnFrom
 	Status        string `json:"status"`
 }
 
+// GetAllowance returns how many tokens a spender may still transfer on behalf of an owner
+func (bs *BlockchainService) GetAllowance(ctx context.Context, chainID uint64, owner, spender string) (*blockchain.TokenAmount, error) {
+	ownerAddr, err := parseAddress(owner)
+	if err != nil {
+		return nil, err
+	}
+	spenderAddr, err := parseAddress(spender)
+	if err != nil {
+		return nil, err
+	}
+
+	return bs.client.Allowance(ctx, chainID, ownerAddr, spenderAddr)
+}
+
+// Approve sets the allowance of a spender over the wallet's tokens.
+// Use SetExactAllowance to change an existing non-zero allowance safely.
+func (bs *BlockchainService) Approve(ctx context.Context, chainID uint64, spender string, amount string) (*ApprovalResult, error) {
+	return bs.approval(ctx, chainID, spender, amount, bs.client.Approve)
+}
+
+// IncreaseAllowance raises the allowance of a spender by amount
+func (bs *BlockchainService) IncreaseAllowance(ctx context.Context, chainID uint64, spender string, amount string) (*ApprovalResult, error) {
+	return bs.approval(ctx, chainID, spender, amount, bs.client.IncreaseAllowance)
+}
+
+// DecreaseAllowance lowers the allowance of a spender by amount
+func (bs *BlockchainService) DecreaseAllowance(ctx context.Context, chainID uint64, spender string, amount string) (*ApprovalResult, error) {
+	return bs.approval(ctx, chainID, spender, amount, bs.client.DecreaseAllowance)
+}
+
+// SetExactAllowance sets the allowance of a spender to exactly amount, resetting it to zero
+// first when both the current and the new allowance are non-zero. It returns one result per
+// transaction sent, none when the allowance already matches. When the final approval fails
+// after a confirmed reset, the reset is returned as confirmed followed by a failed result for
+// the approval, along with the error.
+func (bs *BlockchainService) SetExactAllowance(ctx context.Context, chainID uint64, spender string, amount string) ([]*ApprovalResult, error) {
+	spenderAddr, err := parseAddress(spender)
+	if err != nil {
+		return nil, err
+	}
+	tokenAmount, err := blockchain.ParseTokenAmount(amount, int32(bs.client.Decimals(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %w", err)
+	}
+
+	txs, err := bs.client.SetExactAllowance(ctx, chainID, spenderAddr, tokenAmount)
+
+	// A reset to zero precedes the final approval. It is the only transaction sent when it
+	// was not confirmed, or when the final approval failed after it was.
+	resetConfirmed := !errors.Is(err, blockchain.ErrAllowanceReset)
+	results := make([]*ApprovalResult, 0, len(txs)+1)
+	for i, tx := range txs {
+		approved, status := amount, "pending"
+		if err != nil || i < len(txs)-1 {
+			approved = "0"
+			if resetConfirmed {
+				status = "confirmed"
+			}
+		}
+		results = append(results, &ApprovalResult{
+			TxHash:  tx.Hash().Hex(),
+			ChainID: chainID,
+			Spender: spender,
+			Amount:  approved,
+			Status:  status,
+		})
+	}
+	if err != nil && resetConfirmed && len(txs) > 0 {
+		results = append(results, &ApprovalResult{
+			ChainID: chainID,
+			Spender: spender,
+			Amount:  amount,
+			Status:  "failed",
+		})
+	}
+
+	return results, err
+}
+
+// approval validates the inputs of an allowance change and sends it with send
+func (bs *BlockchainService) approval(
+	ctx context.Context,
+	chainID uint64,
+	spender string,
+	amount string,
+	send func(context.Context, uint64, common.Address, *blockchain.TokenAmount) (*types.Transaction, error),
+) (*ApprovalResult, error) {
+	spenderAddr, err := parseAddress(spender)
+	if err != nil {
+		return nil, err
+	}
+	tokenAmount, err := blockchain.ParseTokenAmount(amount, int32(bs.client.Decimals(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %w", err)
+	}
+
+	tx, err := send(ctx, chainID, spenderAddr, tokenAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ApprovalResult{
+		TxHash:  tx.Hash().Hex(),
+		ChainID: chainID,
+		Spender: spender,
+		Amount:  amount,
+		Status:  "pending",
+	}, nil
+}
+
+// ApprovalResult represents the result of an allowance change
+type ApprovalResult struct {
+	TxHash  string `json:"txHash"`
+	ChainID uint64 `json:"chainId"`
+	Spender string `json:"spender"`
+	Amount  string `json:"amount"` // Amount approved, or the increase/decrease
+	Status  string `json:"status"`
+}
+
+// TransferFrom transfers tokens from an owner who approved the wallet as spender
+func (bs *BlockchainService) TransferFrom(ctx context.Context, chainID uint64, fromAddress, toAddress string, amount string) (*TransferResult, error) {
+	from, err := parseAddress(fromAddress)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseAddress(toAddress)
+	if err != nil {
+		return nil, err
+	}
+	tokenAmount, err := blockchain.ParseTokenAmount(amount, int32(bs.client.Decimals(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %w", err)
+	}
+
+	tx, err := bs.client.TransferFrom(ctx, chainID, from, to, tokenAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransferResult{
+		TxHash:  tx.Hash().Hex(),
+		ChainID: chainID,
+		From:    fromAddress,
+		To:      toAddress,
+		Amount:  amount,
+		Status:  "pending",
+	}, nil
+}
+
+// BurnFrom burns tokens from an account that approved the wallet as spender
+func (bs *BlockchainService) BurnFrom(ctx context.Context, chainID uint64, account string, amount string) (*BurnResult, error) {
+	accountAddr, err := parseAddress(account)
+	if err != nil {
+		return nil, err
+	}
+	tokenAmount, err := blockchain.ParseTokenAmount(amount, int32(bs.client.Decimals(chainID)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %w", err)
+	}
+
+	tx, err := bs.client.BurnFrom(ctx, chainID, accountAddr, tokenAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BurnResult{
+		TxHash:  tx.Hash().Hex(),
+		ChainID: chainID,
+		Amount:  amount,
+		Account: account,
+		Status:  "pending",
+	}, nil
+}
+
 // InitiateBridge initiates a cross-chain bridge transaction
 func (bs *BlockchainService) InitiateBridge(ctx context.Context, request *BridgeTransactionRequest) (*BridgeResult, error) {
-	if !blockchain.IsChainSupported(request.FromChainID) {
+	if !bs.client.Registry().IsChainSupported(request.FromChainID) {
 		return nil, fmt.Errorf("source chain %d not supported", request.FromChainID)
 	}
 
-	if !blockchain.IsChainSupported(request.ToChainID) {
+	if !bs.client.Registry().IsChainSupported(request.ToChainID) {
 		return nil, fmt.Errorf("destination chain %d not supported", request.ToChainID)
 	}
 
-	tokenAmount, err := blockchain.ParseTokenAmount(request.Amount, int32(blockchain.GetDecimals(request.FromChainID)))
+	tokenAmount, err := blockchain.ParseTokenAmount(request.Amount, int32(bs.client.Decimals(request.FromChainID)))
 	if err != nil {
 		return nil, fmt.Errorf("invalid amount: %w", err)
 	}
@@ -175,7 +581,7 @@ type BridgeResult struct {
 
 // GetTransactionStatus returns the status of a blockchain transaction
 func (bs *BlockchainService) GetTransactionStatus(ctx context.Context, chainID uint64, txHash string) (*TransactionStatus, error) {
-	client, err := bs.client.GetClient(chainID)
+	client, err := bs.client.GetBackend(chainID)
 	if err != nil {
 		return nil, err
 	}
@@ -223,9 +629,12 @@ type TransactionStatus struct {
 
 // GetNetworkInfo returns information about supported networks
 func (bs *BlockchainService) GetNetworkInfo() []NetworkInfo {
-	networks := make([]NetworkInfo, 0, len(blockchain.SupportedNetworks))
+	registry := bs.client.Registry()
+	names := registry.Names()
+	networks := make([]NetworkInfo, 0, len(names))
 
-	for networkName, config := range blockchain.SupportedNetworks {
+	for _, networkName := range names {
+		config, _ := registry.Get(networkName)
 		networks = append(networks, NetworkInfo{
 			NetworkName:     networkName,
 			ChainID:         config.ChainID,
@@ -236,6 +645,19 @@ func (bs *BlockchainService) GetNetworkInfo() []NetworkInfo {
 		})
 	}
 
+	if bs.solana != nil {
+		for _, networkName := range bs.solana.Networks() {
+			config, _ := bs.solana.Network(networkName)
+			networks = append(networks, NetworkInfo{
+				NetworkName:     networkName,
+				Name:            config.Name,
+				BlockTime:       config.BlockTime,
+				ContractAddress: config.Mint.String(),
+				IsTestnet:       config.IsTestnet,
+			})
+		}
+	}
+
 	return networks
 }
 
@@ -245,7 +667,7 @@ type NetworkInfo struct {
 	ChainID         uint64        `json:"chainId"`
 	Name            string        `json:"name"`
 	BlockTime       time.Duration `json:"blockTime"`
-	ContractAddress string        `json:"contractAddress"`
+	ContractAddress string        `json:"contractAddress"` // Mint address on Solana
 	IsTestnet       bool          `json:"isTestnet"`
 }
 
@@ -300,6 +722,33 @@ func (bs *BlockchainService) IsAddressBlacklisted(ctx context.Context, chainID u
 	return bs.client.IsBlacklisted(ctx, chainID, addr)
 }
 
+// AreAddressesBlacklisted checks the blacklist status of many addresses in a few batched requests.
+// The result is keyed by the addresses as given; addresses whose lookup failed are left out and
+// their errors joined into the returned error.
+func (bs *BlockchainService) AreAddressesBlacklisted(ctx context.Context, chainID uint64, addresses []string) (map[string]bool, error) {
+	addrs := make([]common.Address, len(addresses))
+	for i, address := range addresses {
+		addr, err := parseAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = addr
+	}
+
+	statuses, err := bs.client.BlacklistStatuses(ctx, chainID, addrs)
+	if statuses == nil {
+		return nil, err
+	}
+
+	result := make(map[string]bool, len(statuses))
+	for i, address := range addresses {
+		if blacklisted, ok := statuses[addrs[i]]; ok {
+			result[address] = blacklisted
+		}
+	}
+	return result, err
+}
+
 // GetBridgeNonce returns the current bridge nonce for a chain
 func (bs *BlockchainService) GetBridgeNonce(ctx context.Context, chainID uint64) (string, error) {
 	nonce, err := bs.client.GetBridgeNonce(ctx, chainID)
@@ -308,3 +757,281 @@ func (bs *BlockchainService) GetBridgeNonce(ctx context.Context, chainID uint64)
 	}
 	return nonce.String(), nil
 }
+
+// BalancesResult aggregates an address's IDRX balance across every enabled chain
+type BalancesResult struct {
+	Address  string                     `json:"address"`
+	Total    decimal.Decimal            `json:"total"` // Sum of the chains that answered, in IDR
+	Balances []models.TokenBalance      `json:"balances"`
+	Errors   []*blockchain.NetworkError `json:"errors,omitempty"` // Chains that failed or timed out
+}
+
+// SupplyResult aggregates IDRX total supply across every enabled chain
+type SupplyResult struct {
+	Total  decimal.Decimal            `json:"total"` // Sum of the chains that answered, in IDR
+	Chains []models.ChainStats        `json:"chains"`
+	Errors []*blockchain.NetworkError `json:"errors,omitempty"` // Chains that failed or timed out
+}
+
+// GetBalances queries an address's balance on every enabled chain concurrently. Each chain
+// gets the client's RPC timeout; chains that fail are reported in Errors and left out of
+// Total. When no chain answers, the result is returned with the per-chain errors joined.
+// Amounts are already scaled by each chain's decimals, so 1 IDRX is 1 IDR everywhere.
+func (bs *BlockchainService) GetBalances(ctx context.Context, address string) (*BalancesResult, error) {
+	addr, err := parseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BalancesResult{Address: addr.Hex(), Total: decimal.Zero}
+	var mu sync.Mutex
+
+	result.Errors = bs.forEachChain(ctx, func(ctx context.Context, chainID uint64) error {
+		balance, err := bs.client.BalanceOf(ctx, chainID, addr)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		result.Balances = append(result.Balances, models.TokenBalance{
+			ChainID:     chainID,
+			Address:     addr,
+			Balance:     balance.Amount,
+			LastUpdated: time.Now(),
+		})
+		result.Total = result.Total.Add(balance.Amount)
+		return nil
+	})
+
+	sort.Slice(result.Balances, func(i, j int) bool { return result.Balances[i].ChainID < result.Balances[j].ChainID })
+	if len(result.Balances) == 0 {
+		return result, joinNetworkErrors(result.Errors)
+	}
+	return result, nil
+}
+
+// GetGlobalSupply queries total supply on every enabled chain concurrently. Each chain's
+// supply is read at the block reported in LastBlockNumber; failing chains are reported in
+// Errors and left out of Total. When no chain answers, the per-chain errors are joined.
+func (bs *BlockchainService) GetGlobalSupply(ctx context.Context) (*SupplyResult, error) {
+	result := &SupplyResult{Total: decimal.Zero}
+	var mu sync.Mutex
+
+	result.Errors = bs.forEachChain(ctx, func(ctx context.Context, chainID uint64) error {
+		backend, err := bs.client.GetBackend(chainID)
+		if err != nil {
+			return err
+		}
+		block, err := backend.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get block number: %w", err)
+		}
+
+		supply, err := bs.client.TotalSupplyAt(ctx, chainID, new(big.Int).SetUint64(block))
+		if err != nil {
+			return err
+		}
+
+		networkConfig, _, _ := bs.client.Registry().GetByChainID(chainID)
+
+		mu.Lock()
+		defer mu.Unlock()
+		result.Chains = append(result.Chains, models.ChainStats{
+			ChainID:         chainID,
+			TotalSupply:     supply.Amount,
+			LastBlockNumber: block,
+			AvgBlockTime:    networkConfig.BlockTime,
+			UpdatedAt:       time.Now(),
+		})
+		result.Total = result.Total.Add(supply.Amount)
+		return nil
+	})
+
+	sort.Slice(result.Chains, func(i, j int) bool { return result.Chains[i].ChainID < result.Chains[j].ChainID })
+	if len(result.Chains) == 0 {
+		return result, joinNetworkErrors(result.Errors)
+	}
+	return result, nil
+}
+
+// ImplementationCheckResult reports the proxy implementation running on every enabled chain
+type ImplementationCheckResult struct {
+	Chains     []*blockchain.ProxyState   `json:"chains"`
+	Mismatched []uint64                   `json:"mismatched"`       // Chains whose implementation bytecode hash is not the expected one
+	Unverified []uint64                   `json:"unverified"`       // Chains with an expected hash that could not be checked
+	Errors     []*blockchain.NetworkError `json:"errors,omitempty"` // Chains that failed, timed out or are not enabled
+}
+
+// CheckImplementations reads the EIP-1967 implementation of the IDRX proxy on every enabled
+// chain concurrently and compares its bytecode hash with the expected hash for that chain.
+// Chains without an expected hash are reported but never flagged. Chains in expected that fail
+// or are not enabled are listed in Unverified, and their errors are joined into the returned
+// error alongside the result. Run it periodically to be notified when a deployment is upgraded.
+func (bs *BlockchainService) CheckImplementations(ctx context.Context, expected map[uint64]common.Hash) (*ImplementationCheckResult, error) {
+	result := &ImplementationCheckResult{}
+	var mu sync.Mutex
+
+	result.Errors = bs.forEachChain(ctx, func(ctx context.Context, chainID uint64) error {
+		state, err := bs.client.ProxyState(ctx, chainID, nil)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		result.Chains = append(result.Chains, state)
+		if hash, ok := expected[chainID]; ok && hash != state.ImplementationCodeHash {
+			result.Mismatched = append(result.Mismatched, chainID)
+		}
+		return nil
+	})
+
+	sort.Slice(result.Chains, func(i, j int) bool { return result.Chains[i].ChainID < result.Chains[j].ChainID })
+	sort.Slice(result.Mismatched, func(i, j int) bool { return result.Mismatched[i] < result.Mismatched[j] })
+
+	checked := make(map[uint64]bool, len(result.Chains))
+	for _, state := range result.Chains {
+		checked[state.ChainID] = true
+	}
+	failed := make(map[uint64]*blockchain.NetworkError, len(result.Errors))
+	for _, networkErr := range result.Errors {
+		failed[networkErr.ChainID] = networkErr
+	}
+
+	var unverified []*blockchain.NetworkError
+	for chainID := range expected {
+		if checked[chainID] {
+			continue
+		}
+		networkErr, ok := failed[chainID]
+		if !ok {
+			_, name, _ := bs.client.Registry().GetByChainID(chainID)
+			networkErr = &blockchain.NetworkError{Network: name, ChainID: chainID, Err: fmt.Errorf("chain ID %d not enabled", chainID)}
+			result.Errors = append(result.Errors, networkErr)
+		}
+		result.Unverified = append(result.Unverified, chainID)
+		unverified = append(unverified, networkErr)
+	}
+	sort.Slice(result.Unverified, func(i, j int) bool { return result.Unverified[i] < result.Unverified[j] })
+	sort.Slice(result.Errors, func(i, j int) bool { return result.Errors[i].ChainID < result.Errors[j].ChainID })
+	sort.Slice(unverified, func(i, j int) bool { return unverified[i].ChainID < unverified[j].ChainID })
+
+	return result, joinNetworkErrors(unverified)
+}
+
+// WalletOwnershipProof is a signed wallet ownership attestation as sent to a verifier
+type WalletOwnershipProof struct {
+	Wallet    string    `json:"wallet"`
+	MemberID  int       `json:"memberId"`
+	ChainID   uint64    `json:"chainId"`
+	Nonce     string    `json:"nonce"`
+	IssuedAt  time.Time `json:"issuedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Signature string    `json:"signature"`
+}
+
+// ProveWalletOwnership signs an EIP-712 attestation that the client's wallet belongs to an
+// IDRX member on a chain, e.g. before registering it as a mint destination. Nonce is the
+// verifier's challenge; the proof expires after validFor.
+func (bs *BlockchainService) ProveWalletOwnership(memberID int, chainID uint64, nonce string, validFor time.Duration) (*WalletOwnershipProof, error) {
+	issuedAt := time.Now().UTC().Truncate(time.Second)
+	attestation := &blockchain.OwnershipAttestation{
+		Wallet:    bs.client.GetAddress(),
+		MemberID:  memberID,
+		ChainID:   chainID,
+		Nonce:     nonce,
+		IssuedAt:  issuedAt,
+		ExpiresAt: issuedAt.Add(validFor),
+	}
+
+	signature, err := bs.client.SignOwnershipAttestation(attestation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WalletOwnershipProof{
+		Wallet:    attestation.Wallet.Hex(),
+		MemberID:  memberID,
+		ChainID:   chainID,
+		Nonce:     nonce,
+		IssuedAt:  attestation.IssuedAt,
+		ExpiresAt: attestation.ExpiresAt,
+		Signature: hexutil.Encode(signature),
+	}, nil
+}
+
+// VerifyWalletOwnership checks that a proof is signed by its wallet and has not expired.
+// The caller must also check that the nonce is the challenge it issued.
+func VerifyWalletOwnership(proof *WalletOwnershipProof) error {
+	wallet, err := parseAddress(proof.Wallet)
+	if err != nil {
+		return err
+	}
+	signature, err := hexutil.Decode(proof.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	return blockchain.VerifyOwnershipAttestation(&blockchain.OwnershipAttestation{
+		Wallet:    wallet,
+		MemberID:  proof.MemberID,
+		ChainID:   proof.ChainID,
+		Nonce:     proof.Nonce,
+		IssuedAt:  proof.IssuedAt,
+		ExpiresAt: proof.ExpiresAt,
+	}, signature, time.Now())
+}
+
+// forEachChain runs fn for every enabled chain in parallel, each under its own timeout,
+// and returns the failures ordered by chain ID
+func (bs *BlockchainService) forEachChain(ctx context.Context, fn func(ctx context.Context, chainID uint64) error) []*blockchain.NetworkError {
+	registry := bs.client.Registry()
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []*blockchain.NetworkError
+	)
+	for _, name := range bs.client.EnabledNetworks() {
+		networkConfig, exists := registry.Get(name)
+		if !exists {
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, chainID uint64) {
+			defer wg.Done()
+
+			chainCtx, cancel := context.WithTimeout(ctx, bs.client.Timeout())
+			defer cancel()
+
+			if err := fn(chainCtx, chainID); err != nil {
+				mu.Lock()
+				failed = append(failed, &blockchain.NetworkError{Network: name, ChainID: chainID, Err: err})
+				mu.Unlock()
+			}
+		}(name, networkConfig.ChainID)
+	}
+	wg.Wait()
+
+	sort.Slice(failed, func(i, j int) bool { return failed[i].ChainID < failed[j].ChainID })
+	return failed
+}
+
+// joinNetworkErrors joins per-chain failures into one error, or returns nil when there are none
+func joinNetworkErrors(failed []*blockchain.NetworkError) error {
+	errs := make([]error, len(failed))
+	for i, err := range failed {
+		errs[i] = err
+	}
+	return errors.Join(errs...)
+}
+
+// parseAddress converts a hex address, rejecting malformed input that HexToAddress would silently accept
+func parseAddress(address string) (common.Address, error) {
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("invalid address: %s", address)
+	}
+	return common.HexToAddress(address), nil
+}
@@ -0,0 +1,402 @@
+package idrx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/blockchain/solana"
+	"github.com/widnyana/idrx-go/internal/backendtest"
+)
+
+// stubBackend answers every eth_call with the same uint256, or blocks until the context ends
+type stubBackend struct {
+	blockchain.Backend // unimplemented methods panic if called
+
+	chainID uint64
+	value   int64
+	hang    bool
+}
+
+func (s *stubBackend) ChainID(_ context.Context) (*big.Int, error) {
+	return new(big.Int).SetUint64(s.chainID), nil
+}
+
+func (s *stubBackend) BlockNumber(_ context.Context) (uint64, error) {
+	return 100, nil
+}
+
+func (s *stubBackend) CallContract(ctx context.Context, _ ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	if s.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return common.LeftPadBytes(big.NewInt(s.value).Bytes(), 32), nil
+}
+
+// stubChain is a devnet served by a stubBackend
+type stubChain struct {
+	name     string
+	chainID  uint64
+	decimals uint8
+	backend  *stubBackend
+}
+
+func (s *stubBackend) StorageAt(ctx context.Context, _ common.Address, _ common.Hash, _ *big.Int) ([]byte, error) {
+	if s.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return make([]byte, 32), nil
+}
+
+// newMultiChainService builds a service over devnets with 0 and 2 decimals and one unresponsive chain
+func newMultiChainService(t *testing.T) *BlockchainService {
+	t.Helper()
+
+	return newStubService(t, []stubChain{
+		{"ZeroDecimals", 1001, 0, &stubBackend{chainID: 1001, value: 1500}},
+		{"TwoDecimals", 1002, 2, &stubBackend{chainID: 1002, value: 250050}},
+		{"Unresponsive", 1003, 2, &stubBackend{chainID: 1003, hang: true}},
+	})
+}
+
+// newStubService builds a service over the given devnets
+func newStubService(t *testing.T, chains []stubChain) *BlockchainService {
+	t.Helper()
+
+	registry := blockchain.NewRegistry()
+	backends := map[string]blockchain.Backend{}
+	for i, chain := range chains {
+		contract := common.BigToAddress(big.NewInt(int64(0xa0 + i)))
+		config := blockchain.DevnetConfig(chain.chainID, fmt.Sprintf("http://127.0.0.1:%d", 8545+i), contract, chain.decimals)
+		if err := registry.Register(chain.name, config); err != nil {
+			t.Fatalf("failed to register %s: %v", chain.name, err)
+		}
+		backends[chain.name] = chain.backend
+	}
+
+	client, err := blockchain.NewClient(&blockchain.ClientConfig{
+		PrivateKeyHex: backendtest.PrivateKey,
+		Timeout:       200 * time.Millisecond,
+		Registry:      registry,
+		Backends:      backends,
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	return NewBlockchainService(client)
+}
+
+func TestGetBalancesAcrossChains(t *testing.T) {
+	service := newMultiChainService(t)
+
+	result, err := service.GetBalances(context.Background(), "0x00000000000000000000000000000000000000cc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Balances) != 2 {
+		t.Fatalf("expected 2 balances, got %d", len(result.Balances))
+	}
+	if !result.Balances[0].Balance.Equal(decimal.NewFromInt(1500)) {
+		t.Errorf("expected 1500 on the 0-decimal chain, got %s", result.Balances[0].Balance)
+	}
+	if !result.Balances[1].Balance.Equal(decimal.RequireFromString("2500.50")) {
+		t.Errorf("expected 2500.50 on the 2-decimal chain, got %s", result.Balances[1].Balance)
+	}
+	if !result.Total.Equal(decimal.RequireFromString("4000.50")) {
+		t.Errorf("expected total 4000.50, got %s", result.Total)
+	}
+
+	if len(result.Errors) != 1 || result.Errors[0].ChainID != 1003 {
+		t.Fatalf("expected a timeout for chain 1003, got %v", result.Errors)
+	}
+	if !errors.Is(result.Errors[0], context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", result.Errors[0])
+	}
+}
+
+func TestGetBalancesSerialisesChainErrors(t *testing.T) {
+	service := newMultiChainService(t)
+
+	result, err := service.GetBalances(context.Background(), "0x00000000000000000000000000000000000000cc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to marshal result: %v", err)
+	}
+	var decoded struct {
+		Errors []struct {
+			Network string `json:"network"`
+			ChainID uint64 `json:"chainId"`
+			Error   string `json:"error"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	if len(decoded.Errors) != 1 || decoded.Errors[0].ChainID != 1003 || decoded.Errors[0].Network != "Unresponsive" {
+		t.Fatalf("expected the chain 1003 failure in JSON, got %s", data)
+	}
+	if decoded.Errors[0].Error == "" {
+		t.Error("expected the failure message in JSON")
+	}
+}
+
+func TestAggregatesFailWhenNoChainAnswers(t *testing.T) {
+	service := newStubService(t, []stubChain{
+		{"Unresponsive", 1003, 2, &stubBackend{chainID: 1003, hang: true}},
+		{"AlsoUnresponsive", 1004, 2, &stubBackend{chainID: 1004, hang: true}},
+	})
+
+	balances, err := service.GetBalances(context.Background(), "0x00000000000000000000000000000000000000cc")
+	var networkErr *blockchain.NetworkError
+	if !errors.As(err, &networkErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected joined network errors, got %v", err)
+	}
+	if balances == nil || len(balances.Errors) != 2 {
+		t.Fatalf("expected both failures in the result, got %+v", balances)
+	}
+
+	supply, err := service.GetGlobalSupply(context.Background())
+	if !errors.As(err, &networkErr) {
+		t.Fatalf("expected joined network errors, got %v", err)
+	}
+	if supply == nil || len(supply.Errors) != 2 {
+		t.Fatalf("expected both failures in the result, got %+v", supply)
+	}
+}
+
+func TestGetBalancesInvalidAddress(t *testing.T) {
+	service := newMultiChainService(t)
+
+	if _, err := service.GetBalances(context.Background(), "not-an-address"); err == nil {
+		t.Error("expected error for invalid address")
+	}
+	if _, err := service.GetBalanceAt(context.Background(), 1002, "not-an-address", time.Now()); err == nil {
+		t.Error("expected error for invalid address")
+	}
+}
+
+// approvalChain holds a non-zero allowance, mines every transaction immediately and refuses
+// transactions once sendLimit have been sent
+type approvalChain struct {
+	backendtest.Chain
+
+	sent      int
+	sendLimit int
+}
+
+func (a *approvalChain) CallContract(_ context.Context, _ ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	return common.LeftPadBytes(big.NewInt(500).Bytes(), 32), nil
+}
+
+func (a *approvalChain) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (a *approvalChain) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	return uint64(a.sent), nil
+}
+
+func (a *approvalChain) SendTransaction(_ context.Context, _ *types.Transaction) error {
+	if a.sent >= a.sendLimit {
+		return errors.New("transaction underpriced")
+	}
+	a.sent++
+	return nil
+}
+
+func (a *approvalChain) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	return &types.Receipt{TxHash: hash, Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(1)}, nil
+}
+
+func TestSetExactAllowanceReportsFailedApprovalAfterReset(t *testing.T) {
+	backend := &approvalChain{Chain: backendtest.Chain{Head: 1}, sendLimit: 1}
+	service := NewBlockchainService(backendtest.NewSigningClient(t, backend, backendtest.FastBlocks))
+
+	spender := "0x00000000000000000000000000000000000000dd"
+	results, err := service.SetExactAllowance(context.Background(), backendtest.ChainID, spender, "3")
+	if err == nil {
+		t.Fatal("expected the final approval to fail")
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected the reset and the failed approval, got %d results", len(results))
+	}
+	if reset := results[0]; reset.Status != "confirmed" || reset.Amount != "0" || reset.TxHash == "" {
+		t.Errorf("expected the reset to be confirmed, got %+v", reset)
+	}
+	if approval := results[1]; approval.Status != "failed" || approval.Amount != "3" || approval.TxHash != "" {
+		t.Errorf("expected a failed approval without a transaction, got %+v", approval)
+	}
+}
+
+func TestGetGlobalSupply(t *testing.T) {
+	service := newMultiChainService(t)
+
+	result, err := service.GetGlobalSupply(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Chains) != 2 || len(result.Errors) != 1 {
+		t.Fatalf("expected 2 chains and 1 error, got %d and %d", len(result.Chains), len(result.Errors))
+	}
+	if !result.Total.Equal(decimal.RequireFromString("4000.50")) {
+		t.Errorf("expected total 4000.50, got %s", result.Total)
+	}
+	if result.Chains[0].LastBlockNumber != 100 {
+		t.Errorf("expected supply read at block 100, got %d", result.Chains[0].LastBlockNumber)
+	}
+}
+
+func TestCheckImplementationsReportsUnverifiedChains(t *testing.T) {
+	service := newMultiChainService(t)
+
+	result, err := service.CheckImplementations(context.Background(), map[uint64]common.Hash{
+		1001: {},                       // matches: the stub proxies have no implementation
+		1003: common.HexToHash("0x01"), // times out
+		9999: common.HexToHash("0x02"), // not enabled
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the timeout in the returned error, got %v", err)
+	}
+	if result == nil {
+		t.Fatal("expected the result alongside the error")
+	}
+
+	if len(result.Chains) != 2 || len(result.Mismatched) != 0 {
+		t.Errorf("expected 2 checked chains and no mismatch, got %d and %v", len(result.Chains), result.Mismatched)
+	}
+	if len(result.Unverified) != 2 || result.Unverified[0] != 1003 || result.Unverified[1] != 9999 {
+		t.Errorf("expected chains 1003 and 9999 unverified, got %v", result.Unverified)
+	}
+	if len(result.Errors) != 2 || result.Errors[1].ChainID != 9999 {
+		t.Fatalf("expected errors for chains 1003 and 9999, got %v", result.Errors)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to marshal result: %v", err)
+	}
+	if !strings.Contains(string(data), `"chainId":9999`) {
+		t.Errorf("expected the errors in JSON, got %s", data)
+	}
+}
+
+func TestCheckImplementationsWithoutFailures(t *testing.T) {
+	service := newMultiChainService(t)
+
+	result, err := service.CheckImplementations(context.Background(), map[uint64]common.Hash{
+		1001: {},
+		1002: common.HexToHash("0x01"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Mismatched) != 1 || result.Mismatched[0] != 1002 || len(result.Unverified) != 0 {
+		t.Errorf("expected chain 1002 mismatched and none unverified, got %v and %v", result.Mismatched, result.Unverified)
+	}
+}
+
+func TestProveWalletOwnership(t *testing.T) {
+	service := newMultiChainService(t)
+
+	proof, err := service.ProveWalletOwnership(1234, blockchain.BaseChainID, "challenge-1", 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := VerifyWalletOwnership(proof); err != nil {
+		t.Fatalf("expected the proof to verify: %v", err)
+	}
+
+	proof.MemberID = 4321
+	if err := VerifyWalletOwnership(proof); !errors.Is(err, blockchain.ErrSignerMismatch) {
+		t.Errorf("expected ErrSignerMismatch for a changed member ID, got: %v", err)
+	}
+}
+
+func TestByNetworkRoutesSolanaNetworks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+
+		var result interface{}
+		switch request.Method {
+		case "getTokenSupply":
+			result = map[string]interface{}{"value": map[string]interface{}{"decimals": 2}}
+		case "getTokenAccountsByOwner":
+			result = map[string]interface{}{"value": []interface{}{map[string]interface{}{
+				"account": map[string]interface{}{"data": map[string]interface{}{"parsed": map[string]interface{}{
+					"info": map[string]interface{}{"tokenAmount": map[string]interface{}{"amount": "987654"}},
+				}}},
+			}}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	}))
+	defer server.Close()
+
+	service := newMultiChainService(t)
+	solanaClient, err := solana.NewClient(&solana.ClientConfig{Networks: map[string]*solana.NetworkConfig{
+		solana.SolanaMainnet: solana.MainnetConfig(solana.PublicKey{1}, server.URL),
+	}})
+	if err != nil {
+		t.Fatalf("failed to create solana client: %v", err)
+	}
+	service.solana = solanaClient
+
+	balance, err := service.GetBalanceByNetwork(context.Background(), solana.SolanaMainnet, solana.PublicKey{2}.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !balance.Amount.Equal(decimal.RequireFromString("9876.54")) {
+		t.Errorf("expected 9876.54 on Solana, got %s", balance.Amount)
+	}
+
+	balance, err = service.GetBalanceByNetwork(context.Background(), "TwoDecimals", "0x00000000000000000000000000000000000000cc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !balance.Amount.Equal(decimal.RequireFromString("2500.50")) {
+		t.Errorf("expected 2500.50 on the EVM network, got %s", balance.Amount)
+	}
+
+	// The Solana client has no key, so writes fail before anything is sent
+	if _, err := service.TransferByNetwork(context.Background(), solana.SolanaMainnet, solana.PublicKey{2}.String(), "1"); !errors.Is(err, blockchain.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly, got: %v", err)
+	}
+	if _, err := service.BurnForRedemptionByNetwork(context.Background(), "Unknown", "1", "123"); err == nil {
+		t.Error("expected an error for an unknown network")
+	}
+}
+
+func TestWithSolanaRequiresBlockchain(t *testing.T) {
+	client := NewClient(WithSolana(&solana.ClientConfig{Networks: map[string]*solana.NetworkConfig{
+		solana.SolanaMainnet: solana.MainnetConfig(solana.PublicKey{1}),
+	}}))
+	if client.Err() == nil {
+		t.Error("expected an initialization error without blockchain operations")
+	}
+}
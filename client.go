@@ -1,10 +1,13 @@
 package idrx
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/blockchain/solana"
 )
 
 // Client is the main entry point for the IDRX SDK using the facade pattern.
@@ -18,6 +21,18 @@ type Client struct {
 	Account     *AccountService
 	Transaction *TransactionService
 	Blockchain  *BlockchainService // Optional blockchain service
+	Admin       *AdminService      // Optional admin service, enabled by WithAdmin
+	Banks       *BankDirectory     // Optional bank directory, enabled by WithBankValidation
+
+	// adminConfig is set by WithAdmin; the admin service is built once Blockchain is available
+	adminConfig *AdminConfig
+	// solanaConfig is set by WithSolana; the Solana client is attached to Blockchain once it is available
+	solanaConfig *solana.ClientConfig
+	// bankDirectoryConfig is set by WithBankValidation; the directory is built with the services
+	bankDirectoryConfig *BankDirectoryConfig
+
+	// initErrs collects errors from options that cannot fail NewClient directly
+	initErrs []error
 }
 
 // ClientOption represents a configuration option for the Client.
@@ -47,10 +62,37 @@ func NewClient(opts ...ClientOption) *Client {
 	// Initialize services with reference to client
 	client.Account = &AccountService{client: client}
 	client.Transaction = &TransactionService{client: client}
+	if client.bankDirectoryConfig != nil {
+		client.Banks = NewBankDirectory(client.Transaction, *client.bankDirectoryConfig)
+	}
+
+	if client.adminConfig != nil {
+		if client.Blockchain == nil {
+			client.initErrs = append(client.initErrs, errors.New("admin service requires blockchain operations to be enabled"))
+		} else {
+			client.Admin = NewAdminService(client.Blockchain.client, *client.adminConfig)
+		}
+	}
+
+	if client.solanaConfig != nil {
+		if client.Blockchain == nil {
+			client.initErrs = append(client.initErrs, errors.New("solana support requires blockchain operations to be enabled"))
+		} else if solanaClient, err := solana.NewClient(client.solanaConfig); err != nil {
+			client.initErrs = append(client.initErrs, fmt.Errorf("failed to initialize solana client: %w", err))
+		} else {
+			client.Blockchain.solana = solanaClient
+		}
+	}
 
 	return client
 }
 
+// Err returns the errors recorded while applying options, such as a failed
+// blockchain initialization, or nil if every option was applied successfully.
+func (c *Client) Err() error {
+	return errors.Join(c.initErrs...)
+}
+
 // WithBaseURL sets a custom base URL for the API.
 func WithBaseURL(baseURL string) ClientOption {
 	return func(c *Client) {
@@ -97,25 +139,69 @@ func WithTimeout(timeout time.Duration) ClientOption {
 }
 
 // WithBlockchain enables blockchain operations with the provided private key.
-// The private key should be hex-encoded (with or without 0x prefix).
+// The private key should be hex-encoded (with or without 0x prefix); an empty key gives a
+// read-only client that can query the chain but not sign.
+// If initialization fails, Client.Blockchain stays nil and the error is returned by Client.Err.
 func WithBlockchain(privateKeyHex string) ClientOption {
+	return WithBlockchainConfig(&blockchain.ClientConfig{
+		PrivateKeyHex: privateKeyHex,
+	})
+}
+
+// WithBlockchainConfig enables blockchain operations using the given configuration,
+// for example to restrict which networks are enabled. A zero Timeout defaults to the
+// HTTP client timeout. If initialization fails, the error is returned by Client.Err.
+func WithBlockchainConfig(config *blockchain.ClientConfig) ClientOption {
 	return func(c *Client) {
+		cfg := *config
+
 		// Remove 0x prefix if present
-		if len(privateKeyHex) > 2 && privateKeyHex[:2] == "0x" {
-			privateKeyHex = privateKeyHex[2:]
+		if len(cfg.PrivateKeyHex) > 2 && cfg.PrivateKeyHex[:2] == "0x" {
+			cfg.PrivateKeyHex = cfg.PrivateKeyHex[2:]
+		}
+		if cfg.Timeout == 0 {
+			cfg.Timeout = c.httpClient.Timeout
 		}
 
 		// Initialize blockchain client
-		blockchainClient, err := blockchain.NewClient(&blockchain.ClientConfig{
-			PrivateKeyHex: privateKeyHex,
-			Timeout:       c.httpClient.Timeout,
-		})
+		blockchainClient, err := blockchain.NewClient(&cfg)
 		if err != nil {
-			// For now, we'll silently skip blockchain initialization if it fails
-			// In a production environment, you might want to log this error
+			c.initErrs = append(c.initErrs, fmt.Errorf("failed to initialize blockchain client: %w", err))
 			return
 		}
 
 		c.Blockchain = NewBlockchainService(blockchainClient)
 	}
 }
+
+// WithAdmin enables the admin service for wallets holding IDRX roles such as PAUSER_ROLE
+// or BLACKLIST_ROLE. It requires WithBlockchain or WithBlockchainConfig, in any order.
+func WithAdmin(config AdminConfig) ClientOption {
+	return func(c *Client) {
+		c.adminConfig = &config
+	}
+}
+
+// WithSolana adds IDRX SPL token support on the configured Solana networks, so that the
+// *ByNetwork methods of Client.Blockchain also accept Solana network names such as
+// solana.SolanaMainnet. It requires WithBlockchain or WithBlockchainConfig, in any order.
+// A zero Timeout defaults to the HTTP client timeout.
+func WithSolana(config *solana.ClientConfig) ClientOption {
+	return func(c *Client) {
+		cfg := *config
+		if cfg.Timeout == 0 && cfg.HTTPClient == nil {
+			cfg.Timeout = c.httpClient.Timeout
+		}
+		c.solanaConfig = &cfg
+	}
+}
+
+// WithBankValidation enables Client.Banks, a cached directory of the banks returned by
+// GetMethods. AddBankAccount and RedeemRequest then reject unsupported bank codes, account
+// numbers that break the bank's format rules and redemptions above the bank's transfer limit
+// before sending them, and RedeemRequest fills in an empty BankName.
+func WithBankValidation(config BankDirectoryConfig) ClientOption {
+	return func(c *Client) {
+		c.bankDirectoryConfig = &config
+	}
+}
@@ -0,0 +1,35 @@
+package idrx
+
+import (
+	"testing"
+
+	"github.com/widnyana/idrx-go/blockchain"
+)
+
+func TestWithBlockchainInvalidKey(t *testing.T) {
+	client := NewClient(WithBlockchain("not-a-key"))
+
+	if client.Blockchain != nil {
+		t.Error("expected Blockchain to stay nil when initialization fails")
+	}
+	if client.Err() == nil {
+		t.Error("expected initialization error to be reported by Err")
+	}
+}
+
+func TestWithBlockchainConfig(t *testing.T) {
+	client := NewClient(WithBlockchainConfig(&blockchain.ClientConfig{
+		PrivateKeyHex: "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+		Networks:      []string{blockchain.BaseMainnet},
+	}))
+
+	if err := client.Err(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if client.Blockchain == nil {
+		t.Fatal("expected Blockchain service to be initialized")
+	}
+	if len(client.Blockchain.NetworkErrors()) != 0 {
+		t.Error("no connection should have been attempted yet")
+	}
+}
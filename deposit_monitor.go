@@ -0,0 +1,612 @@
+package idrx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/models"
+)
+
+// DepositStatus is the redemption state of a deposit
+type DepositStatus string
+
+// Deposit statuses
+const (
+	DepositPending  DepositStatus = "pending"  // No matching redemption yet, still within the SLA
+	DepositRedeemed DepositStatus = "redeemed" // A DEPOSIT_REDEEM entry matches the deposit
+	DepositOverdue  DepositStatus = "overdue"  // No matching redemption within the SLA
+	DepositFailed   DepositStatus = "failed"   // The matching redemption failed or was canceled
+)
+
+// redemptionClockSkew is how far before a deposit's block time a redemption entry may be
+// timestamped and still match it
+const redemptionClockSkew = 10 * time.Minute
+
+// Deposit is an IDRX transfer to a bank-account-linked deposit wallet
+type Deposit struct {
+	ChainID           uint64              `json:"chainId"`
+	TxHash            string              `json:"txHash"`
+	LogIndex          uint                `json:"logIndex"`
+	BlockNumber       uint64              `json:"blockNumber"`
+	From              string              `json:"from"`
+	Address           string              `json:"address"` // Deposit wallet
+	Amount            decimal.Decimal     `json:"amount"`
+	BankAccountID     int                 `json:"bankAccountId"`
+	BankAccountNumber string              `json:"bankAccountNumber"`
+	BankName          string              `json:"bankName"`
+	DepositedAt       time.Time           `json:"depositedAt"` // Block time; zero until the block header could be fetched
+	Status            DepositStatus       `json:"status"`
+	Redemption        *models.Transaction `json:"redemption,omitempty"` // Matching DEPOSIT_REDEEM entry
+}
+
+// DepositMonitorConfig configures a deposit monitor
+type DepositMonitorConfig struct {
+	SLA             time.Duration     // Time allowed between a deposit and its redemption (defaults to 1 hour)
+	RefreshInterval time.Duration     // How often bank accounts and redemptions are reloaded (defaults to 1 minute)
+	FromBlocks      map[uint64]uint64 // Replay deposits from these blocks per chain (defaults to the next block)
+	PollInterval    time.Duration     // Log polling interval (defaults to the network block time)
+	ForcePolling    bool              // Poll eth_getLogs even if the endpoint supports subscriptions
+	Retention       time.Duration     // How long redeemed deposits are kept after their block time (defaults to 24 hours)
+	OnUpdate        func(Deposit)     // Called after a deposit is seen and after every status change (optional)
+	OnError         func(error)       // Receives non-fatal errors such as a failed refresh (optional)
+}
+
+// DepositMonitor watches the deposit wallets of the member's bank accounts. IDRX sent to a
+// deposit wallet is redeemed to its bank account automatically; the monitor matches every
+// deposit with its DEPOSIT_REDEEM entry in the transaction history and flags deposits that
+// are not redeemed within the SLA.
+//
+// Redemptions are matched by transaction hash, or failing that by chain, deposit wallet and
+// amount, pairing the earliest deposit with the earliest entry.
+//
+// Deposits are kept in memory only. Completed redemptions are dropped once their deposit is
+// older than Retention; every other deposit is kept until it is redeemed. Replay FromBlocks
+// after a restart to rebuild the state.
+type DepositMonitor struct {
+	client *Client
+	config DepositMonitorConfig
+	now    func() time.Time
+
+	mu            sync.Mutex
+	wallets       map[uint64]map[common.Address]models.BankAccount
+	deposits      map[string]*Deposit
+	claimed       map[string]string    // Redemption ID -> deposit key
+	retired       map[string]time.Time // Redemption ID -> creation time, for deposits dropped after Retention
+	subscriptions map[uint64]*blockchain.EventSubscription
+	reported      map[string]bool // Wallet problems already passed to OnError
+}
+
+// NewDepositMonitor creates a deposit monitor. The client needs user authentication for the
+// bank account and transaction history endpoints, and blockchain operations for the chains
+// the deposit wallets are on.
+func NewDepositMonitor(client *Client, config DepositMonitorConfig) (*DepositMonitor, error) {
+	if client.Blockchain == nil {
+		return nil, errors.New("deposit monitor requires blockchain operations to be enabled")
+	}
+	if config.SLA <= 0 {
+		config.SLA = time.Hour
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = time.Minute
+	}
+	if config.Retention <= 0 {
+		config.Retention = 24 * time.Hour
+	}
+
+	return &DepositMonitor{
+		client:        client,
+		config:        config,
+		now:           time.Now,
+		wallets:       make(map[uint64]map[common.Address]models.BankAccount),
+		deposits:      make(map[string]*Deposit),
+		claimed:       make(map[string]string),
+		retired:       make(map[string]time.Time),
+		subscriptions: make(map[uint64]*blockchain.EventSubscription),
+		reported:      make(map[string]bool),
+	}, nil
+}
+
+// Run watches deposits until ctx is cancelled. Bank accounts and redemptions are reloaded
+// every RefreshInterval, so deposit wallets added while running are picked up. Run returns
+// an error only if the first bank account load fails; later failures go to OnError.
+func (m *DepositMonitor) Run(ctx context.Context) error {
+	if err := m.LoadWallets(ctx); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	defer func() {
+		m.mu.Lock()
+		subscriptions := m.subscriptions
+		m.subscriptions = make(map[uint64]*blockchain.EventSubscription)
+		m.mu.Unlock()
+
+		for _, subscription := range subscriptions {
+			subscription.Unsubscribe()
+		}
+		wg.Wait()
+	}()
+
+	m.subscribe(ctx, &wg)
+
+	ticker := time.NewTicker(m.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if err := m.LoadWallets(ctx); err != nil {
+			m.reportError(err)
+		}
+		m.subscribe(ctx, &wg)
+		if err := m.Correlate(ctx); err != nil {
+			m.reportError(err)
+		}
+	}
+}
+
+// LoadWallets reloads the active deposit wallets from GetBankAccounts. Wallets on chains
+// that are not supported are skipped and reported once through OnError.
+func (m *DepositMonitor) LoadWallets(ctx context.Context) error {
+	accounts, err := m.client.Account.GetBankAccounts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load deposit wallets: %w", err)
+	}
+
+	registry := m.client.Blockchain.client.Registry()
+	wallets := make(map[uint64]map[common.Address]models.BankAccount)
+	var problems []error
+	for _, account := range accounts {
+		wallet := account.DepositWalletAddress
+		if !account.IsActive || !wallet.IsActive || wallet.Address == "" {
+			continue
+		}
+
+		chainID, err := strconv.ParseUint(wallet.ChainID, 10, 64)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("bank account %d: invalid deposit chain ID %q", account.ID, wallet.ChainID))
+			continue
+		}
+		if _, _, exists := registry.GetByChainID(chainID); !exists {
+			problems = append(problems, fmt.Errorf("bank account %d: deposit chain %d is not supported", account.ID, chainID))
+			continue
+		}
+		address, err := parseAddress(wallet.Address)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("bank account %d: %w", account.ID, err))
+			continue
+		}
+
+		if wallets[chainID] == nil {
+			wallets[chainID] = make(map[common.Address]models.BankAccount)
+		}
+		wallets[chainID][address] = account
+	}
+
+	m.mu.Lock()
+	m.wallets = wallets
+	var fresh []error
+	for _, problem := range problems {
+		if !m.reported[problem.Error()] {
+			m.reported[problem.Error()] = true
+			fresh = append(fresh, problem)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, problem := range fresh {
+		m.reportError(problem)
+	}
+	return nil
+}
+
+// subscribe follows Transfer events on every chain with a deposit wallet not yet followed
+func (m *DepositMonitor) subscribe(ctx context.Context, wg *sync.WaitGroup) {
+	var failures []error
+	defer func() {
+		for _, err := range failures {
+			m.reportError(err)
+		}
+	}()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for chainID := range m.wallets {
+		if _, exists := m.subscriptions[chainID]; exists {
+			continue
+		}
+
+		options := &blockchain.SubscribeOptions{
+			Events:       []blockchain.EventType{blockchain.EventTransfer},
+			PollInterval: m.config.PollInterval,
+			ForcePolling: m.config.ForcePolling,
+		}
+		if from, exists := m.config.FromBlocks[chainID]; exists {
+			options.FromBlock = &from
+		}
+
+		subscription, err := m.client.Blockchain.client.SubscribeEvents(ctx, chainID, options)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("chain %d: %w", chainID, err))
+			continue
+		}
+		m.subscriptions[chainID] = subscription
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for event := range subscription.Events() {
+				if err := m.handleEvent(ctx, event); err != nil {
+					m.reportError(err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for err := range subscription.Err() {
+				m.reportError(fmt.Errorf("chain %d: %w", chainID, err))
+			}
+		}()
+	}
+}
+
+// handleEvent records a Transfer to a deposit wallet, or drops one removed by a reorganisation.
+// A deposit whose block time cannot be fetched is still recorded; Correlate fills it in.
+func (m *DepositMonitor) handleEvent(ctx context.Context, event *blockchain.Event) error {
+	transfer, ok := event.Data.(*models.TransferEvent)
+	if !ok {
+		return nil
+	}
+	key := depositKey(event.ChainID, event.Log.TxHash.Hex(), event.Log.Index)
+
+	m.mu.Lock()
+	account, watched := m.wallets[event.ChainID][transfer.To]
+	if event.Removed {
+		if deposit, exists := m.deposits[key]; exists {
+			if deposit.Redemption != nil {
+				delete(m.claimed, deposit.Redemption.ID)
+			}
+			delete(m.deposits, key)
+		}
+	}
+	_, seen := m.deposits[key]
+	m.mu.Unlock()
+
+	if event.Removed || !watched || seen {
+		return nil
+	}
+
+	depositedAt, err := m.blockTime(ctx, event.ChainID, event.Log.BlockNumber)
+
+	deposit := &Deposit{
+		ChainID:           event.ChainID,
+		TxHash:            event.Log.TxHash.Hex(),
+		LogIndex:          event.Log.Index,
+		BlockNumber:       event.Log.BlockNumber,
+		From:              transfer.From.Hex(),
+		Address:           transfer.To.Hex(),
+		Amount:            transfer.Value,
+		BankAccountID:     account.ID,
+		BankAccountNumber: account.BankAccountNumber,
+		BankName:          account.BankName,
+		DepositedAt:       depositedAt,
+		Status:            DepositPending,
+	}
+
+	m.mu.Lock()
+	if _, seen := m.deposits[key]; seen {
+		m.mu.Unlock()
+		return nil
+	}
+	m.deposits[key] = deposit
+	snapshot := *deposit
+	m.mu.Unlock()
+
+	m.notify(snapshot)
+	return err
+}
+
+// blockTime returns the timestamp of a block
+func (m *DepositMonitor) blockTime(ctx context.Context, chainID uint64, number uint64) (time.Time, error) {
+	backend, err := m.client.Blockchain.client.GetBackend(chainID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	header, err := backend.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get block %d on chain %d: %w", number, chainID, err)
+	}
+	return time.Unix(int64(header.Time), 0), nil
+}
+
+// Correlate fills in missing block times, loads DEPOSIT_REDEEM entries since the earliest
+// unredeemed deposit, matches them with deposits, marks deposits past the SLA as overdue and
+// drops redeemed deposits older than Retention. Deposits without a block time are left out
+// until it is known.
+func (m *DepositMonitor) Correlate(ctx context.Context) error {
+	updated, timeErr := m.fillBlockTimes(ctx)
+
+	m.mu.Lock()
+	var since time.Time
+	for _, deposit := range m.deposits {
+		if deposit.DepositedAt.IsZero() || (deposit.Redemption != nil && deposit.Redemption.Status == models.TransactionStatusCompleted) {
+			continue
+		}
+		if since.IsZero() || deposit.DepositedAt.Before(since) {
+			since = deposit.DepositedAt
+		}
+	}
+	m.mu.Unlock()
+
+	if !since.IsZero() {
+		redemptions, err := m.redemptions(ctx, since.Add(-redemptionClockSkew))
+		if err != nil {
+			for _, deposit := range updated {
+				m.notify(deposit)
+			}
+			return errors.Join(timeErr, err)
+		}
+
+		m.mu.Lock()
+		updated = append(updated, m.match(redemptions)...)
+		m.mu.Unlock()
+	}
+
+	m.mu.Lock()
+	now := m.now()
+	for _, deposit := range m.pendingDeposits() {
+		if deposit.Status == DepositPending && !deposit.DepositedAt.IsZero() && now.Sub(deposit.DepositedAt) > m.config.SLA {
+			deposit.Status = DepositOverdue
+			updated = append(updated, *deposit)
+		}
+	}
+	m.prune(now, since)
+	m.mu.Unlock()
+
+	for _, deposit := range updated {
+		m.notify(deposit)
+	}
+	return timeErr
+}
+
+// fillBlockTimes fetches the block times that were not available when deposits were seen
+func (m *DepositMonitor) fillBlockTimes(ctx context.Context) ([]Deposit, error) {
+	m.mu.Lock()
+	var missing []Deposit
+	for _, deposit := range m.deposits {
+		if deposit.DepositedAt.IsZero() {
+			missing = append(missing, *deposit)
+		}
+	}
+	m.mu.Unlock()
+
+	var updated []Deposit
+	var errs []error
+	for _, deposit := range missing {
+		depositedAt, err := m.blockTime(ctx, deposit.ChainID, deposit.BlockNumber)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		m.mu.Lock()
+		if current, exists := m.deposits[depositKey(deposit.ChainID, deposit.TxHash, deposit.LogIndex)]; exists {
+			current.DepositedAt = depositedAt
+			updated = append(updated, *current)
+		}
+		m.mu.Unlock()
+	}
+	return updated, errors.Join(errs...)
+}
+
+// prune drops redeemed deposits older than Retention. Their redemptions are remembered until
+// they fall before since, the start of the redemptions loaded, so they cannot be matched
+// with another deposit. The lock is held.
+func (m *DepositMonitor) prune(now, since time.Time) {
+	for key, deposit := range m.deposits {
+		if deposit.Status != DepositRedeemed || deposit.Redemption.Status != models.TransactionStatusCompleted ||
+			now.Sub(deposit.DepositedAt) <= m.config.Retention {
+			continue
+		}
+		delete(m.deposits, key)
+		delete(m.claimed, deposit.Redemption.ID)
+		m.retired[deposit.Redemption.ID] = deposit.Redemption.CreatedAt
+	}
+
+	if since.IsZero() {
+		return
+	}
+	for id, createdAt := range m.retired {
+		if createdAt.Before(since.Add(-redemptionClockSkew)) {
+			delete(m.retired, id)
+		}
+	}
+}
+
+// redemptions loads every DEPOSIT_REDEEM entry created since a time
+func (m *DepositMonitor) redemptions(ctx context.Context, since time.Time) ([]models.Transaction, error) {
+	var entries []models.Transaction
+	for page := 1; ; page++ {
+		response, err := m.client.Transaction.GetTransactionHistory(ctx, &models.TransactionHistoryRequest{
+			TransactionType: models.TransactionTypeDepositRedeem,
+			Page:            page,
+			Take:            100,
+			StartDate:       &since,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load redemptions: %w", err)
+		}
+
+		entries = append(entries, response.Data...)
+		if len(response.Data) == 0 || page >= response.Metadata.TotalPages {
+			return entries, nil
+		}
+	}
+}
+
+// match pairs unclaimed redemptions with deposits and returns the deposits that changed.
+// Entries already matched are refreshed so a later failure or completion is seen.
+func (m *DepositMonitor) match(redemptions []models.Transaction) []Deposit {
+	sort.SliceStable(redemptions, func(i, j int) bool {
+		return redemptions[i].CreatedAt.Before(redemptions[j].CreatedAt)
+	})
+
+	var updated []Deposit
+	for i := range redemptions {
+		redemption := redemptions[i]
+		if _, retired := m.retired[redemption.ID]; retired {
+			continue
+		}
+
+		deposit := m.deposits[m.claimed[redemption.ID]]
+		if deposit == nil {
+			deposit = m.findDeposit(&redemption)
+		}
+		if deposit == nil {
+			continue
+		}
+
+		status := DepositRedeemed
+		if redemption.Status == models.TransactionStatusFailed || redemption.Status == models.TransactionStatusCanceled {
+			status = DepositFailed
+		}
+		changed := deposit.Redemption == nil || deposit.Redemption.Status != redemption.Status || deposit.Status != status
+
+		m.claimed[redemption.ID] = depositKey(deposit.ChainID, deposit.TxHash, deposit.LogIndex)
+		deposit.Redemption = &redemption
+		deposit.Status = status
+		if changed {
+			updated = append(updated, *deposit)
+		}
+	}
+	return updated
+}
+
+// findDeposit returns the unmatched deposit a redemption belongs to: the one with the same
+// transaction hash, otherwise the earliest with the same chain, wallet and amount
+func (m *DepositMonitor) findDeposit(redemption *models.Transaction) *Deposit {
+	candidates := m.pendingDeposits()
+
+	if redemption.TxHash != "" {
+		for _, deposit := range candidates {
+			if strings.EqualFold(deposit.TxHash, redemption.TxHash) {
+				return deposit
+			}
+		}
+	}
+
+	amount, err := decimal.NewFromString(redemption.Amount)
+	if err != nil {
+		return nil
+	}
+	for _, deposit := range candidates {
+		if strconv.FormatUint(deposit.ChainID, 10) != redemption.ChainID || !deposit.Amount.Equal(amount) {
+			continue
+		}
+		if redemption.WalletAddress != "" && !strings.EqualFold(deposit.Address, redemption.WalletAddress) {
+			continue
+		}
+		if redemption.WalletAddress == "" && redemption.BankAccount != deposit.BankAccountNumber {
+			continue
+		}
+		if redemption.CreatedAt.Before(deposit.DepositedAt.Add(-redemptionClockSkew)) {
+			continue
+		}
+		return deposit
+	}
+	return nil
+}
+
+// pendingDeposits returns deposits without a redemption, oldest first
+func (m *DepositMonitor) pendingDeposits() []*Deposit {
+	var pending []*Deposit
+	for _, deposit := range m.deposits {
+		if deposit.Redemption == nil {
+			pending = append(pending, deposit)
+		}
+	}
+	sortDeposits(pending)
+	return pending
+}
+
+// Deposits returns every deposit seen, oldest first
+func (m *DepositMonitor) Deposits() []Deposit {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	all := make([]*Deposit, 0, len(m.deposits))
+	for _, deposit := range m.deposits {
+		all = append(all, deposit)
+	}
+	sortDeposits(all)
+
+	deposits := make([]Deposit, len(all))
+	for i, deposit := range all {
+		deposits[i] = *deposit
+	}
+	return deposits
+}
+
+// Breaches returns the deposits that were not redeemed within the SLA, including those whose
+// redemption failed, as of the last Correlate
+func (m *DepositMonitor) Breaches() []Deposit {
+	var breaches []Deposit
+	for _, deposit := range m.Deposits() {
+		if deposit.Status == DepositOverdue || deposit.Status == DepositFailed {
+			breaches = append(breaches, deposit)
+		}
+	}
+	return breaches
+}
+
+// notify passes a deposit to OnUpdate
+func (m *DepositMonitor) notify(deposit Deposit) {
+	if m.config.OnUpdate != nil {
+		m.config.OnUpdate(deposit)
+	}
+}
+
+// reportError passes a non-fatal error to OnError
+func (m *DepositMonitor) reportError(err error) {
+	if m.config.OnError != nil {
+		m.config.OnError(err)
+	}
+}
+
+// depositKey identifies a deposit by its log
+func depositKey(chainID uint64, txHash string, logIndex uint) string {
+	return fmt.Sprintf("%d:%s:%d", chainID, strings.ToLower(txHash), logIndex)
+}
+
+// sortDeposits orders deposits by block time, then chain and log position
+func sortDeposits(deposits []*Deposit) {
+	sort.Slice(deposits, func(i, j int) bool {
+		a, b := deposits[i], deposits[j]
+		if !a.DepositedAt.Equal(b.DepositedAt) {
+			return a.DepositedAt.Before(b.DepositedAt)
+		}
+		if a.ChainID != b.ChainID {
+			return a.ChainID < b.ChainID
+		}
+		if a.BlockNumber != b.BlockNumber {
+			return a.BlockNumber < b.BlockNumber
+		}
+		return a.LogIndex < b.LogIndex
+	})
+}
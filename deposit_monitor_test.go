@@ -0,0 +1,277 @@
+package idrx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/contracts"
+	"github.com/widnyana/idrx-go/internal/backendtest"
+	"github.com/widnyana/idrx-go/models"
+)
+
+var (
+	depositWallet = common.HexToAddress("0x00000000000000000000000000000000000000d1")
+)
+
+// logChain serves a fixed set of logs from blocks whose timestamps are given
+type logChain struct {
+	backendtest.Chain
+
+	blockTime time.Time
+	headerErr error // Returned by HeaderByNumber when set
+	logs      []types.Log
+}
+
+func (c *logChain) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	if c.headerErr != nil {
+		return nil, c.headerErr
+	}
+	return &types.Header{Number: number, Time: uint64(c.blockTime.Unix())}, nil
+}
+
+func (c *logChain) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	for _, log := range c.logs {
+		if log.BlockNumber >= query.FromBlock.Uint64() && log.BlockNumber <= query.ToBlock.Uint64() {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+// transferLog builds a Transfer log of whole tokens (the devnet uses 2 decimals)
+func transferLog(t *testing.T, to common.Address, tokens int64, block uint64, txHash common.Hash) types.Log {
+	t.Helper()
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+	return types.Log{
+		Address: backendtest.Contract,
+		Topics: []common.Hash{
+			parsed.Events["Transfer"].ID,
+			common.BytesToHash(common.HexToAddress("0x00000000000000000000000000000000000000f0").Bytes()),
+			common.BytesToHash(to.Bytes()),
+		},
+		Data:        common.LeftPadBytes(big.NewInt(tokens*100).Bytes(), 32),
+		BlockNumber: block,
+		TxHash:      txHash,
+	}
+}
+
+// newDepositTestClient serves one bank account with depositWallet on the devnet and the given
+// DEPOSIT_REDEEM entries
+func newDepositTestClient(t *testing.T, chain blockchain.Backend, redemptions []models.Transaction) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/auth/get-bank-accounts":
+			_ = json.NewEncoder(w).Encode(models.BankAccountsResponse{Data: []models.BankAccount{{
+				ID:                7,
+				BankAccountNumber: "1234567890",
+				BankName:          "BANK CENTRAL ASIA",
+				IsActive:          true,
+				DepositWalletAddress: models.DepositWallet{
+					Address:  depositWallet.Hex(),
+					ChainID:  "31337",
+					IsActive: true,
+				},
+			}}})
+		case "/api/transaction/user-transaction-history":
+			if r.URL.Query().Get("transactionType") != string(models.TransactionTypeDepositRedeem) {
+				t.Errorf("unexpected transaction type %s", r.URL.Query().Get("transactionType"))
+			}
+			_ = json.NewEncoder(w).Encode(models.TransactionHistoryResponse{
+				Data:     redemptions,
+				Metadata: models.ListMetadata{Page: 1, Take: 100, Total: len(redemptions), TotalPages: 1},
+			})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := &Client{
+		baseURL:    server.URL,
+		httpClient: server.Client(),
+		auth:       NewUserAuth("user-key", testSecretKey()),
+		Blockchain: NewBlockchainService(backendtest.NewClient(t, chain)),
+	}
+	client.Account = &AccountService{client: client}
+	client.Transaction = &TransactionService{client: client}
+	return client
+}
+
+func TestDepositMonitorReportsUnredeemedDeposits(t *testing.T) {
+	depositedAt := time.Now().Add(-2 * time.Hour)
+	chain := &logChain{
+		Chain:     backendtest.Chain{Head: 12},
+		blockTime: depositedAt,
+		logs: []types.Log{
+			transferLog(t, depositWallet, 500, 10, common.Hash{0x01}), // redeemed, matched by hash
+			transferLog(t, depositWallet, 750, 11, common.Hash{0x02}), // redeemed, matched by wallet and amount
+			transferLog(t, depositWallet, 300, 12, common.Hash{0x03}), // never redeemed
+			transferLog(t, common.HexToAddress("0x00000000000000000000000000000000000000d2"), 900, 12, common.Hash{0x04}),
+		},
+	}
+
+	client := newDepositTestClient(t, chain, []models.Transaction{
+		{
+			ID: "r1", Type: models.TransactionTypeDepositRedeem, Status: models.TransactionStatusCompleted,
+			Amount: "500", ChainID: "31337", TxHash: common.Hash{0x01}.Hex(), CreatedAt: depositedAt.Add(time.Minute),
+		},
+		{
+			ID: "r2", Type: models.TransactionTypeDepositRedeem, Status: models.TransactionStatusCompleted,
+			Amount: "750", ChainID: "31337", WalletAddress: depositWallet.Hex(), CreatedAt: depositedAt.Add(2 * time.Minute),
+		},
+	})
+
+	var mu sync.Mutex
+	statuses := map[string]DepositStatus{}
+	done := make(chan struct{})
+	monitor, err := NewDepositMonitor(client, DepositMonitorConfig{
+		RefreshInterval: 10 * time.Millisecond,
+		PollInterval:    10 * time.Millisecond,
+		ForcePolling:    true,
+		FromBlocks:      map[uint64]uint64{backendtest.ChainID: 0},
+		OnUpdate: func(deposit Deposit) {
+			mu.Lock()
+			defer mu.Unlock()
+			statuses[deposit.TxHash] = deposit.Status
+			if len(statuses) == 3 && statuses[common.Hash{0x03}.Hex()] == DepositOverdue {
+				select {
+				case <-done:
+				default:
+					close(done)
+				}
+			}
+		},
+		OnError: func(err error) { t.Errorf("unexpected error: %v", err) },
+	})
+	if err != nil {
+		t.Fatalf("failed to create monitor: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go func() {
+		<-done
+		cancel()
+	}()
+	_ = monitor.Run(ctx)
+
+	deposits := monitor.Deposits()
+	if len(deposits) != 3 {
+		t.Fatalf("expected 3 deposits, got %d", len(deposits))
+	}
+	for i, want := range []DepositStatus{DepositRedeemed, DepositRedeemed, DepositOverdue} {
+		if deposits[i].Status != want {
+			t.Errorf("deposit %d: expected %s, got %s", i, want, deposits[i].Status)
+		}
+	}
+	if deposits[1].Redemption == nil || deposits[1].Redemption.ID != "r2" {
+		t.Errorf("expected deposit 1 to match redemption r2, got %+v", deposits[1].Redemption)
+	}
+	if deposits[0].BankAccountNumber != "1234567890" || !deposits[0].DepositedAt.Equal(depositedAt.Truncate(time.Second)) {
+		t.Errorf("unexpected deposit details: %+v", deposits[0])
+	}
+
+	unredeemed := common.Hash{0x03}.Hex()
+	breaches := monitor.Breaches()
+	if len(breaches) != 1 || breaches[0].TxHash != unredeemed || breaches[0].Amount.IntPart() != 300 {
+		t.Errorf("expected the 300 IDRX deposit to breach the SLA, got %+v", breaches)
+	}
+}
+
+// seeDeposit passes a transfer to the monitor as if its subscription delivered it
+func seeDeposit(t *testing.T, monitor *DepositMonitor, log types.Log) error {
+	t.Helper()
+	event, err := monitor.client.Blockchain.client.DecodeEvent(backendtest.ChainID, log)
+	if err != nil {
+		t.Fatalf("failed to decode log: %v", err)
+	}
+	return monitor.handleEvent(context.Background(), event)
+}
+
+func TestDepositMonitorRetriesBlockTime(t *testing.T) {
+	chain := &logChain{Chain: backendtest.Chain{Head: 10}, blockTime: time.Now().Add(-time.Minute), headerErr: errors.New("header unavailable")}
+	monitor, err := NewDepositMonitor(newDepositTestClient(t, chain, nil), DepositMonitorConfig{})
+	if err != nil {
+		t.Fatalf("failed to create monitor: %v", err)
+	}
+	if err := monitor.LoadWallets(context.Background()); err != nil {
+		t.Fatalf("failed to load wallets: %v", err)
+	}
+
+	if err := seeDeposit(t, monitor, transferLog(t, depositWallet, 500, 10, common.Hash{0x01})); err == nil {
+		t.Fatal("expected the block time failure to be reported")
+	}
+	deposits := monitor.Deposits()
+	if len(deposits) != 1 || !deposits[0].DepositedAt.IsZero() {
+		t.Fatalf("expected the deposit kept without a block time, got %+v", deposits)
+	}
+	if err := monitor.Correlate(context.Background()); err == nil {
+		t.Error("expected Correlate to report the block time failure")
+	}
+
+	chain.headerErr = nil
+	if err := monitor.Correlate(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deposits = monitor.Deposits()
+	if !deposits[0].DepositedAt.Equal(chain.blockTime.Truncate(time.Second)) || deposits[0].Status != DepositPending {
+		t.Errorf("expected the block time filled in, got %+v", deposits[0])
+	}
+}
+
+func TestDepositMonitorPrunesRedeemedDeposits(t *testing.T) {
+	depositedAt := time.Now().Add(-48 * time.Hour)
+	chain := &logChain{Chain: backendtest.Chain{Head: 11}, blockTime: depositedAt}
+	client := newDepositTestClient(t, chain, []models.Transaction{{
+		ID: "r1", Type: models.TransactionTypeDepositRedeem, Status: models.TransactionStatusCompleted, Amount: "500",
+		ChainID: "31337", TxHash: common.Hash{0x01}.Hex(), WalletAddress: depositWallet.Hex(), CreatedAt: depositedAt.Add(time.Minute),
+	}})
+	monitor, err := NewDepositMonitor(client, DepositMonitorConfig{})
+	if err != nil {
+		t.Fatalf("failed to create monitor: %v", err)
+	}
+	if err := monitor.LoadWallets(context.Background()); err != nil {
+		t.Fatalf("failed to load wallets: %v", err)
+	}
+
+	if err := seeDeposit(t, monitor, transferLog(t, depositWallet, 500, 10, common.Hash{0x01})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := monitor.Correlate(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deposits := monitor.Deposits(); len(deposits) != 0 {
+		t.Fatalf("expected the redeemed deposit dropped after the retention, got %+v", deposits)
+	}
+
+	// The dropped deposit's redemption must not be matched with a later deposit of the same amount
+	if err := seeDeposit(t, monitor, transferLog(t, depositWallet, 500, 11, common.Hash{0x02})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := monitor.Correlate(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deposits := monitor.Deposits()
+	if len(deposits) != 1 || deposits[0].Status != DepositOverdue || deposits[0].Redemption != nil {
+		t.Errorf("expected the second deposit overdue without a redemption, got %+v", deposits)
+	}
+}
@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"time"
 
 	idrx "github.com/widnyana/idrx-go"
-	"github.com/widnyana/idrx-go/blockchain"
 )
 
 func main() {
@@ -27,10 +27,13 @@ func main() {
 	)
 
 	// Check if blockchain service was initialized successfully
-	if client.Blockchain == nil {
-		log.Fatal("Failed to initialize blockchain service. Check your private key.")
+	if err := client.Err(); err != nil {
+		log.Fatalf("Failed to initialize blockchain service: %v", err)
 	}
 
+	// Look networks up in the client's registry so custom and testnet registries work too
+	registry := client.Blockchain.Client().Registry()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -40,45 +43,37 @@ func main() {
 		"0x1234567890123456789012345678901234567890", // Example wallet 2
 	}
 
-	// Networks to check across
-	networks := []string{
-		blockchain.BaseMainnet,
-		blockchain.BSCMainnet,
-		blockchain.EtherlinkMainnet,
-		blockchain.GnosisMainnet,
-		blockchain.KaiaMainnet,
-		blockchain.LiskMainnet,
-		blockchain.PolygonMainnet,
-		blockchain.WorldChainMainnet,
-	}
+	// Networks to check across: every network the client has enabled
+	networks := client.Blockchain.Client().EnabledNetworks()
+	sort.Strings(networks)
 
 	fmt.Println("=== IDRX Blacklist Verification ===")
 
-	// Check each wallet across multiple networks
-	for _, walletAddr := range walletsToCheck {
-		fmt.Printf("Checking wallet: %s\n", walletAddr)
+	// Check all wallets on each network with a single batched request per network
+	for _, networkName := range networks {
+		// Get network configuration
+		networkConfig, exists := registry.Get(networkName)
+		if !exists {
+			log.Printf("Warning: Network %s not found\n", networkName)
+			continue
+		}
+
+		fmt.Printf("%s (Chain %d)\n", networkConfig.Name, networkConfig.ChainID)
 		fmt.Println("─────────────────────────────────────────────────────")
 
-		for _, networkName := range networks {
-			// Get network configuration
-			networkConfig, exists := blockchain.GetNetworkConfig(networkName)
-			if !exists {
-				log.Printf("Warning: Network %s not found\n", networkName)
-				continue
-			}
+		// Check blacklist status; failed lookups are reported in err and left out of statuses
+		statuses, err := client.Blockchain.AreAddressesBlacklisted(
+			ctx,
+			networkConfig.ChainID,
+			walletsToCheck,
+		)
+		if err != nil {
+			log.Printf("✗ %s: Error - %v\n", networkConfig.Name, err)
+		}
 
-			// Check blacklist status
-			isBlacklisted, err := client.Blockchain.IsAddressBlacklisted(
-				ctx,
-				networkConfig.ChainID,
-				walletAddr,
-			)
-			if err != nil {
-				log.Printf("✗ %s (Chain %d): Error - %v\n",
-					networkConfig.Name,
-					networkConfig.ChainID,
-					err,
-				)
+		for _, walletAddr := range walletsToCheck {
+			isBlacklisted, ok := statuses[walletAddr]
+			if !ok {
 				continue
 			}
 
@@ -88,11 +83,7 @@ func main() {
 				status = "✗ BLACKLISTED"
 			}
 
-			fmt.Printf("  %s (Chain %d): %s\n",
-				networkConfig.Name,
-				networkConfig.ChainID,
-				status,
-			)
+			fmt.Printf("  %s: %s\n", walletAddr, status)
 		}
 
 		fmt.Println()
@@ -103,7 +94,10 @@ func main() {
 	fmt.Println()
 
 	for _, networkName := range networks {
-		networkConfig, _ := blockchain.GetNetworkConfig(networkName)
+		networkConfig, exists := registry.Get(networkName)
+		if !exists {
+			continue
+		}
 		fmt.Printf("%s\n", networkConfig.Name)
 		fmt.Printf("  Chain ID:         %d\n", networkConfig.ChainID)
 		fmt.Printf("  Contract Address: %s\n", networkConfig.ContractAddress.Hex())
@@ -0,0 +1,257 @@
+// Package export streams the REST transaction history into files for accounting: CSV, JSON
+// Lines and a double-entry journal. Pages are written as they are fetched, so memory use does
+// not grow with the size of the history.
+package export
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/models"
+)
+
+// WIB is Western Indonesia Time (UTC+7), the default timezone of exported times
+var WIB = time.FixedZone("WIB", 7*60*60)
+
+// pageSize is the largest page the transaction history endpoint serves
+const pageSize = 100
+
+// HistorySource provides the REST transaction history; *idrx.TransactionService implements it
+type HistorySource interface {
+	GetTransactionHistory(ctx context.Context, req *models.TransactionHistoryRequest) (*models.TransactionHistoryResponse, error)
+}
+
+// Writer receives exported transactions one at a time
+type Writer interface {
+	Write(transaction *models.Transaction) error
+	// Flush writes buffered output to the underlying writer
+	Flush() error
+}
+
+// Query selects the transactions to export
+type Query struct {
+	Types   []models.TransactionType  // Defaults to MINT, BURN, BRIDGE and DEPOSIT_REDEEM
+	Status  *models.TransactionStatus // Optional
+	ChainID *string                   // Optional
+	Start   *time.Time                // Optional, inclusive
+	End     *time.Time                // Optional
+}
+
+// AllTypes are the transaction types exported when Query.Types is empty
+var AllTypes = []models.TransactionType{
+	models.TransactionTypeMint,
+	models.TransactionTypeBurn,
+	models.TransactionTypeBridge,
+	models.TransactionTypeDepositRedeem,
+}
+
+// Month returns the start and end of a calendar month in WIB, for monthly statements
+func Month(year int, month time.Month) (start, end time.Time) {
+	start = time.Date(year, month, 1, 0, 0, 0, 0, WIB)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// Export fetches every page of the matching transactions, type by type, and passes each
+// transaction to every writer. Writers are flushed after each page. It returns the number
+// of transactions exported.
+func Export(ctx context.Context, history HistorySource, query Query, writers ...Writer) (int, error) {
+	types := query.Types
+	if len(types) == 0 {
+		types = AllTypes
+	}
+
+	count := 0
+	for _, transactionType := range types {
+		for page := 1; ; page++ {
+			response, err := history.GetTransactionHistory(ctx, &models.TransactionHistoryRequest{
+				TransactionType: transactionType,
+				Page:            page,
+				Take:            pageSize,
+				Status:          query.Status,
+				ChainID:         query.ChainID,
+				StartDate:       query.Start,
+				EndDate:         query.End,
+			})
+			if err != nil {
+				return count, fmt.Errorf("failed to load %s transactions page %d: %w", transactionType, page, err)
+			}
+
+			for i := range response.Data {
+				for _, writer := range writers {
+					if err := writer.Write(&response.Data[i]); err != nil {
+						return count, fmt.Errorf("transaction %s: %w", response.Data[i].ID, err)
+					}
+				}
+				count++
+			}
+			for _, writer := range writers {
+				if err := writer.Flush(); err != nil {
+					return count, err
+				}
+			}
+
+			if len(response.Data) == 0 || page >= response.Metadata.TotalPages {
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+// Options control the columns and time format of CSV and JSON Lines output
+type Options struct {
+	Columns    []string       // Column names in output order (defaults to DefaultColumns)
+	Location   *time.Location // Timezone of exported times (defaults to WIB)
+	TimeFormat string         // Layout of exported times (defaults to time.RFC3339)
+}
+
+// DefaultColumns are exported when Options.Columns is empty; see Columns for all names
+var DefaultColumns = []string{
+	"id", "type", "status", "amount", "currency", "fees", "chain_id", "chain_name", "tx_hash",
+	"wallet_address", "bank_account", "bank_name", "reference_number", "created_at", "completed_at",
+}
+
+// Fee is an active fee line of a transaction
+type Fee struct {
+	Name   string          `json:"name"`
+	Amount decimal.Decimal `json:"amount"`
+}
+
+// column extracts one exported value. Values are strings, decimals, fee lists or nil.
+type column func(transaction *models.Transaction, format timeFormatter) (interface{}, error)
+
+// timeFormatter formats times in the configured zone and layout
+type timeFormatter func(t time.Time) string
+
+// columns maps column names to their values
+var columns = map[string]column{
+	"id":               text(func(t *models.Transaction) string { return t.ID }),
+	"type":             text(func(t *models.Transaction) string { return string(t.Type) }),
+	"status":           text(func(t *models.Transaction) string { return string(t.Status) }),
+	"amount":           func(t *models.Transaction, _ timeFormatter) (interface{}, error) { return parseAmount(t.Amount) },
+	"currency":         text(func(t *models.Transaction) string { return t.Currency }),
+	"fees":             func(t *models.Transaction, _ timeFormatter) (interface{}, error) { return activeFees(t) },
+	"total_fees":       totalFees,
+	"chain_id":         text(func(t *models.Transaction) string { return t.ChainID }),
+	"chain_name":       text(func(t *models.Transaction) string { return t.ChainName }),
+	"tx_hash":          text(func(t *models.Transaction) string { return t.TxHash }),
+	"wallet_address":   text(func(t *models.Transaction) string { return t.WalletAddress }),
+	"bank_account":     text(func(t *models.Transaction) string { return t.BankAccount }),
+	"bank_name":        text(func(t *models.Transaction) string { return t.BankName }),
+	"reference_number": text(func(t *models.Transaction) string { return t.ReferenceNumber }),
+	"notes":            text(func(t *models.Transaction) string { return t.Notes }),
+	"created_at":       timestamp(func(t *models.Transaction) *time.Time { return &t.CreatedAt }),
+	"updated_at":       timestamp(func(t *models.Transaction) *time.Time { return &t.UpdatedAt }),
+	"completed_at":     timestamp(func(t *models.Transaction) *time.Time { return t.CompletedAt }),
+}
+
+// Columns returns every supported column name
+func Columns() []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func text(value func(*models.Transaction) string) column {
+	return func(t *models.Transaction, _ timeFormatter) (interface{}, error) {
+		return value(t), nil
+	}
+}
+
+func timestamp(value func(*models.Transaction) *time.Time) column {
+	return func(t *models.Transaction, format timeFormatter) (interface{}, error) {
+		at := value(t)
+		if at == nil || at.IsZero() {
+			return nil, nil
+		}
+		return format(*at), nil
+	}
+}
+
+func totalFees(t *models.Transaction, _ timeFormatter) (interface{}, error) {
+	fees, err := activeFees(t)
+	if err != nil {
+		return nil, err
+	}
+	total := decimal.Zero
+	for _, fee := range fees {
+		total = total.Add(fee.Amount)
+	}
+	return total, nil
+}
+
+// parseAmount reads a REST amount as a decimal
+func parseAmount(amount string) (decimal.Decimal, error) {
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("invalid amount %q", amount)
+	}
+	return value, nil
+}
+
+// activeFees returns a transaction's active fee lines with decimal amounts
+func activeFees(t *models.Transaction) ([]Fee, error) {
+	fees := []Fee{}
+	for _, fee := range t.Fees {
+		if !fee.IsActive {
+			continue
+		}
+		amount, err := parseAmount(fee.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("fee %s: %w", fee.Name, err)
+		}
+		fees = append(fees, Fee{Name: fee.Name, Amount: amount})
+	}
+	return fees, nil
+}
+
+// resolved is a validated set of options
+type resolved struct {
+	names   []string
+	columns []column
+	format  timeFormatter
+}
+
+// resolve validates column names and fills defaults
+func (o Options) resolve() (*resolved, error) {
+	names := o.Columns
+	if len(names) == 0 {
+		names = DefaultColumns
+	}
+	r := &resolved{names: names, format: newTimeFormatter(o.Location, o.TimeFormat)}
+
+	var unknown []string
+	for _, name := range names {
+		c, exists := columns[name]
+		if !exists {
+			unknown = append(unknown, name)
+			continue
+		}
+		r.columns = append(r.columns, c)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown columns: %s", strings.Join(unknown, ", "))
+	}
+	return r, nil
+}
+
+// newTimeFormatter formats times in location with layout, defaulting to WIB and RFC 3339
+func newTimeFormatter(location *time.Location, layout string) timeFormatter {
+	if location == nil {
+		location = WIB
+	}
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return func(t time.Time) string {
+		return t.In(location).Format(layout)
+	}
+}
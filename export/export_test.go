@@ -0,0 +1,167 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/models"
+)
+
+// pagedHistory serves transactions in pages of two and records the pages requested
+type pagedHistory struct {
+	transactions map[models.TransactionType][]models.Transaction
+	requests     []string
+}
+
+func (h *pagedHistory) GetTransactionHistory(_ context.Context, req *models.TransactionHistoryRequest) (*models.TransactionHistoryResponse, error) {
+	h.requests = append(h.requests, fmt.Sprintf("%s:%d", req.TransactionType, req.Page))
+
+	all := h.transactions[req.TransactionType]
+	start := min((req.Page-1)*2, len(all))
+	end := min(start+2, len(all))
+	return &models.TransactionHistoryResponse{
+		Data:     all[start:end],
+		Metadata: models.ListMetadata{Page: req.Page, Total: len(all), TotalPages: (len(all) + 1) / 2},
+	}, nil
+}
+
+func testHistory() *pagedHistory {
+	created := time.Date(2025, 1, 31, 20, 0, 0, 0, time.UTC) // 1 February 03:00 WIB
+	fees := []models.Fee{
+		{Name: "Platform fee", Amount: "2500", IsActive: true},
+		{Name: "Retired fee", Amount: "9999", IsActive: false},
+	}
+	return &pagedHistory{transactions: map[models.TransactionType][]models.Transaction{
+		models.TransactionTypeMint: {
+			{ID: "m1", Type: models.TransactionTypeMint, Status: models.TransactionStatusCompleted, Amount: "1000000.000001", Fees: fees, CreatedAt: created},
+			{ID: "m2", Type: models.TransactionTypeMint, Status: models.TransactionStatusFailed, Amount: "5", CreatedAt: created},
+			{ID: "m3", Type: models.TransactionTypeMint, Status: models.TransactionStatusCompleted, Amount: "10", CreatedAt: created},
+		},
+		models.TransactionTypeBurn: {
+			{ID: "b1", Type: models.TransactionTypeBurn, Status: models.TransactionStatusCompleted, Amount: "50000", Fees: fees, CreatedAt: created},
+		},
+		models.TransactionTypeBridge: {
+			{ID: "x1", Type: models.TransactionTypeBridge, Status: models.TransactionStatusCompleted, Amount: "700", Fees: fees, CreatedAt: created},
+		},
+	}}
+}
+
+func TestExportStreamsEveryPage(t *testing.T) {
+	history := testHistory()
+
+	var csvOut, jsonlOut, journalOut bytes.Buffer
+	csvWriter, err := NewCSVWriter(&csvOut, Options{Columns: []string{"id", "amount", "fees", "created_at"}})
+	if err != nil {
+		t.Fatalf("NewCSVWriter: %v", err)
+	}
+	jsonlWriter, err := NewJSONLWriter(&jsonlOut, Options{Columns: []string{"id", "amount", "total_fees", "completed_at"}})
+	if err != nil {
+		t.Fatalf("NewJSONLWriter: %v", err)
+	}
+	journal := NewJournalWriter(&journalOut, JournalOptions{})
+
+	count, err := Export(context.Background(), history, Query{}, csvWriter, jsonlWriter, journal)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("expected 5 transactions, got %d", count)
+	}
+	wantRequests := "MINT:1 MINT:2 BURN:1 BRIDGE:1 DEPOSIT_REDEEM:1"
+	if got := strings.Join(history.requests, " "); got != wantRequests {
+		t.Errorf("requested pages %s, want %s", got, wantRequests)
+	}
+
+	rows, err := csv.NewReader(&csvOut).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 6 || strings.Join(rows[0], ",") != "id,amount,fees,created_at" {
+		t.Fatalf("unexpected CSV: %v", rows)
+	}
+	if want := []string{"m1", "1000000.000001", "Platform fee=2500", "2025-02-01T03:00:00+07:00"}; strings.Join(rows[1], "|") != strings.Join(want, "|") {
+		t.Errorf("row = %v, want %v", rows[1], want)
+	}
+
+	lines := strings.Split(strings.TrimSpace(jsonlOut.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 JSON lines, got %d", len(lines))
+	}
+	if want := `{"id":"m1","amount":"1000000.000001","total_fees":"2500","completed_at":null}`; lines[0] != want {
+		t.Errorf("line = %s, want %s", lines[0], want)
+	}
+}
+
+func TestJournalBalances(t *testing.T) {
+	var out bytes.Buffer
+	journal := NewJournalWriter(&out, JournalOptions{})
+	if _, err := Export(context.Background(), testHistory(), Query{}, journal); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+
+	balances := map[string]decimal.Decimal{}
+	accounts := map[string]decimal.Decimal{}
+	for _, row := range rows[1:] {
+		if row[0] != "2025-02-01" {
+			t.Errorf("expected the WIB date 2025-02-01, got %s", row[0])
+		}
+		debit, credit := decimal.Zero, decimal.Zero
+		if row[4] != "" {
+			debit = decimal.RequireFromString(row[4])
+		}
+		if row[5] != "" {
+			credit = decimal.RequireFromString(row[5])
+		}
+		balances[row[1]] = balances[row[1]].Add(debit).Sub(credit)
+		accounts[row[3]] = accounts[row[3]].Add(debit).Sub(credit)
+	}
+
+	for id, balance := range balances {
+		if !balance.IsZero() {
+			t.Errorf("transaction %s does not balance: %s", id, balance)
+		}
+	}
+	if _, exists := balances["m2"]; exists {
+		t.Error("failed transaction was journaled")
+	}
+
+	// m1 and m3 mint 1000010.000001; b1 redeems 50000; each of m1, b1 and x1 pays a 2500 fee
+	want := map[string]string{
+		"Assets:IDRX":        "947510.000001",
+		"Assets:Bank":        "-955010.000001",
+		"Expenses:IDRX Fees": "7500",
+	}
+	for account, amount := range want {
+		if !accounts[account].Equal(decimal.RequireFromString(amount)) {
+			t.Errorf("%s = %s, want %s", account, accounts[account], amount)
+		}
+	}
+}
+
+func TestUnknownColumnRejected(t *testing.T) {
+	if _, err := NewCSVWriter(&bytes.Buffer{}, Options{Columns: []string{"id", "colour"}}); err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+}
+
+func TestInvalidAmountStopsExport(t *testing.T) {
+	history := &pagedHistory{transactions: map[models.TransactionType][]models.Transaction{
+		models.TransactionTypeMint: {{ID: "bad", Type: models.TransactionTypeMint, Amount: "1,000"}},
+	}}
+	writer, _ := NewJSONLWriter(&bytes.Buffer{}, Options{})
+	if _, err := Export(context.Background(), history, Query{}, writer); err == nil || !strings.Contains(err.Error(), "bad") {
+		t.Errorf("expected an error naming the transaction, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/models"
+)
+
+// JournalAccounts names the ledger accounts of journal entries
+type JournalAccounts struct {
+	Wallet string // IDRX held in the member's wallets (defaults to "Assets:IDRX")
+	Bank   string // The member's bank account (defaults to "Assets:Bank")
+	Fees   string // Fees charged by IDRX (defaults to "Expenses:IDRX Fees")
+}
+
+// JournalOptions configure a journal writer
+type JournalOptions struct {
+	Accounts   JournalAccounts
+	Location   *time.Location // Timezone of entry dates (defaults to WIB)
+	DateFormat string         // Layout of entry dates (defaults to "2006-01-02")
+}
+
+// journalHeader lists the journal columns
+var journalHeader = []string{"date", "transaction_id", "type", "account", "debit", "credit", "description"}
+
+// JournalWriter writes a double-entry journal as CSV, one line per debit or credit. Each
+// transaction's lines balance. Only completed transactions are journaled:
+//
+//   - MINT: debit Wallet, credit Bank for the amount
+//   - BURN and DEPOSIT_REDEEM: debit Bank, credit Wallet for the amount
+//   - BRIDGE: moves IDRX between the member's own wallets, so only its fees are journaled
+//
+// Every active fee line adds a debit to Fees. Mint fees are credited to Bank, as they are paid
+// with the fiat; redemption fees are credited to Bank, as they are withheld from the payout;
+// bridge fees are credited to Wallet, as they are cut from the bridged tokens.
+type JournalWriter struct {
+	writer        *csv.Writer
+	accounts      JournalAccounts
+	format        timeFormatter
+	headerWritten bool
+}
+
+// NewJournalWriter creates a journal writer
+func NewJournalWriter(w io.Writer, options JournalOptions) *JournalWriter {
+	accounts := options.Accounts
+	if accounts.Wallet == "" {
+		accounts.Wallet = "Assets:IDRX"
+	}
+	if accounts.Bank == "" {
+		accounts.Bank = "Assets:Bank"
+	}
+	if accounts.Fees == "" {
+		accounts.Fees = "Expenses:IDRX Fees"
+	}
+	layout := options.DateFormat
+	if layout == "" {
+		layout = time.DateOnly
+	}
+
+	return &JournalWriter{
+		writer:   csv.NewWriter(w),
+		accounts: accounts,
+		format:   newTimeFormatter(options.Location, layout),
+	}
+}
+
+// journalLine is one debit or credit
+type journalLine struct {
+	account     string
+	debit       decimal.Decimal
+	credit      decimal.Decimal
+	description string
+}
+
+// Write appends the journal lines of a completed transaction and skips any other
+func (j *JournalWriter) Write(transaction *models.Transaction) error {
+	if err := j.writeHeader(); err != nil {
+		return err
+	}
+	if transaction.Status != models.TransactionStatusCompleted {
+		return nil
+	}
+
+	lines, err := j.lines(transaction)
+	if err != nil {
+		return err
+	}
+
+	date := transaction.CreatedAt
+	if transaction.CompletedAt != nil {
+		date = *transaction.CompletedAt
+	}
+	for _, line := range lines {
+		err := j.writer.Write([]string{
+			j.format(date),
+			transaction.ID,
+			string(transaction.Type),
+			line.account,
+			amountText(line.debit),
+			amountText(line.credit),
+			line.description,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lines builds the balanced entries of a transaction
+func (j *JournalWriter) lines(transaction *models.Transaction) ([]journalLine, error) {
+	amount, err := parseAmount(transaction.Amount)
+	if err != nil {
+		return nil, err
+	}
+	fees, err := activeFees(transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []journalLine
+	feeSource := j.accounts.Bank
+	switch transaction.Type {
+	case models.TransactionTypeMint:
+		lines = append(lines,
+			journalLine{account: j.accounts.Wallet, debit: amount, description: "IDRX minted"},
+			journalLine{account: j.accounts.Bank, credit: amount, description: "Payment for mint"},
+		)
+	case models.TransactionTypeBurn, models.TransactionTypeDepositRedeem:
+		lines = append(lines,
+			journalLine{account: j.accounts.Bank, debit: amount, description: "Redemption payout"},
+			journalLine{account: j.accounts.Wallet, credit: amount, description: "IDRX redeemed"},
+		)
+	case models.TransactionTypeBridge:
+		feeSource = j.accounts.Wallet
+	default:
+		return nil, fmt.Errorf("no journal rule for transaction type %s", transaction.Type)
+	}
+
+	for _, fee := range fees {
+		lines = append(lines,
+			journalLine{account: j.accounts.Fees, debit: fee.Amount, description: fee.Name},
+			journalLine{account: feeSource, credit: fee.Amount, description: fee.Name},
+		)
+	}
+	return lines, nil
+}
+
+// Flush writes buffered lines, and the header if no line was written
+func (j *JournalWriter) Flush() error {
+	if err := j.writeHeader(); err != nil {
+		return err
+	}
+	j.writer.Flush()
+	return j.writer.Error()
+}
+
+func (j *JournalWriter) writeHeader() error {
+	if j.headerWritten {
+		return nil
+	}
+	j.headerWritten = true
+	return j.writer.Write(journalHeader)
+}
+
+// amountText leaves the unused side of a line empty
+func amountText(amount decimal.Decimal) string {
+	if amount.IsZero() {
+		return ""
+	}
+	return amount.String()
+}
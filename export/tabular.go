@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/models"
+)
+
+// CSVWriter writes one row per transaction with a header row first
+type CSVWriter struct {
+	writer        *csv.Writer
+	options       *resolved
+	headerWritten bool
+}
+
+// NewCSVWriter creates a CSV writer. Fee lists are written as "name=amount" pairs joined by ";".
+func NewCSVWriter(w io.Writer, options Options) (*CSVWriter, error) {
+	resolvedOptions, err := options.resolve()
+	if err != nil {
+		return nil, err
+	}
+	return &CSVWriter{writer: csv.NewWriter(w), options: resolvedOptions}, nil
+}
+
+// Write appends a transaction's row
+func (c *CSVWriter) Write(transaction *models.Transaction) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	row := make([]string, len(c.options.columns))
+	for i, value := range c.options.columns {
+		v, err := value(transaction, c.options.format)
+		if err != nil {
+			return err
+		}
+		row[i] = csvValue(v)
+	}
+	return c.writer.Write(row)
+}
+
+// Flush writes buffered rows, and the header if no row was written
+func (c *CSVWriter) Flush() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *CSVWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	return c.writer.Write(c.options.names)
+}
+
+// csvValue formats a column value as CSV text
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case decimal.Decimal:
+		return v.String()
+	case []Fee:
+		parts := make([]string, len(v))
+		for i, fee := range v {
+			parts[i] = fee.Name + "=" + fee.Amount.String()
+		}
+		return strings.Join(parts, ";")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// JSONLWriter writes one JSON object per line, with keys in column order. Amounts are JSON
+// strings so no precision is lost, and empty values are null.
+type JSONLWriter struct {
+	writer  *bufio.Writer
+	options *resolved
+	line    bytes.Buffer
+}
+
+// NewJSONLWriter creates a JSON Lines writer
+func NewJSONLWriter(w io.Writer, options Options) (*JSONLWriter, error) {
+	resolvedOptions, err := options.resolve()
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLWriter{writer: bufio.NewWriter(w), options: resolvedOptions}, nil
+}
+
+// Write appends a transaction's line
+func (j *JSONLWriter) Write(transaction *models.Transaction) error {
+	j.line.Reset()
+	j.line.WriteByte('{')
+	for i, value := range j.options.columns {
+		v, err := value(transaction, j.options.format)
+		if err != nil {
+			return err
+		}
+
+		key, _ := json.Marshal(j.options.names[i])
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			j.line.WriteByte(',')
+		}
+		j.line.Write(key)
+		j.line.WriteByte(':')
+		j.line.Write(encoded)
+	}
+	j.line.WriteString("}\n")
+
+	_, err := j.writer.Write(j.line.Bytes())
+	return err
+}
+
+// Flush writes buffered lines
+func (j *JSONLWriter) Flush() error {
+	return j.writer.Flush()
+}
@@ -0,0 +1,145 @@
+// Package backendtest holds the fake chain shared by the tests of packages built on
+// blockchain.Client: a devnet with a fixed chain ID, contract and wallet key, and a backend
+// that answers the calls every client makes when it connects.
+package backendtest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/contracts"
+)
+
+const (
+	// ChainID is the chain ID of the test devnet
+	ChainID = 31337
+	// Decimals is the token precision of the test devnet
+	Decimals = 2
+	// PrivateKey is the hex key of the test wallet
+	PrivateKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+)
+
+// Contract is the IDRX contract address on the test devnet
+var Contract = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+// Chain answers the chain ID, head and header calls made when a client connects. Test fakes
+// embed it and implement the calls their tests need; any other method panics if called.
+type Chain struct {
+	blockchain.Backend // unimplemented methods panic if called
+
+	Head    uint64
+	BaseFee *big.Int // Set on headers when non-nil, which makes the chain support EIP-1559
+}
+
+// ChainID implements blockchain.Backend
+func (c *Chain) ChainID(_ context.Context) (*big.Int, error) {
+	return big.NewInt(ChainID), nil
+}
+
+// BlockNumber implements blockchain.Backend
+func (c *Chain) BlockNumber(_ context.Context) (uint64, error) {
+	return c.Head, nil
+}
+
+// HeaderByNumber implements blockchain.Backend; a nil number is the head
+func (c *Chain) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil {
+		number = new(big.Int).SetUint64(c.Head)
+	}
+	return &types.Header{Number: number, BaseFee: c.BaseFee}, nil
+}
+
+// DecodeCall decodes the calldata of an IDRX contract call into its method and arguments
+func DecodeCall(data []byte) (*abi.Method, []interface{}, error) {
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("calldata too short: %d bytes", len(data))
+	}
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		return nil, nil, err
+	}
+	method, err := parsed.MethodById(data[:4])
+	if err != nil {
+		return nil, nil, err
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, nil, err
+	}
+	return method, args, nil
+}
+
+// FastBlocks shortens the devnet block time so confirmations are polled quickly
+func FastBlocks(config *blockchain.NetworkConfig) {
+	config.BlockTime = 20 * time.Millisecond
+}
+
+// Registry returns a registry holding only the test devnet, named Devnet; configure adjusts
+// the network before it is registered
+func Registry(t testing.TB, configure ...func(*blockchain.NetworkConfig)) *blockchain.Registry {
+	t.Helper()
+
+	config := blockchain.DevnetConfig(ChainID, "http://127.0.0.1:8545", Contract, Decimals)
+	for _, fn := range configure {
+		fn(config)
+	}
+	registry := blockchain.NewRegistry()
+	if err := registry.Register("Devnet", config); err != nil {
+		t.Fatalf("failed to register devnet: %v", err)
+	}
+	return registry
+}
+
+// NewClient creates a read-only client whose devnet is served by backend
+func NewClient(t testing.TB, backend blockchain.Backend, configure ...func(*blockchain.NetworkConfig)) *blockchain.Client {
+	t.Helper()
+	return newClient(t, "", backend, configure)
+}
+
+// NewSigningClient creates a client with the test wallet whose devnet is served by backend
+func NewSigningClient(t testing.TB, backend blockchain.Backend, configure ...func(*blockchain.NetworkConfig)) *blockchain.Client {
+	t.Helper()
+	return newClient(t, PrivateKey, backend, configure)
+}
+
+func newClient(t testing.TB, privateKey string, backend blockchain.Backend, configure []func(*blockchain.NetworkConfig)) *blockchain.Client {
+	t.Helper()
+
+	client, err := blockchain.NewClient(&blockchain.ClientConfig{
+		PrivateKeyHex: privateKey,
+		Registry:      Registry(t, configure...),
+		Backends:      map[string]blockchain.Backend{"Devnet": backend},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+// Key returns the test wallet's private key
+func Key(t testing.TB) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := crypto.HexToECDSA(PrivateKey)
+	if err != nil {
+		t.Fatalf("failed to parse test key: %v", err)
+	}
+	return key
+}
+
+// Amount returns a token amount in the devnet's precision
+func Amount(value string) *blockchain.TokenAmount {
+	return &blockchain.TokenAmount{Amount: decimal.RequireFromString(value), Decimals: Decimals}
+}
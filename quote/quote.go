@@ -0,0 +1,353 @@
+// Package quote prices mints, redemptions, refunds and bridges before they are submitted. It
+// combines the REST additional fees and rates with the on-chain bridge fees, using exact
+// decimal math and each chain's token decimals, and returns an itemised breakdown.
+package quote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/models"
+)
+
+// FeeSource provides the REST fees and rates; *idrx.TransactionService implements it
+type FeeSource interface {
+	GetAdditionalFees(ctx context.Context, req *models.FeesRequest) ([]models.Fee, error)
+	GetRates(ctx context.Context, req *models.RatesRequest) (*models.RatesResponse, error)
+}
+
+// ChainSource provides the on-chain fee configuration; *blockchain.Client implements it
+type ChainSource interface {
+	GetPlatformFeeInfo(ctx context.Context, chainID uint64) (*blockchain.PlatformFeeInfo, error)
+	GetMaxPlatformFee(ctx context.Context, chainID uint64) (uint64, error)
+	Decimals(chainID uint64) uint8
+}
+
+// Kind is the operation a quote prices
+type Kind string
+
+const (
+	KindMint   Kind = "mint"
+	KindRedeem Kind = "redeem"
+	KindRefund Kind = "refund"
+	KindBridge Kind = "bridge"
+)
+
+// Source is where a fee item comes from
+type Source string
+
+const (
+	SourceAPI      Source = "api"      // An additional fee from the REST API
+	SourceChain    Source = "chain"    // A bridge fee taken by the contract
+	SourceRounding Source = "rounding" // Precision lost to a chain's token decimals
+)
+
+// ErrFeesExceedAmount is returned when the fees leave nothing of the amount
+var ErrFeesExceedAmount = errors.New("fees exceed the amount")
+
+// Item is one line of a quote's fee breakdown
+type Item struct {
+	Name        string          `json:"name"`
+	Source      Source          `json:"source"`
+	ChainID     uint64          `json:"chainId"`
+	BasisPoints uint64          `json:"basisPoints,omitempty"` // Set for bridge fees
+	Amount      decimal.Decimal `json:"amount"`
+}
+
+// Rate is the USDT value of a quote's net amount
+type Rate struct {
+	Price      decimal.Decimal `json:"price"`
+	USDTAmount decimal.Decimal `json:"usdtAmount"`
+}
+
+// Quote is an itemised price. Gross is what the customer puts in and Net what they receive;
+// Gross always equals Net plus TotalFees.
+//
+//   - Mint: Gross is the rupiah paid (toBeMinted) and Net the IDRX minted, which is what
+//     MintResponse.AdjustedAmount reports
+//   - Redeem: Gross is the IDRX burned and Net the rupiah paid out
+//   - Refund: Gross is the amount of a failed redemption and Net the IDRX minted back
+//   - Bridge: Gross is the IDRX burned on ChainID and Net the IDRX minted on ToChainID
+type Quote struct {
+	Kind      Kind            `json:"kind"`
+	ChainID   uint64          `json:"chainId"`
+	ToChainID uint64          `json:"toChainId,omitempty"`
+	Gross     decimal.Decimal `json:"gross"`
+	Fees      []Item          `json:"fees"`
+	TotalFees decimal.Decimal `json:"totalFees"`
+	Net       decimal.Decimal `json:"net"`
+	Rate      *Rate           `json:"rate,omitempty"` // Set when Config.IncludeRates is true
+}
+
+// CheckMint compares a mint quote with the adjusted amount of the mint request it priced
+func (q *Quote) CheckMint(response *models.MintResponse) error {
+	if q.Kind != KindMint {
+		return fmt.Errorf("quote is for a %s, not a mint", q.Kind)
+	}
+	adjusted, err := decimal.NewFromString(response.AdjustedAmount)
+	if err != nil {
+		return fmt.Errorf("invalid adjusted amount %q", response.AdjustedAmount)
+	}
+	if !adjusted.Equal(q.Net) {
+		return fmt.Errorf("adjusted amount %s differs from the quoted %s", adjusted, q.Net)
+	}
+	return nil
+}
+
+// Config configures an Engine
+type Config struct {
+	Fees         FeeSource   // Required
+	Chain        ChainSource // Required for bridge quotes; token decimals default to the built-in networks
+	IncludeRates bool        // Look up the USDT value of each quote's net amount
+}
+
+// Engine computes quotes from the current fees
+type Engine struct {
+	config Config
+}
+
+// NewEngine creates a quote engine
+func NewEngine(config Config) (*Engine, error) {
+	if config.Fees == nil {
+		return nil, fmt.Errorf("fee source is required")
+	}
+	return &Engine{config: config}, nil
+}
+
+// Mint quotes paying amount rupiah for IDRX minted on chainID. MINT fees are deducted from the
+// payment and the rest is minted, rounded down to the chain's decimals.
+func (e *Engine) Mint(ctx context.Context, chainID uint64, amount string) (*Quote, error) {
+	gross, err := parsePositive(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Quote{Kind: KindMint, ChainID: chainID, Gross: gross}
+	if err := e.addAPIFees(ctx, q, models.FeeTypeMint, chainID); err != nil {
+		return nil, err
+	}
+	if err := q.settle(chainID, int32(e.decimals(chainID))); err != nil {
+		return nil, err
+	}
+	return e.finish(ctx, q, chainID)
+}
+
+// Redeem quotes burning amount IDRX on chainID for a bank payout. REDEEM fees are withheld from
+// the payout.
+func (e *Engine) Redeem(ctx context.Context, chainID uint64, amount string) (*Quote, error) {
+	gross, err := e.parseTokens(amount, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Quote{Kind: KindRedeem, ChainID: chainID, Gross: gross}
+	if err := e.addAPIFees(ctx, q, models.FeeTypeRedeem, chainID); err != nil {
+		return nil, err
+	}
+	if err := q.settle(chainID, -1); err != nil {
+		return nil, err
+	}
+	return e.finish(ctx, q, chainID)
+}
+
+// Refund quotes minting a failed redemption of amount IDRX back on chainID. REFUND fees are
+// deducted and the rest is minted, rounded down to the chain's decimals.
+func (e *Engine) Refund(ctx context.Context, chainID uint64, amount string) (*Quote, error) {
+	gross, err := e.parseTokens(amount, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Quote{Kind: KindRefund, ChainID: chainID, Gross: gross}
+	if err := e.addAPIFees(ctx, q, models.FeeTypeRefund, chainID); err != nil {
+		return nil, err
+	}
+	if err := q.settle(chainID, int32(e.decimals(chainID))); err != nil {
+		return nil, err
+	}
+	return e.finish(ctx, q, chainID)
+}
+
+// Bridge quotes burning amount IDRX on fromChainID and minting it on toChainID. The burn bridge
+// fee of the source chain is cut from the amount, the rest is minted on the destination, and
+// the destination's mint bridge fee is cut from that. Both cuts round the fee down in the
+// chain's smallest unit, as the contract does, and both fees must not exceed the chain's
+// MaxPlatformFee.
+func (e *Engine) Bridge(ctx context.Context, fromChainID, toChainID uint64, amount string) (*Quote, error) {
+	if e.config.Chain == nil {
+		return nil, fmt.Errorf("a chain source is required for bridge quotes")
+	}
+	gross, err := e.parseTokens(amount, fromChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	burnFee, err := e.bridgeFee(ctx, fromChainID, func(info *blockchain.PlatformFeeInfo) uint64 { return info.BurnBridgeFee })
+	if err != nil {
+		return nil, err
+	}
+	mintFee, err := e.bridgeFee(ctx, toChainID, func(info *blockchain.PlatformFeeInfo) uint64 { return info.MintBridgeFee })
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Quote{Kind: KindBridge, ChainID: fromChainID, ToChainID: toChainID, Gross: gross}
+	burned := q.cut("Burn bridge fee", fromChainID, int32(e.decimals(fromChainID)), burnFee, gross)
+
+	// Carry the burned amount over to the destination's decimals
+	toDecimals := int32(e.decimals(toChainID))
+	arrived := burned.Truncate(toDecimals)
+	q.add(Item{Name: "Rounding", Source: SourceRounding, ChainID: toChainID, Amount: burned.Sub(arrived)})
+
+	q.Net = q.cut("Mint bridge fee", toChainID, toDecimals, mintFee, arrived)
+	if !q.Net.IsPositive() {
+		return nil, fmt.Errorf("%w: %s bridged from chain %d", ErrFeesExceedAmount, gross, fromChainID)
+	}
+	return e.finish(ctx, q, toChainID)
+}
+
+// bridgeFee reads one bridge fee of a chain and checks it against the chain's maximum
+func (e *Engine) bridgeFee(ctx context.Context, chainID uint64, fee func(*blockchain.PlatformFeeInfo) uint64) (uint64, error) {
+	info, err := e.config.Chain.GetPlatformFeeInfo(ctx, chainID)
+	if err != nil {
+		return 0, fmt.Errorf("chain %d: %w", chainID, err)
+	}
+	maxFee, err := e.config.Chain.GetMaxPlatformFee(ctx, chainID)
+	if err != nil {
+		return 0, fmt.Errorf("chain %d: %w", chainID, err)
+	}
+
+	basisPoints := fee(info)
+	if basisPoints > maxFee {
+		return 0, fmt.Errorf("bridge fee of %d basis points on chain %d exceeds the maximum of %d", basisPoints, chainID, maxFee)
+	}
+	return basisPoints, nil
+}
+
+// cut applies a bridge fee the way the contract does and returns the amount after the cut
+func (q *Quote) cut(name string, chainID uint64, decimals int32, basisPoints uint64, amount decimal.Decimal) decimal.Decimal {
+	units := &blockchain.TokenAmount{Amount: amount, Decimals: decimals}
+
+	fee, afterCut := blockchain.BridgeCut(units.ToWei(), basisPoints)
+	q.add(Item{
+		Name:        name,
+		Source:      SourceChain,
+		ChainID:     chainID,
+		BasisPoints: basisPoints,
+		Amount:      blockchain.FromWei(fee, decimals).Amount,
+	})
+	return blockchain.FromWei(afterCut, decimals).Amount
+}
+
+// addAPIFees adds the active additional fees of a type
+func (e *Engine) addAPIFees(ctx context.Context, q *Quote, feeType models.FeeType, chainID uint64) error {
+	fees, err := e.config.Fees.GetAdditionalFees(ctx, models.NewFeesRequest(feeType, strconv.FormatUint(chainID, 10)))
+	if err != nil {
+		return err
+	}
+
+	for _, fee := range fees {
+		if !fee.IsActive {
+			continue
+		}
+		amount, err := decimal.NewFromString(fee.Amount)
+		if err != nil {
+			return fmt.Errorf("fee %s: invalid amount %q", fee.Name, fee.Amount)
+		}
+		q.add(Item{Name: fee.Name, Source: SourceAPI, ChainID: chainID, Amount: amount})
+	}
+	return nil
+}
+
+// settle deducts the fees from the gross amount. When the net amount is minted, decimals is the
+// chain's token decimals and any precision beyond them becomes a rounding item; otherwise it is
+// negative.
+func (q *Quote) settle(chainID uint64, decimals int32) error {
+	net := q.Gross.Sub(q.TotalFees)
+	if !net.IsPositive() {
+		return fmt.Errorf("%w: fees of %s on %s", ErrFeesExceedAmount, q.TotalFees, q.Gross)
+	}
+	if decimals >= 0 {
+		rounded := net.Truncate(decimals)
+		q.add(Item{Name: "Rounding", Source: SourceRounding, ChainID: chainID, Amount: net.Sub(rounded)})
+		net = rounded
+		if !net.IsPositive() {
+			return fmt.Errorf("%w: %s rounds to nothing on chain %d", ErrFeesExceedAmount, q.Gross, chainID)
+		}
+	}
+	q.Net = net
+	return nil
+}
+
+// add appends a fee item; zero rounding items are left out
+func (q *Quote) add(item Item) {
+	if item.Source == SourceRounding && item.Amount.IsZero() {
+		return
+	}
+	q.Fees = append(q.Fees, item)
+	q.TotalFees = q.TotalFees.Add(item.Amount)
+}
+
+// finish looks up the rate of the net amount if configured
+func (e *Engine) finish(ctx context.Context, q *Quote, chainID uint64) (*Quote, error) {
+	if q.Fees == nil {
+		q.Fees = []Item{}
+	}
+	if !e.config.IncludeRates {
+		return q, nil
+	}
+
+	net := q.Net.String()
+	chain := strconv.FormatUint(chainID, 10)
+	rates, err := e.config.Fees.GetRates(ctx, &models.RatesRequest{IDRXAmount: &net, ChainID: &chain})
+	if err != nil {
+		return nil, err
+	}
+	price, err := decimal.NewFromString(rates.Price)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate price %q", rates.Price)
+	}
+	usdt, err := decimal.NewFromString(rates.BuyAmount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate buy amount %q", rates.BuyAmount)
+	}
+	q.Rate = &Rate{Price: price, USDTAmount: usdt}
+	return q, nil
+}
+
+// parseTokens parses an IDRX amount that must be representable on a chain
+func (e *Engine) parseTokens(amount string, chainID uint64) (decimal.Decimal, error) {
+	value, err := parsePositive(amount)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	decimals := e.decimals(chainID)
+	if !value.Equal(value.Truncate(int32(decimals))) {
+		return decimal.Decimal{}, fmt.Errorf("amount %s has more than %d decimals on chain %d", value, decimals, chainID)
+	}
+	return value, nil
+}
+
+// decimals returns a chain's token decimals
+func (e *Engine) decimals(chainID uint64) uint8 {
+	if e.config.Chain != nil {
+		return e.config.Chain.Decimals(chainID)
+	}
+	return blockchain.GetDecimals(chainID)
+}
+
+func parsePositive(amount string) (decimal.Decimal, error) {
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("invalid amount %q", amount)
+	}
+	if !value.IsPositive() {
+		return decimal.Decimal{}, fmt.Errorf("amount must be positive, got %s", value)
+	}
+	return value, nil
+}
@@ -0,0 +1,207 @@
+package quote
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/models"
+)
+
+// fees serves fixed additional fees per fee type and records the requests
+type fees struct {
+	byType   map[models.FeeType][]models.Fee
+	requests []models.FeesRequest
+	rates    []models.RatesRequest
+}
+
+func (f *fees) GetAdditionalFees(_ context.Context, req *models.FeesRequest) ([]models.Fee, error) {
+	f.requests = append(f.requests, *req)
+	return f.byType[req.FeeType], nil
+}
+
+func (f *fees) GetRates(_ context.Context, req *models.RatesRequest) (*models.RatesResponse, error) {
+	f.rates = append(f.rates, *req)
+	return &models.RatesResponse{Price: "0.0000625", BuyAmount: "6.09375"}, nil
+}
+
+// chain serves fixed bridge fees and decimals per chain
+type chain struct {
+	decimals map[uint64]uint8
+	burnFee  map[uint64]uint64
+	mintFee  map[uint64]uint64
+	maxFee   uint64
+}
+
+func (c *chain) GetPlatformFeeInfo(_ context.Context, chainID uint64) (*blockchain.PlatformFeeInfo, error) {
+	return &blockchain.PlatformFeeInfo{BurnBridgeFee: c.burnFee[chainID], MintBridgeFee: c.mintFee[chainID]}, nil
+}
+
+func (c *chain) GetMaxPlatformFee(_ context.Context, _ uint64) (uint64, error) {
+	return c.maxFee, nil
+}
+
+func (c *chain) Decimals(chainID uint64) uint8 {
+	return c.decimals[chainID]
+}
+
+func testFees() *fees {
+	return &fees{byType: map[models.FeeType][]models.Fee{
+		models.FeeTypeMint: {
+			{Name: "Platform fee", Amount: "2500", IsActive: true},
+			{Name: "Retired fee", Amount: "9999", IsActive: false},
+		},
+		models.FeeTypeRedeem: {{Name: "Transfer fee", Amount: "5000", IsActive: true}},
+		models.FeeTypeRefund: {{Name: "Refund fee", Amount: "0.5", IsActive: true}},
+	}}
+}
+
+func testChain() *chain {
+	return &chain{
+		decimals: map[uint64]uint8{1: 2, 2: 0},
+		burnFee:  map[uint64]uint64{1: 50},
+		mintFee:  map[uint64]uint64{2: 30},
+		maxFee:   100,
+	}
+}
+
+func assertBalanced(t *testing.T, q *Quote) {
+	t.Helper()
+	sum := decimal.Zero
+	for _, item := range q.Fees {
+		sum = sum.Add(item.Amount)
+	}
+	if !sum.Equal(q.TotalFees) || !q.Net.Add(q.TotalFees).Equal(q.Gross) {
+		t.Errorf("quote does not balance: gross %s, fees %s (items %s), net %s", q.Gross, q.TotalFees, sum, q.Net)
+	}
+}
+
+func TestMintQuoteMatchesAdjustedAmount(t *testing.T) {
+	source := testFees()
+	engine, err := NewEngine(Config{Fees: source})
+	if err != nil {
+		t.Fatalf("NewEngine: %v", err)
+	}
+
+	// Polygon has 2 decimals, so the half-cent cannot be minted
+	q, err := engine.Mint(context.Background(), blockchain.PolygonChainID, "100000.005")
+	if err != nil {
+		t.Fatalf("Mint: %v", err)
+	}
+	assertBalanced(t, q)
+
+	if !q.Net.Equal(decimal.NewFromInt(97500)) || len(q.Fees) != 2 {
+		t.Fatalf("unexpected quote: %+v", q)
+	}
+	if q.Fees[0].Source != SourceAPI || q.Fees[1].Source != SourceRounding || q.Fees[1].Amount.String() != "0.005" {
+		t.Errorf("unexpected breakdown: %+v", q.Fees)
+	}
+	if req := source.requests[0]; req.FeeType != models.FeeTypeMint || req.ChainID == nil || *req.ChainID != "137" {
+		t.Errorf("unexpected fees request: %+v", req)
+	}
+
+	if err := q.CheckMint(&models.MintResponse{AdjustedAmount: "97500.00"}); err != nil {
+		t.Errorf("expected the adjusted amount to match: %v", err)
+	}
+	if err := q.CheckMint(&models.MintResponse{AdjustedAmount: "97500.01"}); err == nil {
+		t.Error("expected a differing adjusted amount to be reported")
+	}
+}
+
+func TestRedeemAndRefundQuotes(t *testing.T) {
+	engine, _ := NewEngine(Config{Fees: testFees(), Chain: testChain()})
+
+	redeem, err := engine.Redeem(context.Background(), 1, "50000.25")
+	if err != nil {
+		t.Fatalf("Redeem: %v", err)
+	}
+	assertBalanced(t, redeem)
+	if redeem.Net.String() != "45000.25" {
+		t.Errorf("expected a payout of 45000.25, got %s", redeem.Net)
+	}
+
+	// Chain 2 has no decimals: the amount must be whole, and the refund rounds down
+	if _, err := engine.Redeem(context.Background(), 2, "10.5"); err == nil || !strings.Contains(err.Error(), "more than 0 decimals") {
+		t.Errorf("expected a precision error, got %v", err)
+	}
+	refund, err := engine.Refund(context.Background(), 2, "100")
+	if err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	assertBalanced(t, refund)
+	if refund.Net.String() != "99" || len(refund.Fees) != 2 {
+		t.Errorf("unexpected refund: %+v", refund)
+	}
+
+	if _, err := engine.Redeem(context.Background(), 1, "5000"); !errors.Is(err, ErrFeesExceedAmount) {
+		t.Errorf("expected ErrFeesExceedAmount, got %v", err)
+	}
+}
+
+func TestBridgeQuoteFollowsContractRounding(t *testing.T) {
+	engine, _ := NewEngine(Config{Fees: testFees(), Chain: testChain()})
+
+	q, err := engine.Bridge(context.Background(), 1, 2, "12345.67")
+	if err != nil {
+		t.Fatalf("Bridge: %v", err)
+	}
+	assertBalanced(t, q)
+
+	// 1234567 units * 50 / 10000 = 6172 units burned as fee; 12283.95 is carried to a chain
+	// without decimals; 12283 * 30 / 10000 = 36 taken by the mint
+	want := []struct {
+		source Source
+		amount string
+	}{
+		{SourceChain, "61.72"},
+		{SourceRounding, "0.95"},
+		{SourceChain, "36"},
+	}
+	if len(q.Fees) != len(want) {
+		t.Fatalf("unexpected breakdown: %+v", q.Fees)
+	}
+	for i, w := range want {
+		if q.Fees[i].Source != w.source || q.Fees[i].Amount.String() != w.amount {
+			t.Errorf("item %d = %+v, want %s %s", i, q.Fees[i], w.source, w.amount)
+		}
+	}
+	if q.Fees[0].BasisPoints != 50 || q.Fees[2].ChainID != 2 || q.Net.String() != "12247" {
+		t.Errorf("unexpected quote: %+v", q)
+	}
+}
+
+func TestBridgeFeeAboveMaximum(t *testing.T) {
+	source := testChain()
+	source.mintFee[2] = 150
+	engine, _ := NewEngine(Config{Fees: testFees(), Chain: source})
+
+	_, err := engine.Bridge(context.Background(), 1, 2, "1000")
+	if err == nil || !strings.Contains(err.Error(), "exceeds the maximum of 100") {
+		t.Errorf("expected the fee to be checked against the maximum, got %v", err)
+	}
+
+	withoutChain, _ := NewEngine(Config{Fees: testFees()})
+	if _, err := withoutChain.Bridge(context.Background(), 1, 2, "1000"); err == nil {
+		t.Error("expected an error without a chain source")
+	}
+}
+
+func TestQuoteIncludesRate(t *testing.T) {
+	source := testFees()
+	engine, _ := NewEngine(Config{Fees: source, Chain: testChain(), IncludeRates: true})
+
+	q, err := engine.Redeem(context.Background(), 1, "102500")
+	if err != nil {
+		t.Fatalf("Redeem: %v", err)
+	}
+	if q.Rate == nil || q.Rate.USDTAmount.String() != "6.09375" {
+		t.Fatalf("unexpected rate: %+v", q.Rate)
+	}
+	if req := source.rates[0]; *req.IDRXAmount != "97500" || *req.ChainID != "1" || req.USDTAmount != nil {
+		t.Errorf("unexpected rates request: %+v", req)
+	}
+}
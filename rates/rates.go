@@ -0,0 +1,334 @@
+// Package rates caches swap rate quotes in front of the REST API. Quotes are cached per chain
+// and amount bucket, refreshed in the background, and concurrent identical requests share a
+// single API call. Subscriptions emit a quote whenever the price moves beyond a threshold.
+package rates
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/models"
+)
+
+// Source provides swap rates; *idrx.TransactionService implements it
+type Source interface {
+	GetRates(ctx context.Context, req *models.RatesRequest) (*models.RatesResponse, error)
+}
+
+// Config configures a Provider
+type Config struct {
+	TTL             time.Duration     // How long a quote is served from the cache (defaults to 30 seconds)
+	RefreshInterval time.Duration     // How often Run refreshes cached quotes (defaults to half the TTL)
+	IdleTimeout     time.Duration     // Run drops quotes not requested for this long unless subscribed (defaults to 10 minutes)
+	Timeout         time.Duration     // Limit for one API call, which may be shared by several requests (defaults to 10 seconds)
+	Buckets         []decimal.Decimal // Ascending upper bounds of the amount buckets (defaults to powers of ten)
+	OnError         func(error)       // Called with background refresh errors
+}
+
+// key identifies a cached quote
+type key struct {
+	chainID  string
+	currency string // "IDRX" or "USDT", the side the amount is given in
+	bucket   int
+}
+
+// entry is a cached quote for the first amount requested in its bucket
+type entry struct {
+	request  models.RatesRequest
+	amount   decimal.Decimal
+	response models.RatesResponse
+	fetched  time.Time
+	used     time.Time
+}
+
+// call is an API request in flight, shared by identical requests
+type call struct {
+	done     chan struct{}
+	response *models.RatesResponse
+	err      error
+}
+
+// Provider serves rates from a cache. It implements the GetRates method of
+// *idrx.TransactionService, so it can stand in for it.
+type Provider struct {
+	source Source
+	config Config
+	now    func() time.Time
+
+	mu            sync.Mutex
+	entries       map[key]*entry
+	inflight      map[key]*call
+	subscriptions map[*Subscription]struct{}
+}
+
+// NewProvider creates a rate provider. Call Run to refresh quotes in the background.
+func NewProvider(source Source, config Config) (*Provider, error) {
+	if source == nil {
+		return nil, fmt.Errorf("rate source is required")
+	}
+	if config.TTL <= 0 {
+		config.TTL = 30 * time.Second
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = config.TTL / 2
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = 10 * time.Minute
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	for i := 1; i < len(config.Buckets); i++ {
+		if !config.Buckets[i].GreaterThan(config.Buckets[i-1]) {
+			return nil, fmt.Errorf("buckets must be ascending")
+		}
+	}
+
+	return &Provider{
+		source:        source,
+		config:        config,
+		now:           time.Now,
+		entries:       make(map[key]*entry),
+		inflight:      make(map[key]*call),
+		subscriptions: make(map[*Subscription]struct{}),
+	}, nil
+}
+
+// GetRates returns the rate for the request. A quote cached for the same chain and amount
+// bucket is served while it is younger than the TTL, with BuyAmount scaled to the requested
+// amount at the precision the API returned; otherwise the API is called.
+func (p *Provider) GetRates(ctx context.Context, req *models.RatesRequest) (*models.RatesResponse, error) {
+	k, amount, err := p.parse(req)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	cached, exists := p.entries[k]
+	if exists {
+		cached.used = p.now()
+		if p.now().Sub(cached.fetched) < p.config.TTL {
+			response := cached.scaled(amount)
+			p.mu.Unlock()
+			return response, nil
+		}
+	}
+	p.mu.Unlock()
+
+	fetched, err := p.fetch(ctx, k, req, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return fetched.scaled(amount), nil
+}
+
+// fetch calls the API for a bucket, sharing the call with concurrent requests for it, and
+// caches the result. The call is not tied to the context of the request that started it, so
+// cancelling one request does not fail the others; it is bounded by Timeout instead.
+func (p *Provider) fetch(ctx context.Context, k key, req *models.RatesRequest, amount decimal.Decimal) (*entry, error) {
+	p.mu.Lock()
+	c, inflight := p.inflight[k]
+	if !inflight {
+		c = &call{done: make(chan struct{})}
+		p.inflight[k] = c
+	}
+	p.mu.Unlock()
+
+	if !inflight {
+		request := copyRequest(req)
+		go func() {
+			callCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.config.Timeout)
+			defer cancel()
+
+			c.response, c.err = p.source.GetRates(callCtx, &request)
+			if c.err == nil {
+				p.store(k, &request, amount, c.response)
+			}
+
+			p.mu.Lock()
+			delete(p.inflight, k)
+			p.mu.Unlock()
+			close(c.done)
+		}()
+	}
+
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if c.err != nil {
+		return nil, fmt.Errorf("failed to get rates: %w", c.err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cached, exists := p.entries[k]; exists {
+		return cached, nil
+	}
+	// The entry was dropped since; answer from the response itself
+	return &entry{request: *req, amount: amount, response: *c.response}, nil
+}
+
+// store caches a fresh quote and notifies subscribers of its bucket. The request, amount and
+// response are replaced together, since the response is only valid for the amount it priced.
+func (p *Provider) store(k key, req *models.RatesRequest, amount decimal.Decimal, response *models.RatesResponse) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	cached, exists := p.entries[k]
+	if !exists {
+		cached = &entry{used: now}
+		p.entries[k] = cached
+	}
+	cached.request = copyRequest(req)
+	cached.amount = amount
+	cached.response = *response
+	cached.fetched = now
+
+	for sub := range p.subscriptions {
+		if sub.key == k {
+			sub.offer(cached.scaled(sub.amount))
+		}
+	}
+}
+
+// Run refreshes cached quotes every RefreshInterval until the context is cancelled, so
+// requests keep being served from the cache and subscriptions receive price moves. Quotes idle
+// for longer than IdleTimeout are dropped instead, unless they are subscribed.
+func (p *Provider) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			p.Refresh(ctx)
+		}
+	}
+}
+
+// Refresh refetches every cached quote in use once, concurrently
+func (p *Provider) Refresh(ctx context.Context) {
+	p.mu.Lock()
+	subscribed := make(map[key]bool)
+	for sub := range p.subscriptions {
+		subscribed[sub.key] = true
+	}
+	type refresh struct {
+		key     key
+		request models.RatesRequest
+		amount  decimal.Decimal
+	}
+	var due []refresh
+	for k, cached := range p.entries {
+		if !subscribed[k] && p.now().Sub(cached.used) > p.config.IdleTimeout {
+			delete(p.entries, k)
+			continue
+		}
+		due = append(due, refresh{key: k, request: cached.request, amount: cached.amount})
+	}
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, r := range due {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.fetch(ctx, r.key, &r.request, r.amount); err != nil && p.config.OnError != nil {
+				p.config.OnError(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// parse validates a request and returns its cache key and amount
+func (p *Provider) parse(req *models.RatesRequest) (key, decimal.Decimal, error) {
+	if req == nil {
+		return key{}, decimal.Decimal{}, fmt.Errorf("rates request cannot be nil")
+	}
+
+	hasIDRXAmount := req.IDRXAmount != nil && *req.IDRXAmount != ""
+	hasUSDTAmount := req.USDTAmount != nil && *req.USDTAmount != ""
+	if hasIDRXAmount == hasUSDTAmount {
+		return key{}, decimal.Decimal{}, fmt.Errorf("exactly one of idrxAmount or usdtAmount must be provided")
+	}
+
+	k := key{currency: "IDRX"}
+	text := req.IDRXAmount
+	if hasUSDTAmount {
+		k.currency, text = "USDT", req.USDTAmount
+	}
+	if req.ChainID != nil {
+		k.chainID = *req.ChainID
+	}
+
+	amount, err := decimal.NewFromString(*text)
+	if err != nil || !amount.IsPositive() {
+		return key{}, decimal.Decimal{}, fmt.Errorf("invalid %s amount %q", k.currency, *text)
+	}
+	k.bucket = p.bucket(amount)
+	return k, amount, nil
+}
+
+// bucket returns the amount bucket: the index of the first configured bound not below the
+// amount, or by default the number of integer digits
+func (p *Provider) bucket(amount decimal.Decimal) int {
+	if len(p.config.Buckets) == 0 {
+		return amount.NumDigits() + int(amount.Exponent())
+	}
+	for i, bound := range p.config.Buckets {
+		if amount.LessThanOrEqual(bound) {
+			return i
+		}
+	}
+	return len(p.config.Buckets)
+}
+
+// scaled returns the cached quote for another amount in the same bucket
+func (e *entry) scaled(amount decimal.Decimal) *models.RatesResponse {
+	response := e.response
+	if amount.Equal(e.amount) {
+		return &response
+	}
+
+	buyAmount, err := decimal.NewFromString(response.BuyAmount)
+	if err != nil {
+		return &response
+	}
+	places := -buyAmount.Exponent()
+	if places < 0 {
+		places = 0
+	}
+	response.BuyAmount = buyAmount.Mul(amount).DivRound(e.amount, places).StringFixed(places)
+	return &response
+}
+
+// copyRequest returns a deep copy of req, so a cached request does not alias the caller's
+func copyRequest(req *models.RatesRequest) models.RatesRequest {
+	copied := models.RatesRequest{}
+	if req.IDRXAmount != nil {
+		amount := *req.IDRXAmount
+		copied.IDRXAmount = &amount
+	}
+	if req.USDTAmount != nil {
+		amount := *req.USDTAmount
+		copied.USDTAmount = &amount
+	}
+	if req.ChainID != nil {
+		chainID := *req.ChainID
+		copied.ChainID = &chainID
+	}
+	return copied
+}
@@ -0,0 +1,285 @@
+package rates
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/models"
+)
+
+// source quotes IDRX at a settable USDT price and counts its calls
+type source struct {
+	mu      sync.Mutex
+	price   decimal.Decimal
+	calls   int
+	release chan struct{} // When set, calls block until it is closed
+}
+
+func (s *source) GetRates(_ context.Context, req *models.RatesRequest) (*models.RatesResponse, error) {
+	if s.release != nil {
+		<-s.release
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+
+	amount := decimal.RequireFromString(*req.IDRXAmount)
+	return &models.RatesResponse{
+		Price:     s.price.String(),
+		BuyAmount: amount.Mul(s.price).StringFixed(6),
+		FromToken: "IDRX",
+		ToToken:   "USDT",
+	}, nil
+}
+
+func (s *source) setPrice(price string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.price = decimal.RequireFromString(price)
+}
+
+func (s *source) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func idrx(amount string, chainID string) *models.RatesRequest {
+	return &models.RatesRequest{IDRXAmount: &amount, ChainID: &chainID}
+}
+
+// newTestProvider returns a provider whose clock is advanced by hand
+func newTestProvider(t *testing.T, src *source, config Config) (*Provider, *time.Time) {
+	t.Helper()
+	provider, err := NewProvider(src, config)
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	provider.now = func() time.Time { return now }
+	return provider, &now
+}
+
+func TestProviderCachesPerBucketAndChain(t *testing.T) {
+	src := &source{price: decimal.RequireFromString("0.0000625")}
+	provider, now := newTestProvider(t, src, Config{TTL: time.Minute})
+	ctx := context.Background()
+
+	if _, err := provider.GetRates(ctx, idrx("100000", "137")); err != nil {
+		t.Fatalf("GetRates: %v", err)
+	}
+	// Same bucket (six integer digits): served from the cache, scaled to the amount
+	response, err := provider.GetRates(ctx, idrx("150000", "137"))
+	if err != nil {
+		t.Fatalf("GetRates: %v", err)
+	}
+	if src.callCount() != 1 || response.BuyAmount != "9.375000" || response.Price != "0.0000625" {
+		t.Errorf("expected a scaled cached quote after 1 call, got %+v after %d calls", response, src.callCount())
+	}
+
+	_, _ = provider.GetRates(ctx, idrx("1000000", "137"))
+	_, _ = provider.GetRates(ctx, idrx("100000", "8453"))
+	if src.callCount() != 3 {
+		t.Errorf("expected other buckets and chains to call the API, got %d calls", src.callCount())
+	}
+
+	*now = now.Add(time.Minute)
+	_, _ = provider.GetRates(ctx, idrx("100000", "137"))
+	if src.callCount() != 4 {
+		t.Errorf("expected an expired quote to be refetched, got %d calls", src.callCount())
+	}
+
+	if _, err := provider.GetRates(ctx, &models.RatesRequest{}); err == nil {
+		t.Error("expected an error for a request without an amount")
+	}
+}
+
+func TestProviderRefetchPricesTheNewAmount(t *testing.T) {
+	src := &source{price: decimal.RequireFromString("0.0000625")}
+	provider, now := newTestProvider(t, src, Config{TTL: time.Minute})
+	ctx := context.Background()
+
+	if _, err := provider.GetRates(ctx, idrx("100000", "137")); err != nil {
+		t.Fatalf("GetRates: %v", err)
+	}
+
+	// The stale entry is refetched for another amount in the same bucket
+	*now = now.Add(time.Minute)
+	response, err := provider.GetRates(ctx, idrx("500000", "137"))
+	if err != nil {
+		t.Fatalf("GetRates: %v", err)
+	}
+	if response.BuyAmount != "31.250000" {
+		t.Errorf("expected the refetched quote to price 500000 IDRX at 31.250000, got %s", response.BuyAmount)
+	}
+
+	// The refreshed entry scales from the amount it was fetched for
+	response, err = provider.GetRates(ctx, idrx("100000", "137"))
+	if err != nil {
+		t.Fatalf("GetRates: %v", err)
+	}
+	if src.callCount() != 2 || response.BuyAmount != "6.250000" {
+		t.Errorf("expected a cached 6.250000 after 2 calls, got %s after %d calls", response.BuyAmount, src.callCount())
+	}
+}
+
+func TestProviderDeduplicatesConcurrentRequests(t *testing.T) {
+	src := &source{price: decimal.RequireFromString("0.0000625"), release: make(chan struct{})}
+	provider, _ := newTestProvider(t, src, Config{})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := provider.GetRates(context.Background(), idrx("500000", "137"))
+			errs <- err
+		}()
+	}
+
+	// Let every request reach the provider before the API answers
+	time.Sleep(50 * time.Millisecond)
+	close(src.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("GetRates: %v", err)
+		}
+	}
+	if src.callCount() != 1 {
+		t.Errorf("expected identical requests to share 1 call, got %d", src.callCount())
+	}
+}
+
+func TestProviderSharedCallOutlivesCancelledRequest(t *testing.T) {
+	src := &source{price: decimal.RequireFromString("0.0000625"), release: make(chan struct{})}
+	provider, _ := newTestProvider(t, src, Config{})
+
+	// The first request starts the call and gives up before the API answers
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := provider.GetRates(ctx, idrx("500000", "137"))
+		first <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := provider.GetRates(context.Background(), idrx("500000", "137"))
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancelled request to fail with its context, got %v", err)
+	}
+	close(src.release)
+	if err := <-second; err != nil {
+		t.Errorf("expected the waiting request to get the shared quote, got %v", err)
+	}
+	if src.callCount() != 1 {
+		t.Errorf("expected 1 shared call, got %d", src.callCount())
+	}
+}
+
+func TestRefreshDropsIdleQuotes(t *testing.T) {
+	src := &source{price: decimal.RequireFromString("0.0000625")}
+	provider, now := newTestProvider(t, src, Config{TTL: time.Minute, IdleTimeout: 5 * time.Minute})
+	ctx := context.Background()
+
+	_, _ = provider.GetRates(ctx, idrx("100000", "137"))
+	_, _ = provider.GetRates(ctx, idrx("100", "137"))
+
+	*now = now.Add(3 * time.Minute)
+	_, _ = provider.GetRates(ctx, idrx("100", "137")) // expired, refetched
+	*now = now.Add(3 * time.Minute)
+
+	provider.Refresh(ctx)
+	if src.callCount() != 4 {
+		t.Errorf("expected only the quote in use to be refreshed, got %d calls", src.callCount())
+	}
+
+	// The refreshed quote is served without another call; the idle one was dropped
+	_, _ = provider.GetRates(ctx, idrx("100", "137"))
+	_, _ = provider.GetRates(ctx, idrx("100000", "137"))
+	if src.callCount() != 5 {
+		t.Errorf("expected the idle quote to be dropped, got %d calls", src.callCount())
+	}
+}
+
+func TestSubscriptionEmitsPriceMoves(t *testing.T) {
+	src := &source{price: decimal.RequireFromString("0.00006250")}
+	provider, _ := newTestProvider(t, src, Config{})
+	ctx := context.Background()
+
+	sub, err := provider.Subscribe(ctx, idrx("100000", "137"), decimal.RequireFromString("0.001"))
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if first := <-sub.Rates(); first.BuyAmount != "6.250000" {
+		t.Errorf("expected the current quote first, got %+v", first)
+	}
+
+	src.setPrice("0.00006253") // 0.048%: below the threshold
+	provider.Refresh(ctx)
+	select {
+	case response := <-sub.Rates():
+		t.Fatalf("unexpected quote for a small move: %+v", response)
+	default:
+	}
+
+	src.setPrice("0.00006240") // 0.16% from the last emitted price
+	provider.Refresh(ctx)
+	select {
+	case response := <-sub.Rates():
+		if response.Price != "0.0000624" {
+			t.Errorf("expected the moved price, got %+v", response)
+		}
+	default:
+		t.Fatal("expected a quote for a move beyond the threshold")
+	}
+
+	sub.Unsubscribe()
+	if _, open := <-sub.Rates(); open {
+		t.Error("expected the channel to be closed")
+	}
+	sub.Unsubscribe()
+}
+
+func TestSubscriptionEndsWithContext(t *testing.T) {
+	src := &source{price: decimal.RequireFromString("0.00006250")}
+	provider, _ := newTestProvider(t, src, Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, err := provider.Subscribe(ctx, idrx("100000", "137"), decimal.Zero)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	<-sub.Rates()
+
+	cancel()
+	select {
+	case _, open := <-sub.Rates():
+		if open {
+			t.Error("expected no quote after the context ended")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the channel to be closed when the context ends")
+	}
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if len(provider.subscriptions) != 0 {
+		t.Errorf("expected the subscription removed, got %d", len(provider.subscriptions))
+	}
+}
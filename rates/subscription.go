@@ -0,0 +1,114 @@
+package rates
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/models"
+)
+
+// Subscription delivers quotes for one request whenever the price moves by more than its
+// threshold. It is fed by the provider's refreshes, so Run must be running for it to see
+// price moves.
+type Subscription struct {
+	provider  *Provider
+	key       key
+	amount    decimal.Decimal
+	threshold decimal.Decimal
+	last      decimal.Decimal
+	rates     chan *models.RatesResponse
+	done      chan struct{} // Closed by Unsubscribe
+	closed    bool
+}
+
+// Subscribe emits the current quote for req and then every quote whose price differs from the
+// last one emitted by more than threshold, a fraction of the price (0.001 is 0.1%). A zero
+// threshold emits every price change. If the receiver falls behind, older quotes are dropped
+// in favour of the latest. The subscription ends when ctx is cancelled or Unsubscribe is called.
+func (p *Provider) Subscribe(ctx context.Context, req *models.RatesRequest, threshold decimal.Decimal) (*Subscription, error) {
+	k, amount, err := p.parse(req)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &Subscription{
+		provider:  p,
+		key:       k,
+		amount:    amount,
+		threshold: threshold.Abs(),
+		rates:     make(chan *models.RatesResponse, 1),
+		done:      make(chan struct{}),
+	}
+
+	p.mu.Lock()
+	p.subscriptions[sub] = struct{}{}
+	p.mu.Unlock()
+
+	response, err := p.GetRates(ctx, req)
+	if err != nil {
+		sub.Unsubscribe()
+		return nil, err
+	}
+
+	p.mu.Lock()
+	sub.offer(response)
+	p.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			sub.Unsubscribe()
+		case <-sub.done:
+		}
+	}()
+	return sub, nil
+}
+
+// Rates returns the channel of quotes; it is closed when the subscription ends
+func (s *Subscription) Rates() <-chan *models.RatesResponse {
+	return s.rates
+}
+
+// Unsubscribe stops the subscription and closes its channel
+func (s *Subscription) Unsubscribe() {
+	s.provider.mu.Lock()
+	defer s.provider.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	delete(s.provider.subscriptions, s)
+	close(s.rates)
+	close(s.done)
+}
+
+// offer emits a quote if its price moved beyond the threshold. The provider's lock is held.
+func (s *Subscription) offer(response *models.RatesResponse) {
+	if s.closed {
+		return
+	}
+	price, err := decimal.NewFromString(response.Price)
+	if err != nil {
+		return
+	}
+	if !s.last.IsZero() {
+		change := price.Sub(s.last).Abs()
+		if change.IsZero() || change.Div(s.last).LessThanOrEqual(s.threshold) {
+			return
+		}
+	}
+	s.last = price
+
+	select {
+	case s.rates <- response:
+	default:
+		// Replace the quote the receiver has not taken yet
+		select {
+		case <-s.rates:
+		default:
+		}
+		s.rates <- response
+	}
+}
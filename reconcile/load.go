@@ -0,0 +1,249 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/contracts"
+	"github.com/widnyana/idrx-go/models"
+)
+
+// maxLogRange is the largest block range requested in a single eth_getLogs call
+const maxLogRange = 2000
+
+// HistorySource provides the REST transaction history; *idrx.TransactionService implements it
+type HistorySource interface {
+	GetTransactionHistory(ctx context.Context, req *models.TransactionHistoryRequest) (*models.TransactionHistoryResponse, error)
+}
+
+// Run loads both sides for the period and reconciles them. Both sides are loaded with the
+// tolerance as margin, so entries just across the period's edges can still be matched.
+func Run(ctx context.Context, history HistorySource, client *blockchain.Client, config Config) (*Report, error) {
+	config = config.withDefaults()
+	if config.Start.IsZero() || config.End.IsZero() || !config.Start.Before(config.End) {
+		return nil, errors.New("reconciliation needs a start before its end")
+	}
+
+	if len(config.Chains) == 0 {
+		for _, name := range client.EnabledNetworks() {
+			if networkConfig, exists := client.Registry().Get(name); exists {
+				config.Chains = append(config.Chains, networkConfig.ChainID)
+			}
+		}
+		sort.Slice(config.Chains, func(i, j int) bool { return config.Chains[i] < config.Chains[j] })
+	}
+
+	from, to := config.Start.Add(-config.Tolerance), config.End.Add(config.Tolerance)
+
+	transactions, err := LoadTransactions(ctx, history, from, to, config.Types)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, chainID := range config.Chains {
+		chainEvents, err := LoadEvents(ctx, client, chainID, from, to, config.Wallets)
+		if err != nil {
+			return nil, fmt.Errorf("chain %d: %w", chainID, err)
+		}
+		events = append(events, chainEvents...)
+	}
+
+	return Reconcile(transactions, events, config), nil
+}
+
+// LoadTransactions loads the completed transactions of the given types created between start
+// and end
+func LoadTransactions(
+	ctx context.Context,
+	history HistorySource,
+	start, end time.Time,
+	types []models.TransactionType,
+) ([]models.Transaction, error) {
+	if len(types) == 0 {
+		types = DefaultTypes
+	}
+	completed := models.TransactionStatusCompleted
+
+	var transactions []models.Transaction
+	for _, transactionType := range types {
+		for page := 1; ; page++ {
+			response, err := history.GetTransactionHistory(ctx, &models.TransactionHistoryRequest{
+				TransactionType: transactionType,
+				Page:            page,
+				Take:            100,
+				Status:          &completed,
+				StartDate:       &start,
+				EndDate:         &end,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to load %s transactions: %w", transactionType, err)
+			}
+
+			transactions = append(transactions, response.Data...)
+			if len(response.Data) == 0 || page >= response.Metadata.TotalPages {
+				break
+			}
+		}
+	}
+	return transactions, nil
+}
+
+// LoadEvents loads the mints, redemption burns and bridge events of a chain between start
+// and end, with block timestamps. Transfers other than mints are left out; burns are covered
+// by their BurnWithAccountNumber and BurnBridge events.
+//
+// With wallets given, only their events are loaded. Mints are filtered by the node on the
+// indexed recipient; the burn and bridge events do not index the user, so they are fetched
+// for every wallet and filtered here.
+func LoadEvents(
+	ctx context.Context,
+	client *blockchain.Client,
+	chainID uint64,
+	start, end time.Time,
+	wallets []common.Address,
+) ([]Event, error) {
+	networkConfig, _, exists := client.Registry().GetByChainID(chainID)
+	if !exists {
+		return nil, fmt.Errorf("chain ID %d not supported", chainID)
+	}
+	backend, err := client.GetBackend(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromBlock, err := client.BlockAtTime(ctx, chainID, start)
+	if err != nil {
+		return nil, err
+	}
+	toBlock, err := client.BlockAtTime(ctx, chainID, end)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse IDRX ABI: %w", err)
+	}
+	topic := func(eventType blockchain.EventType) common.Hash { return parsed.Events[string(eventType)].ID }
+	burnTopics := []common.Hash{
+		topic(blockchain.EventBurnWithAccountNumber),
+		topic(blockchain.EventBurnBridge),
+		topic(blockchain.EventMintBridge),
+	}
+
+	queries := [][][]common.Hash{{append([]common.Hash{topic(blockchain.EventTransfer)}, burnTopics...)}}
+	if len(wallets) > 0 {
+		recipients := make([]common.Hash, len(wallets))
+		for i, wallet := range wallets {
+			recipients[i] = common.BytesToHash(wallet.Bytes())
+		}
+		// Mints are transfers from the zero address
+		queries = [][][]common.Hash{
+			{{topic(blockchain.EventTransfer)}, {{}}, recipients},
+			{burnTopics},
+		}
+	}
+
+	blockTimes := make(map[uint64]time.Time)
+	var events []Event
+	for from := fromBlock.Uint64(); from <= toBlock.Uint64(); from += maxLogRange {
+		to := min(from+maxLogRange-1, toBlock.Uint64())
+
+		var logs []types.Log
+		for _, topics := range queries {
+			found, err := backend.FilterLogs(ctx, ethereum.FilterQuery{
+				FromBlock: new(big.Int).SetUint64(from),
+				ToBlock:   new(big.Int).SetUint64(to),
+				Addresses: []common.Address{networkConfig.ContractAddress},
+				Topics:    topics,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch logs for blocks %d-%d: %w", from, to, err)
+			}
+			logs = append(logs, found...)
+		}
+		sort.SliceStable(logs, func(i, j int) bool {
+			if logs[i].BlockNumber != logs[j].BlockNumber {
+				return logs[i].BlockNumber < logs[j].BlockNumber
+			}
+			return logs[i].Index < logs[j].Index
+		})
+
+		for _, log := range logs {
+			decoded, err := client.DecodeEvent(chainID, log)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode log %s:%d: %w", log.TxHash.Hex(), log.Index, err)
+			}
+			event, ok := fromChainEvent(decoded)
+			if !ok || len(ownEvents([]Event{event}, wallets)) == 0 {
+				continue
+			}
+
+			blockTime, cached := blockTimes[log.BlockNumber]
+			if !cached {
+				header, err := backend.HeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber))
+				if err != nil {
+					return nil, fmt.Errorf("failed to get block %d: %w", log.BlockNumber, err)
+				}
+				blockTime = time.Unix(int64(header.Time), 0).UTC()
+				blockTimes[log.BlockNumber] = blockTime
+			}
+			event.Time = blockTime
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// fromChainEvent converts a decoded contract event, reporting false for events that do not
+// correspond to a REST transaction
+func fromChainEvent(decoded *blockchain.Event) (Event, bool) {
+	event := Event{
+		Type:        decoded.Type,
+		ChainID:     decoded.ChainID,
+		TxHash:      decoded.Log.TxHash.Hex(),
+		LogIndex:    decoded.Log.Index,
+		BlockNumber: decoded.Log.BlockNumber,
+	}
+
+	switch data := decoded.Data.(type) {
+	case *models.TransferEvent:
+		if data.From != (common.Address{}) {
+			return Event{}, false
+		}
+		event.Kind = models.TransactionTypeMint
+		event.Account = data.To.Hex()
+		event.Amount = data.Value
+	case *models.BurnWithAccountNumberEvent:
+		event.Kind = models.TransactionTypeBurn
+		event.Account = data.User.Hex()
+		event.Amount = data.Amount
+	case *models.BurnBridgeEvent:
+		event.Kind = models.TransactionTypeBridge
+		event.Account = data.User.Hex()
+		event.Amount = data.Amount
+		event.AmountAfterCut = data.AmountAfterCut
+		event.PeerChainID = data.ToChain
+		event.BridgeNonce = data.BridgeNonce.String()
+	case *models.MintBridgeEvent:
+		event.Kind = models.TransactionTypeBridge
+		event.Account = data.User.Hex()
+		event.Amount = data.Amount
+		event.AmountAfterCut = data.AmountAfterCut
+		event.PeerChainID = data.FromChain
+		event.BridgeNonce = data.FromBridgeNonce.String()
+	default:
+		return Event{}, false
+	}
+	return event, true
+}
@@ -0,0 +1,110 @@
+package reconcile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/widnyana/idrx-go/models"
+)
+
+// Result column values in CSV output
+const (
+	ResultMatched         = "matched"
+	ResultMismatched      = "mismatched"
+	ResultMissingOnChain  = "missing_on_chain"
+	ResultMissingOffChain = "missing_off_chain"
+)
+
+// csvHeader lists the CSV columns: the result, then the REST transaction, then the event
+var csvHeader = []string{
+	"result", "reason", "matched_by",
+	"transaction_id", "transaction_type", "transaction_chain_id", "transaction_tx_hash",
+	"transaction_amount", "transaction_wallet", "transaction_created_at",
+	"event", "event_chain_id", "event_tx_hash", "event_log_index", "event_block",
+	"event_amount", "event_account", "event_time",
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes the report as CSV with one row per event; a bridge matched with both legs
+// takes two rows, and a missing transaction one row with empty event columns
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	write := func(result, reason, by string, transaction *models.Transaction, event *Event) error {
+		row := append([]string{result, reason, by}, transactionColumns(transaction)...)
+		return writer.Write(append(row, eventColumns(event)...))
+	}
+
+	for i := range r.Matched {
+		match := &r.Matched[i]
+		for j := range match.Events {
+			if err := write(ResultMatched, "", match.By, &match.Transaction, &match.Events[j]); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range r.Mismatched {
+		mismatch := &r.Mismatched[i]
+		for j := range mismatch.Events {
+			if err := write(ResultMismatched, mismatch.Reason, mismatch.By, &mismatch.Transaction, &mismatch.Events[j]); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range r.MissingOnChain {
+		if err := write(ResultMissingOnChain, "", "", &r.MissingOnChain[i], nil); err != nil {
+			return err
+		}
+	}
+	for i := range r.MissingOffChain {
+		if err := write(ResultMissingOffChain, "", "", nil, &r.MissingOffChain[i]); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func transactionColumns(transaction *models.Transaction) []string {
+	if transaction == nil {
+		return make([]string, 7)
+	}
+	return []string{
+		transaction.ID,
+		string(transaction.Type),
+		transaction.ChainID,
+		transaction.TxHash,
+		transaction.Amount,
+		transaction.WalletAddress,
+		transaction.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func eventColumns(event *Event) []string {
+	if event == nil {
+		return make([]string, 8)
+	}
+	return []string{
+		string(event.Type),
+		strconv.FormatUint(event.ChainID, 10),
+		event.TxHash,
+		strconv.FormatUint(uint64(event.LogIndex), 10),
+		strconv.FormatUint(event.BlockNumber, 10),
+		event.Amount.String(),
+		event.Account,
+		event.Time.UTC().Format(time.RFC3339),
+	}
+}
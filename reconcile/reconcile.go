@@ -0,0 +1,382 @@
+// Package reconcile matches completed mints, redemptions and bridges in the REST transaction
+// history with IDRX contract events, so that every entry on one side can be accounted for on
+// the other.
+package reconcile
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/models"
+)
+
+// Ways a transaction was matched with its event
+const (
+	ByTxHash    = "txHash"    // Same transaction hash
+	ByHeuristic = "heuristic" // Same kind, chain, amount and wallet, closest in time
+)
+
+// DefaultTypes are the transaction types reconciled when Config.Types is empty
+var DefaultTypes = []models.TransactionType{
+	models.TransactionTypeMint,
+	models.TransactionTypeBurn,
+	models.TransactionTypeBridge,
+}
+
+// Config controls a reconciliation
+type Config struct {
+	Start     time.Time                // Start of the period, inclusive
+	End       time.Time                // End of the period, exclusive
+	Chains    []uint64                 // Chains whose events were loaded (defaults to every enabled network in Run)
+	Types     []models.TransactionType // Transaction types to reconcile (defaults to DefaultTypes)
+	Tolerance time.Duration            // Largest time difference for a heuristic match (defaults to 1 hour)
+	Wallets   []common.Address         // Wallets whose events are reconciled (defaults to every wallet)
+}
+
+// withDefaults fills unset config values
+func (c Config) withDefaults() Config {
+	if len(c.Types) == 0 {
+		c.Types = DefaultTypes
+	}
+	if c.Tolerance <= 0 {
+		c.Tolerance = time.Hour
+	}
+	return c
+}
+
+// Event is an IDRX contract event that corresponds to a REST transaction type
+type Event struct {
+	Type           blockchain.EventType   `json:"event"`
+	Kind           models.TransactionType `json:"kind"`
+	ChainID        uint64                 `json:"chainId"`
+	TxHash         string                 `json:"txHash"`
+	LogIndex       uint                   `json:"logIndex"`
+	BlockNumber    uint64                 `json:"blockNumber"`
+	Time           time.Time              `json:"time"`
+	Account        string                 `json:"account"` // Wallet minted to or burned from
+	Amount         decimal.Decimal        `json:"amount"`
+	AmountAfterCut decimal.Decimal        `json:"amountAfterCut"` // Bridge amount net of the platform fee
+	PeerChainID    uint64                 `json:"peerChainId,omitempty"`
+	BridgeNonce    string                 `json:"bridgeNonce,omitempty"`
+}
+
+// Match is a transaction with its events: one event, or both legs of a bridge
+type Match struct {
+	Transaction models.Transaction `json:"transaction"`
+	Events      []Event            `json:"events"`
+	By          string             `json:"by"`
+}
+
+// Mismatch is a transaction whose event was found but disagrees with it
+type Mismatch struct {
+	Transaction models.Transaction `json:"transaction"`
+	Events      []Event            `json:"events"`
+	By          string             `json:"by"`
+	Reason      string             `json:"reason"`
+}
+
+// Report is the outcome of a reconciliation
+type Report struct {
+	Start           time.Time            `json:"start"`
+	End             time.Time            `json:"end"`
+	Matched         []Match              `json:"matched"`
+	Mismatched      []Mismatch           `json:"mismatched"`
+	MissingOnChain  []models.Transaction `json:"missingOnChain"`  // Completed transactions without an event
+	MissingOffChain []Event              `json:"missingOffChain"` // Events without a completed transaction
+}
+
+// Balanced reports whether every transaction and event was matched
+func (r *Report) Balanced() bool {
+	return len(r.Mismatched) == 0 && len(r.MissingOnChain) == 0 && len(r.MissingOffChain) == 0
+}
+
+// entry is a primary event with the destination leg of a bridge attached
+type entry struct {
+	event Event
+	leg   *Event
+	used  bool
+}
+
+// Reconcile matches completed transactions created in the period with events. Events are
+// matched by transaction hash first; the remaining ones are matched by kind, chain, amount
+// and wallet to the transaction closest in time within the tolerance.
+//
+// Transactions and events outside the period are only used as match candidates, so both
+// sides can be loaded with some margin to avoid false gaps at the edges.
+func Reconcile(transactions []models.Transaction, events []Event, config Config) *Report {
+	config = config.withDefaults()
+	report := &Report{
+		Start:           config.Start,
+		End:             config.End,
+		Matched:         []Match{},
+		Mismatched:      []Mismatch{},
+		MissingOnChain:  []models.Transaction{},
+		MissingOffChain: []Event{},
+	}
+
+	types := make(map[models.TransactionType]bool, len(config.Types))
+	for _, transactionType := range config.Types {
+		types[transactionType] = true
+	}
+	var pending []models.Transaction
+	for _, transaction := range transactions {
+		if transaction.Status == models.TransactionStatusCompleted && types[transaction.Type] {
+			pending = append(pending, transaction)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].CreatedAt.Before(pending[j].CreatedAt) })
+
+	entries := buildEntries(ownEvents(events, config.Wallets), types)
+	byHash := make(map[string][]*entry)
+	for _, e := range entries {
+		hash := strings.ToLower(e.event.TxHash)
+		byHash[hash] = append(byHash[hash], e)
+	}
+
+	// Pass 1: transaction hash
+	var unmatched []models.Transaction
+	for _, transaction := range pending {
+		e := hashCandidate(byHash[strings.ToLower(transaction.TxHash)], &transaction)
+		if e == nil {
+			unmatched = append(unmatched, transaction)
+			continue
+		}
+		e.used = true
+		report.add(transaction, e, ByTxHash, config)
+	}
+
+	// Pass 2: heuristics
+	for _, transaction := range unmatched {
+		var best *entry
+		var bestDistance time.Duration
+		for _, e := range entries {
+			if e.used || !similar(&transaction, &e.event) {
+				continue
+			}
+			distance := timeDistance(&transaction, e.event.Time)
+			if distance > config.Tolerance {
+				continue
+			}
+			if best == nil || distance < bestDistance {
+				best, bestDistance = e, distance
+			}
+		}
+
+		if best == nil {
+			if inPeriod(transaction.CreatedAt, config) {
+				report.MissingOnChain = append(report.MissingOnChain, transaction)
+			}
+			continue
+		}
+		best.used = true
+		report.add(transaction, best, ByHeuristic, config)
+	}
+
+	for _, e := range entries {
+		if e.used || !inPeriod(e.event.Time, config) {
+			continue
+		}
+		report.MissingOffChain = append(report.MissingOffChain, e.event)
+		if e.leg != nil {
+			report.MissingOffChain = append(report.MissingOffChain, *e.leg)
+		}
+	}
+
+	return report
+}
+
+// add records a transaction with its event, as a match or a mismatch. Pairs entirely
+// outside the period are left out.
+func (r *Report) add(transaction models.Transaction, e *entry, by string, config Config) {
+	if !inPeriod(transaction.CreatedAt, config) && !inPeriod(e.event.Time, config) {
+		return
+	}
+
+	events := []Event{e.event}
+	if e.leg != nil {
+		events = append(events, *e.leg)
+	}
+
+	var reasons []string
+	if e.event.Kind != transaction.Type {
+		reasons = append(reasons, fmt.Sprintf("%s transaction matches a %s event", transaction.Type, e.event.Type))
+	}
+	if chainID, ok := parseChainID(transaction.ChainID); ok && chainID != e.event.ChainID && (e.leg == nil || chainID != e.leg.ChainID) {
+		reasons = append(reasons, fmt.Sprintf("chain %d differs from event chain %d", chainID, e.event.ChainID))
+	}
+	if !amountMatches(transaction.Amount, &e.event) {
+		reasons = append(reasons, fmt.Sprintf("amount %s differs from on-chain %s", transaction.Amount, e.event.Amount))
+	}
+	if e.event.Type == blockchain.EventBurnBridge && e.leg == nil && loaded(e.event.PeerChainID, config) {
+		reasons = append(reasons, fmt.Sprintf("no MintBridge on chain %d for bridge nonce %s", e.event.PeerChainID, e.event.BridgeNonce))
+	}
+
+	if len(reasons) > 0 {
+		r.Mismatched = append(r.Mismatched, Mismatch{
+			Transaction: transaction,
+			Events:      events,
+			By:          by,
+			Reason:      strings.Join(reasons, "; "),
+		})
+		return
+	}
+	r.Matched = append(r.Matched, Match{Transaction: transaction, Events: events, By: by})
+}
+
+// buildEntries keeps events of the reconciled kinds, attaching each MintBridge to the
+// BurnBridge with the same source chain and nonce. A mint Transfer in the same transaction as
+// a MintBridge is the same mint and is dropped.
+func buildEntries(events []Event, types map[models.TransactionType]bool) []*entry {
+	bridgeMints := make(map[string]bool)
+	for _, event := range events {
+		if event.Type == blockchain.EventMintBridge {
+			bridgeMints[eventTxKey(event.ChainID, event.TxHash)] = true
+		}
+	}
+
+	var entries []*entry
+	burns := make(map[string]*entry)
+	for _, event := range events {
+		if !types[event.Kind] {
+			continue
+		}
+		if event.Type == blockchain.EventTransfer && bridgeMints[eventTxKey(event.ChainID, event.TxHash)] {
+			continue
+		}
+		if event.Type == blockchain.EventMintBridge {
+			continue
+		}
+		e := &entry{event: event}
+		entries = append(entries, e)
+		if event.Type == blockchain.EventBurnBridge {
+			burns[bridgeKey(event.ChainID, event.BridgeNonce)] = e
+		}
+	}
+
+	for _, event := range events {
+		if event.Type != blockchain.EventMintBridge || !types[event.Kind] {
+			continue
+		}
+		if burn := burns[bridgeKey(event.PeerChainID, event.BridgeNonce)]; burn != nil && burn.leg == nil {
+			leg := event
+			burn.leg = &leg
+			continue
+		}
+		entries = append(entries, &entry{event: event})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].event.Time.Before(entries[j].event.Time) })
+	return entries
+}
+
+// ownEvents keeps the events of the given wallets; with no wallets, every event is kept
+func ownEvents(events []Event, wallets []common.Address) []Event {
+	if len(wallets) == 0 {
+		return events
+	}
+	own := make(map[common.Address]bool, len(wallets))
+	for _, wallet := range wallets {
+		own[wallet] = true
+	}
+
+	var kept []Event
+	for _, event := range events {
+		if own[common.HexToAddress(event.Account)] {
+			kept = append(kept, event)
+		}
+	}
+	return kept
+}
+
+// hashCandidate picks the unused event with the transaction's hash, preferring one of the
+// same kind
+func hashCandidate(candidates []*entry, transaction *models.Transaction) *entry {
+	if transaction.TxHash == "" {
+		return nil
+	}
+	var fallback *entry
+	for _, e := range candidates {
+		if e.used {
+			continue
+		}
+		if e.event.Kind == transaction.Type {
+			return e
+		}
+		if fallback == nil {
+			fallback = e
+		}
+	}
+	return fallback
+}
+
+// similar reports whether an event could belong to a transaction without a hash match
+func similar(transaction *models.Transaction, event *Event) bool {
+	if event.Kind != transaction.Type || !amountMatches(transaction.Amount, event) {
+		return false
+	}
+	if chainID, ok := parseChainID(transaction.ChainID); ok && chainID != event.ChainID {
+		return false
+	}
+	return transaction.WalletAddress == "" || strings.EqualFold(transaction.WalletAddress, event.Account)
+}
+
+// amountMatches compares a REST amount with an event amount, or a bridge amount net of fees
+func amountMatches(amount string, event *Event) bool {
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return false
+	}
+	return value.Equal(event.Amount) || (event.Kind == models.TransactionTypeBridge && value.Equal(event.AmountAfterCut))
+}
+
+// timeDistance is the time between an event and the nearer of a transaction's creation and completion
+func timeDistance(transaction *models.Transaction, at time.Time) time.Duration {
+	distance := absDuration(at.Sub(transaction.CreatedAt))
+	if transaction.CompletedAt != nil {
+		distance = min(distance, absDuration(at.Sub(*transaction.CompletedAt)))
+	}
+	return distance
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// inPeriod reports whether a time falls within the reconciled period; a zero bound is open
+func inPeriod(t time.Time, config Config) bool {
+	return (config.Start.IsZero() || !t.Before(config.Start)) && (config.End.IsZero() || t.Before(config.End))
+}
+
+// loaded reports whether events of a chain were loaded
+func loaded(chainID uint64, config Config) bool {
+	for _, loadedChain := range config.Chains {
+		if loadedChain == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+// parseChainID parses a REST chain ID, which may be empty
+func parseChainID(chainID string) (uint64, bool) {
+	value, err := strconv.ParseUint(chainID, 10, 64)
+	return value, err == nil
+}
+
+func eventTxKey(chainID uint64, txHash string) string {
+	return fmt.Sprintf("%d:%s", chainID, strings.ToLower(txHash))
+}
+
+func bridgeKey(sourceChainID uint64, nonce string) string {
+	return fmt.Sprintf("%d:%s", sourceChainID, nonce)
+}
@@ -0,0 +1,292 @@
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+
+	"github.com/widnyana/idrx-go/blockchain"
+	"github.com/widnyana/idrx-go/contracts"
+	"github.com/widnyana/idrx-go/internal/backendtest"
+	"github.com/widnyana/idrx-go/models"
+)
+
+var (
+	wallet      = common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	periodStart = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd   = periodStart.Add(24 * time.Hour)
+)
+
+func transaction(id string, kind models.TransactionType, amount string, txHash string, at time.Time) models.Transaction {
+	return models.Transaction{
+		ID:        id,
+		Type:      kind,
+		Status:    models.TransactionStatusCompleted,
+		Amount:    amount,
+		ChainID:   "31337",
+		TxHash:    txHash,
+		CreatedAt: at,
+	}
+}
+
+func event(eventType blockchain.EventType, kind models.TransactionType, amount string, txHash string, at time.Time) Event {
+	return Event{
+		Type:    eventType,
+		Kind:    kind,
+		ChainID: backendtest.ChainID,
+		TxHash:  txHash,
+		Time:    at,
+		Account: wallet.Hex(),
+		Amount:  decimal.RequireFromString(amount),
+	}
+}
+
+func TestReconcileClassifiesBothSides(t *testing.T) {
+	noon := periodStart.Add(12 * time.Hour)
+	transactions := []models.Transaction{
+		transaction("hash", models.TransactionTypeMint, "1000", "0xAAA", noon),
+		transaction("heuristic", models.TransactionTypeBurn, "250.5", "", noon.Add(time.Hour)),
+		transaction("amount", models.TransactionTypeMint, "99", "0xccc", noon.Add(2*time.Hour)),
+		transaction("missing", models.TransactionTypeBurn, "42", "", noon.Add(3*time.Hour)),
+		transaction("too-late", models.TransactionTypeBurn, "77", "", noon.Add(4*time.Hour)),
+		{ID: "pending", Type: models.TransactionTypeMint, Status: models.TransactionStatusPending, Amount: "5", CreatedAt: noon},
+	}
+	events := []Event{
+		event(blockchain.EventTransfer, models.TransactionTypeMint, "1000", "0xaaa", noon.Add(time.Minute)),
+		event(blockchain.EventBurnWithAccountNumber, models.TransactionTypeBurn, "250.5", "0xbbb", noon.Add(50*time.Minute)),
+		event(blockchain.EventTransfer, models.TransactionTypeMint, "100", "0xccc", noon.Add(2*time.Hour)),
+		event(blockchain.EventBurnWithAccountNumber, models.TransactionTypeBurn, "77", "0xddd", noon.Add(6*time.Hour)),
+		// Outside the period and unmatched: loaded as margin only
+		event(blockchain.EventTransfer, models.TransactionTypeMint, "5", "0xeee", periodEnd.Add(time.Minute)),
+	}
+
+	report := Reconcile(transactions, events, Config{Start: periodStart, End: periodEnd, Chains: []uint64{backendtest.ChainID}})
+
+	if len(report.Matched) != 2 || report.Matched[0].Transaction.ID != "hash" || report.Matched[0].By != ByTxHash ||
+		report.Matched[1].Transaction.ID != "heuristic" || report.Matched[1].By != ByHeuristic {
+		t.Errorf("unexpected matches: %+v", report.Matched)
+	}
+	if len(report.Mismatched) != 1 || report.Mismatched[0].Transaction.ID != "amount" ||
+		!strings.Contains(report.Mismatched[0].Reason, "amount 99 differs from on-chain 100") {
+		t.Errorf("unexpected mismatches: %+v", report.Mismatched)
+	}
+	if len(report.MissingOnChain) != 2 || report.MissingOnChain[0].ID != "missing" || report.MissingOnChain[1].ID != "too-late" {
+		t.Errorf("unexpected transactions missing on chain: %+v", report.MissingOnChain)
+	}
+	if len(report.MissingOffChain) != 1 || report.MissingOffChain[0].TxHash != "0xddd" {
+		t.Errorf("unexpected events missing off chain: %+v", report.MissingOffChain)
+	}
+	if report.Balanced() {
+		t.Error("expected an unbalanced report")
+	}
+}
+
+func TestReconcilePairsBridgeLegs(t *testing.T) {
+	noon := periodStart.Add(12 * time.Hour)
+	burn := event(blockchain.EventBurnBridge, models.TransactionTypeBridge, "500", "0x01", noon)
+	burn.AmountAfterCut = decimal.RequireFromString("495")
+	burn.PeerChainID = 8453
+	burn.BridgeNonce = "7"
+
+	mint := event(blockchain.EventMintBridge, models.TransactionTypeBridge, "500", "0x02", noon.Add(time.Minute))
+	mint.ChainID = 8453
+	mint.AmountAfterCut = burn.AmountAfterCut
+	mint.PeerChainID = backendtest.ChainID
+	mint.BridgeNonce = "7"
+	// The MintBridge transaction also emits a mint Transfer, which must not count separately
+	mintTransfer := event(blockchain.EventTransfer, models.TransactionTypeMint, "495", "0x02", mint.Time)
+	mintTransfer.ChainID = 8453
+
+	orphan := burn
+	orphan.TxHash, orphan.BridgeNonce = "0x03", "8"
+
+	transactions := []models.Transaction{
+		transaction("bridge", models.TransactionTypeBridge, "495", "0x01", noon),
+		transaction("orphan", models.TransactionTypeBridge, "500", "0x03", noon),
+	}
+	config := Config{Start: periodStart, End: periodEnd, Chains: []uint64{backendtest.ChainID, 8453}}
+	report := Reconcile(transactions, []Event{burn, mint, mintTransfer, orphan}, config)
+
+	if len(report.Matched) != 1 || len(report.Matched[0].Events) != 2 || report.Matched[0].Events[1].TxHash != "0x02" {
+		t.Fatalf("expected the bridge matched with both legs, got %+v", report.Matched)
+	}
+	if len(report.Mismatched) != 1 || !strings.Contains(report.Mismatched[0].Reason, "no MintBridge on chain 8453") {
+		t.Errorf("expected the orphan burn to lack its mint leg, got %+v", report.Mismatched)
+	}
+	if len(report.MissingOffChain) != 0 {
+		t.Errorf("expected no unmatched events, got %+v", report.MissingOffChain)
+	}
+}
+
+func TestReportOutput(t *testing.T) {
+	noon := periodStart.Add(12 * time.Hour)
+	report := Reconcile(
+		[]models.Transaction{
+			transaction("t1", models.TransactionTypeMint, "10", "0x01", noon),
+			transaction("t2", models.TransactionTypeMint, "20", "", noon),
+		},
+		[]Event{event(blockchain.EventTransfer, models.TransactionTypeMint, "10", "0x01", noon)},
+		Config{Start: periodStart, End: periodEnd},
+	)
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 3 || rows[1][0] != ResultMatched || rows[1][3] != "t1" || rows[1][15] != "10" ||
+		rows[2][0] != ResultMissingOnChain || rows[2][3] != "t2" || rows[2][10] != "" {
+		t.Errorf("unexpected CSV rows: %v", rows)
+	}
+
+	buf.Reset()
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(decoded.Matched) != 1 || !decoded.Matched[0].Events[0].Amount.Equal(decimal.NewFromInt(10)) || len(decoded.MissingOnChain) != 1 {
+		t.Errorf("unexpected JSON report: %s", buf.String())
+	}
+}
+
+// history serves completed transactions of each type on a single page
+type history map[models.TransactionType][]models.Transaction
+
+func (h history) GetTransactionHistory(_ context.Context, req *models.TransactionHistoryRequest) (*models.TransactionHistoryResponse, error) {
+	return &models.TransactionHistoryResponse{
+		Data:     h[req.TransactionType],
+		Metadata: models.ListMetadata{Page: req.Page, Take: req.Take, TotalPages: 1},
+	}, nil
+}
+
+// chain has one block per minute from periodStart and serves fixed logs
+type chain struct {
+	backendtest.Chain
+
+	logs []types.Log
+}
+
+func (c *chain) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	block := c.Head
+	if number != nil {
+		block = number.Uint64()
+	}
+	return &types.Header{Number: new(big.Int).SetUint64(block), Time: uint64(periodStart.Unix()) + block*60}, nil
+}
+
+func (c *chain) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	for _, log := range c.logs {
+		if log.BlockNumber >= query.FromBlock.Uint64() && log.BlockNumber <= query.ToBlock.Uint64() && matchesTopics(log, query.Topics) {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+// matchesTopics applies a log filter's topic positions the way a node does
+func matchesTopics(log types.Log, topics [][]common.Hash) bool {
+	for i, allowed := range topics {
+		if len(allowed) == 0 {
+			continue
+		}
+		if i >= len(log.Topics) {
+			return false
+		}
+		found := false
+		for _, topic := range allowed {
+			found = found || topic == log.Topics[i]
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRunLoadsBothSides(t *testing.T) {
+	parsed, err := contracts.IDRXMetaData.GetAbi()
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+
+	mintData, _ := parsed.Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(150000))
+	burnData, err := parsed.Events["BurnWithAccountNumber"].Inputs.Pack(wallet, big.NewInt(2550), "hashed")
+	if err != nil {
+		t.Fatalf("failed to pack burn: %v", err)
+	}
+	foreign := common.HexToAddress("0x00000000000000000000000000000000000000f0")
+	foreignBurnData, err := parsed.Events["BurnWithAccountNumber"].Inputs.Pack(foreign, big.NewInt(2550), "hashed")
+	if err != nil {
+		t.Fatalf("failed to pack burn: %v", err)
+	}
+	backend := &chain{Chain: backendtest.Chain{Head: 24 * 60}, logs: []types.Log{
+		{
+			Address:     backendtest.Contract,
+			Topics:      []common.Hash{parsed.Events["Transfer"].ID, {}, common.BytesToHash(wallet.Bytes())},
+			Data:        mintData,
+			BlockNumber: 600,
+			TxHash:      common.Hash{0x01},
+		},
+		{
+			Address:     backendtest.Contract,
+			Topics:      []common.Hash{parsed.Events["BurnWithAccountNumber"].ID},
+			Data:        burnData,
+			BlockNumber: 700,
+			TxHash:      common.Hash{0x02},
+		},
+		// Other holders' mint and redemption must not show up as missing off chain
+		{
+			Address:     backendtest.Contract,
+			Topics:      []common.Hash{parsed.Events["Transfer"].ID, {}, common.BytesToHash(foreign.Bytes())},
+			Data:        mintData,
+			BlockNumber: 800,
+			TxHash:      common.Hash{0x03},
+		},
+		{
+			Address:     backendtest.Contract,
+			Topics:      []common.Hash{parsed.Events["BurnWithAccountNumber"].ID},
+			Data:        foreignBurnData,
+			BlockNumber: 900,
+			TxHash:      common.Hash{0x04},
+		},
+	}}
+
+	client := backendtest.NewClient(t, backend)
+
+	source := history{
+		models.TransactionTypeMint: {transaction("mint", models.TransactionTypeMint, "1500", common.Hash{0x01}.Hex(), periodStart.Add(600*time.Minute))},
+		models.TransactionTypeBurn: {transaction("redeem", models.TransactionTypeBurn, "25.50", "", periodStart.Add(710*time.Minute))},
+	}
+
+	report, err := Run(context.Background(), source, client, Config{
+		Start:   periodStart.Add(time.Hour),
+		End:     periodEnd.Add(-time.Hour),
+		Wallets: []common.Address{wallet},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !report.Balanced() || len(report.Matched) != 2 {
+		t.Fatalf("expected both transactions matched, got %+v", report)
+	}
+	if report.Matched[1].By != ByHeuristic || !report.Matched[1].Events[0].Time.Equal(periodStart.Add(700*time.Minute)) {
+		t.Errorf("unexpected redemption match: %+v", report.Matched[1])
+	}
+}
@@ -40,6 +40,13 @@ func (s *TransactionService) RedeemRequest(ctx context.Context, req *models.Rede
 		return nil, fmt.Errorf("redeem request cannot be nil")
 	}
 
+	// Check the bank details and fill in the bank name from the bank directory
+	if s.client.Banks != nil {
+		if err := s.client.Banks.PrepareRedeem(ctx, req); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
 	// Validate required fields
 	if err := s.validateRedeemRequest(req); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)